@@ -3,10 +3,13 @@ package aviatrix
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
 )
@@ -14,11 +17,43 @@ import (
 // Config contains the configuration for the Aviatrix provider
 // (Username, Password, and Controller IP)
 type Config struct {
-	Username     string
-	Password     string
-	ControllerIP string
-	VerifyCert   bool
-	PathToCACert string
+	Username      string
+	Password      string
+	ControllerIP  string
+	VerifyCert    bool
+	PathToCACert  string
+	ProxyURL      string
+	TLSMinVersion string
+	APIRateLimit  float64
+	// SkipTLSVerify disables the verification of the TLS certificate of the controller. It is the only
+	// setting that does, VerifyCert only selects whether PathToCACert is used.
+	SkipTLSVerify bool
+	CACertFile    string
+
+	// HTTPClientTimeout bounds the time of each request to the controller, no limit if zero
+	HTTPClientTimeout time.Duration
+	// Transport are the connection settings of the transport to the controller
+	Transport goaviatrix.TransportSettings
+	// RetryBaseDelay and RetryMaxDelay configure the waits between retries of transient failures
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// CircuitBreaker configures when requests fail fast after repeated failures of the controller
+	CircuitBreaker goaviatrix.CircuitBreakerSettings
+	// DebugHTTP logs the redacted bodies of the requests to the controller and of their responses
+	DebugHTTP bool
+
+	DefaultDeviceHostOS string
+	DisableTagCache     bool
+	IgnoreTags          goaviatrix.IgnoreTagsConfig
+	TagPrefix           string
+}
+
+// tlsVersions maps the values accepted by the provider's tls_min_version to TLS versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
 }
 
 // Client gets the Aviatrix client to access the Controller
@@ -28,12 +63,70 @@ type Config struct {
 //    the aviatrix client (from goaviatrix)
 //    error (if any)
 func (c *Config) Client() (*goaviatrix.Client, error) {
+	tr, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := goaviatrix.NewClient(c.Username, c.Password, c.ControllerIP, &http.Client{Transport: tr, Timeout: c.HTTPClientTimeout})
+	if err == nil {
+		if c.APIRateLimit > 0 {
+			client.SetRateLimit(c.APIRateLimit)
+		}
+		if c.CircuitBreaker.Threshold > 0 {
+			client.SetCircuitBreaker(c.CircuitBreaker)
+		}
+		client.DefaultDeviceHostOS = c.DefaultDeviceHostOS
+		client.DisableTagCache = c.DisableTagCache
+		client.RetryBaseDelay = c.RetryBaseDelay
+		client.RetryMaxDelay = c.RetryMaxDelay
+		client.IgnoreTags = c.IgnoreTags
+		client.TagPrefix = c.TagPrefix
+		client.DebugHTTP = c.DebugHTTP
+	}
+
+	log.Printf("[INFO] Aviatrix Client configured for use")
+
+	if client == nil || err != nil {
+		log.Printf("[ERROR] unable to create client: %s", err)
+	}
+	return client, err
+}
+
+// transport returns the HTTP transport used to connect to the controller, with the proxy
+// and TLS settings of the provider configuration applied.
+func (c *Config) transport() (*http.Transport, error) {
+	if c.SkipTLSVerify && c.VerifyCert {
+		return nil, fmt.Errorf("skip_tls_verify and verify_ssl_certificate can not both be true")
+	}
+	if c.SkipTLSVerify {
+		log.Printf("[WARN] the TLS certificate of the controller %s will NOT be verified. Only use this for lab "+
+			"controllers with self-signed certificates, set skip_tls_verify to false to verify it", c.ControllerIP)
+	}
+
 	tr := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
+		Proxy: goaviatrix.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: !c.VerifyCert,
+			InsecureSkipVerify: c.SkipTLSVerify,
 		},
 	}
+	goaviatrix.ConfigureTransport(tr, c.Transport)
+
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url %q: %v", c.ProxyURL, err)
+		}
+		tr.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if c.TLSMinVersion != "" {
+		minVersion, ok := tlsVersions[c.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid tls_min_version %q", c.TLSMinVersion)
+		}
+		tr.TLSClientConfig.MinVersion = minVersion
+	}
 
 	if c.VerifyCert && c.PathToCACert != "" {
 		caCert, err := ioutil.ReadFile(c.PathToCACert)
@@ -45,12 +138,48 @@ func (c *Config) Client() (*goaviatrix.Client, error) {
 		tr.TLSClientConfig.RootCAs = caCertPool
 	}
 
-	client, err := goaviatrix.NewClient(c.Username, c.Password, c.ControllerIP, &http.Client{Transport: tr})
+	if c.CACertFile != "" {
+		if c.SkipTLSVerify {
+			return nil, fmt.Errorf("skip_tls_verify and ca_cert_file can not both be set")
+		}
+		caCertPool, err := loadCACertPool(c.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tr.TLSClientConfig.RootCAs = caCertPool
+	}
 
-	log.Printf("[INFO] Aviatrix Client configured for use")
+	return tr, nil
+}
 
-	if client == nil || err != nil {
-		log.Printf("[ERROR] unable to create client: %s", err)
+// loadCACertPool returns a pool of the certificates in the PEM bundle at path. It fails if the file can
+// not be read, contains a block that is not a valid certificate, or contains no certificate at all.
+func loadCACertPool(path string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read ca_cert_file: %v", err)
 	}
-	return client, err
+
+	caCertPool := x509.NewCertPool()
+	certs := 0
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("ca_cert_file %s contains a %q PEM block, only certificates are supported", path, block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificate %d of ca_cert_file %s: %v", certs+1, path, err)
+		}
+		caCertPool.AddCert(cert)
+		certs++
+	}
+	if certs == 0 {
+		return nil, fmt.Errorf("ca_cert_file %s does not contain any PEM encoded certificate", path)
+	}
+	return caCertPool, nil
 }
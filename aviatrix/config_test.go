@@ -0,0 +1,226 @@
+package aviatrix
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestConfigTransport(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         Config
+		wantProxy      string
+		wantMinVersion uint16
+		wantInsecure   bool
+		wantErr        bool
+	}{
+		{
+			"defaults",
+			Config{},
+			"",
+			0,
+			false,
+			false,
+		},
+		{
+			"skip tls verify",
+			Config{SkipTLSVerify: true},
+			"",
+			0,
+			true,
+			false,
+		},
+		{
+			"verify ssl certificate",
+			Config{VerifyCert: true},
+			"",
+			0,
+			false,
+			false,
+		},
+		{
+			"proxy url and tls min version",
+			Config{ProxyURL: "http://proxy.example.com:3128", TLSMinVersion: "1.2"},
+			"http://proxy.example.com:3128",
+			tls.VersionTLS12,
+			false,
+			false,
+		},
+		{
+			"skip tls verify with verify ssl certificate",
+			Config{SkipTLSVerify: true, VerifyCert: true},
+			"",
+			0,
+			false,
+			true,
+		},
+		{
+			"invalid tls min version",
+			Config{TLSMinVersion: "1.4"},
+			"",
+			0,
+			false,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tt.config.transport()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("transport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tr.TLSClientConfig.InsecureSkipVerify != tt.wantInsecure {
+				t.Errorf("transport() InsecureSkipVerify = %v, want %v", tr.TLSClientConfig.InsecureSkipVerify, tt.wantInsecure)
+			}
+			if tr.TLSClientConfig.MinVersion != tt.wantMinVersion {
+				t.Errorf("transport() TLS MinVersion = %v, want %v", tr.TLSClientConfig.MinVersion, tt.wantMinVersion)
+			}
+			if tt.wantProxy == "" {
+				return
+			}
+			req, _ := http.NewRequest("GET", "https://controller.example.com/v1/api", nil)
+			proxy, err := tr.Proxy(req)
+			if err != nil {
+				t.Fatalf("transport() proxy error: %v", err)
+			}
+			if proxy == nil || proxy.String() != tt.wantProxy {
+				t.Errorf("transport() proxy = %v, want %q", proxy, tt.wantProxy)
+			}
+		})
+	}
+}
+
+func TestConfigTransportVerifiesCertificate(t *testing.T) {
+	// the test server has a self-signed certificate
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"certificate verified", Config{}, true},
+		{"certificate verified with verify ssl certificate", Config{VerifyCert: true}, true},
+		{"skip tls verify", Config{SkipTLSVerify: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tt.config.transport()
+			if err != nil {
+				t.Fatalf("transport() unexpected error: %v", err)
+			}
+			resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("request to a controller with a self-signed certificate error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSkipTLSVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   bool
+	}{
+		{"defaults", map[string]interface{}{}, true},
+		{"verify ssl certificate", map[string]interface{}{"verify_ssl_certificate": true}, false},
+		{"ca cert file", map[string]interface{}{"ca_cert_file": "/path/to/bundle.pem"}, false},
+		{"skip tls verify", map[string]interface{}{"skip_tls_verify": true}, true},
+		{"skip tls verify false", map[string]interface{}{"skip_tls_verify": false}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, Provider().Schema, tt.config)
+			if got := skipTLSVerify(d); got != tt.want {
+				t.Errorf("skipTLSVerify() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigTransportCACertFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Internal CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	dir, err := ioutil.TempDir("", "ca-cert-file")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+		return path
+	}
+	bundle := writeFile("bundle.pem", append(append([]byte{}, certPEM...), certPEM...))
+	notPEM := writeFile("not-pem.txt", []byte("not a certificate"))
+	privateKey := writeFile("key.pem", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")}))
+	invalidCert := writeFile("invalid.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")}))
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"bundle", Config{CACertFile: bundle}, false},
+		{"missing file", Config{CACertFile: filepath.Join(dir, "missing.pem")}, true},
+		{"no pem", Config{CACertFile: notPEM}, true},
+		{"private key", Config{CACertFile: privateKey}, true},
+		{"invalid certificate", Config{CACertFile: invalidCert}, true},
+		{"with skip tls verify", Config{CACertFile: bundle, SkipTLSVerify: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tt.config.transport()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("transport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tr.TLSClientConfig.RootCAs == nil || tr.TLSClientConfig.InsecureSkipVerify {
+				t.Errorf("transport() does not verify the controller certificate against ca_cert_file")
+			}
+		})
+	}
+}
@@ -25,9 +25,9 @@ func dataSourceAviatrixCallerIdentity() *schema.Resource {
 func dataSourceAviatrixCallerIdentityRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*goaviatrix.Client)
 
-	log.Printf("[DEBUG] CID is '%s'", client.CID)
+	log.Printf("[DEBUG] CID is '%s'", client.CurrentCID())
 
 	d.SetId(time.Now().UTC().String())
-	d.Set("cid", client.CID)
+	d.Set("cid", client.CurrentCID())
 	return nil
 }
@@ -0,0 +1,52 @@
+package aviatrix
+
+import (
+	"context"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAviatrixControllerVersion() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAviatrixControllerVersionRead,
+
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Full software version of the controller, e.g. '6.5.2835'.",
+			},
+			"release": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Major and minor version of the controller, e.g. '6.5'.",
+			},
+			"build": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Build number of the controller version.",
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixControllerVersionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	version, err := client.GetControllerVersion()
+	if err != nil {
+		return diag.Errorf("could not get controller version: %v", err)
+	}
+	release, parsedVersion, err := goaviatrix.ParseVersion(version)
+	if err != nil {
+		return diag.Errorf("could not parse controller version %q: %v", version, err)
+	}
+
+	d.Set("version", version)
+	d.Set("release", release)
+	d.Set("build", int(parsedVersion.Build))
+	d.SetId(version)
+	return nil
+}
@@ -0,0 +1,55 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixControllerVersion_basic(t *testing.T) {
+	resourceName := "data.aviatrix_controller_version.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_CONTROLLER_VERSION")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Controller Version test as SKIP_DATA_CONTROLLER_VERSION is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixControllerVersionConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixControllerVersion(resourceName),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixControllerVersionConfigBasic() string {
+	return `
+data "aviatrix_controller_version" "foo" {
+}
+	`
+}
+
+func testAccDataSourceAviatrixControllerVersion(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+		if rs.Primary.Attributes["version"] == "" || rs.Primary.Attributes["release"] == "" {
+			return fmt.Errorf("no controller version was returned")
+		}
+
+		return nil
+	}
+}
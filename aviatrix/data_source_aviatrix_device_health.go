@@ -0,0 +1,71 @@
+package aviatrix
+
+import (
+	"context"
+	"sort"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAviatrixDeviceHealth() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceAviatrixDeviceHealthRead,
+
+		Schema: map[string]*schema.Schema{
+			"devices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Connection status of all registered devices, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the device.",
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Description: "Status of the controller's connection to the device, e.g. 'connected' or " +
+								"'disconnected'. 'unknown' if the status could not be fetched.",
+						},
+						"last_seen": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time the controller last reached the device. Empty if not reported by the controller.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixDeviceHealthRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	devices, err := client.ListDevices(ctx)
+	if err != nil {
+		return diag.Errorf("could not list devices: %v", err)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Name < devices[j].Name
+	})
+
+	var result []map[string]interface{}
+	for _, health := range client.GetDevicesHealth(ctx, devices) {
+		result = append(result, map[string]interface{}{
+			"name":      health.Name,
+			"status":    health.Status,
+			"last_seen": health.LastSeen,
+		})
+	}
+	if err := d.Set("devices", result); err != nil {
+		return diag.Errorf("could not set devices: %v", err)
+	}
+
+	d.SetId("device_health")
+	return nil
+}
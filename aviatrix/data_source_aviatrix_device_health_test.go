@@ -0,0 +1,65 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixDeviceHealth_basic(t *testing.T) {
+	rName := acctest.RandString(5)
+	resourceName := "data.aviatrix_device_health.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_DEVICE_HEALTH")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Device Health test as SKIP_DATA_DEVICE_HEALTH is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			deviceRegistrationPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixDeviceHealthConfigBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixDeviceHealth(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "devices.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixDeviceHealthConfigBasic(rName string) string {
+	return fmt.Sprintf(`
+resource "aviatrix_device_registration" "test_device" {
+	name      = "device-%s"
+	public_ip = "%s"
+	username  = "ec2-user"
+	key_file  = "%s"
+	host_os   = "ios"
+}
+
+data "aviatrix_device_health" "foo" {
+	depends_on = [aviatrix_device_registration.test_device]
+}
+`, rName, os.Getenv("DEVICE_PUBLIC_IP"), os.Getenv("DEVICE_KEY_FILE_PATH"))
+}
+
+func testAccDataSourceAviatrixDeviceHealth(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package aviatrix
+
+import (
+	"context"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAviatrixDeviceRegistration() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceAviatrixDeviceRegistrationRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the device.",
+			},
+			"public_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Public IP address of the device.",
+			},
+			"public_ip_ha": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Public IP address of the second appliance of an HA pair.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Username used to connect to the device.",
+			},
+			"host_os": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Device host OS.",
+			},
+			"ssh_port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "SSH port used to connect to the device.",
+			},
+			"mtu": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "MTU of the WAN uplink of the device. 0 if not reported by the controller.",
+			},
+			"address_1": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Address line 1.",
+			},
+			"address_2": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Address line 2.",
+			},
+			"city": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "City.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "State.",
+			},
+			"country": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ISO two-letter country code.",
+			},
+			"zip_code": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Zip code.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description.",
+			},
+			"software_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Software version of the device.",
+			},
+			"is_caag": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether this device is a Managed CloudN device (CaaG).",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the device was registered. Empty if not reported by the controller.",
+			},
+			"registered_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Controller account that registered the device. Empty if not reported by the controller.",
+			},
+			"host_key_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Fingerprint of the SSH host key of the device. Empty if not reported by the controller.",
+			},
+			"model": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hardware or VM model of the device. Empty if not reported by the controller.",
+			},
+			"serial_number": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Serial number of the device. Empty if not reported by the controller.",
+			},
+			"managed_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the device was onboarded, e.g. 'terraform', 'ui' or 'api'. Empty if not reported by the controller.",
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixDeviceRegistrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	name := d.Get("name").(string)
+	device, err := client.GetDevice(ctx, &goaviatrix.Device{Name: name})
+	if err == goaviatrix.ErrNotFound {
+		return diag.Errorf("could not find device registration %q", name)
+	}
+	if err != nil {
+		return diag.Errorf("could not read device registration %q: %v", name, err)
+	}
+
+	d.Set("name", device.Name)
+	d.Set("public_ip", device.PublicIP)
+	d.Set("public_ip_ha", device.PublicIPHa)
+	d.Set("username", device.Username)
+	d.Set("host_os", device.HostOS)
+	d.Set("ssh_port", sshPortOrDefault(device.SshPort))
+	d.Set("mtu", device.Mtu)
+	d.Set("address_1", device.Address1)
+	d.Set("address_2", device.Address2)
+	d.Set("city", device.City)
+	d.Set("state", device.State)
+	d.Set("country", device.Country)
+	d.Set("zip_code", device.ZipCode)
+	d.Set("description", device.Description)
+	d.Set("software_version", device.SoftwareVersion)
+	d.Set("is_caag", device.IsCaag)
+	d.Set("created_at", device.CreatedAt)
+	d.Set("registered_by", device.RegisteredBy)
+	d.Set("host_key_fingerprint", device.HostKeyFingerprint)
+	d.Set("model", device.Model)
+	d.Set("serial_number", device.SerialNumber)
+	d.Set("managed_by", device.ManagedBy)
+
+	d.SetId(device.Name)
+	return nil
+}
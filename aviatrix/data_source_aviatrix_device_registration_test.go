@@ -0,0 +1,59 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixDeviceRegistration_basic(t *testing.T) {
+	rName := acctest.RandString(5)
+	resourceName := "data.aviatrix_device_registration.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_DEVICE_REGISTRATION")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Device Registration test as SKIP_DATA_DEVICE_REGISTRATION is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			deviceRegistrationPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixDeviceRegistrationConfigBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixDeviceRegistration(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", fmt.Sprintf("device-registration-%s", rName)),
+					resource.TestCheckResourceAttr(resourceName, "public_ip", os.Getenv("DEVICE_PUBLIC_IP")),
+					resource.TestCheckResourceAttr(resourceName, "host_os", "ios"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixDeviceRegistrationConfigBasic(rName string) string {
+	return testAccDeviceRegistrationBasic(rName) + `
+data "aviatrix_device_registration" "foo" {
+	name = aviatrix_device_registration.test_device.name
+}
+`
+}
+
+func testAccDataSourceAviatrixDeviceRegistration(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package aviatrix
+
+import (
+	"context"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAviatrixDeviceSoftwareVersions() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceAviatrixDeviceSoftwareVersionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"device_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description: "Name of a managed CloudN (CaaG) device. If set, only the software versions the device " +
+					"can be upgraded or downgraded to are returned.",
+			},
+			"versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Software versions the controller can install, newest first.",
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixDeviceSoftwareVersionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	deviceName := d.Get("device_name").(string)
+	if deviceName != "" {
+		device, err := client.GetDevice(ctx, &goaviatrix.Device{Name: deviceName})
+		if err != nil {
+			return diag.Errorf("could not get device %s: %v", deviceName, err)
+		}
+		if !device.IsCaag {
+			return diag.Errorf("software versions can only be listed for managed cloudN (CaaG) devices, "+
+				"device %s is not a CaaG", deviceName)
+		}
+		// the controller matches device names case-insensitively, use the name it reports
+		deviceName = device.Name
+	}
+
+	versions, err := client.ListGatewayImageVersions(ctx, deviceName)
+	if err != nil {
+		return diag.Errorf("could not list software versions: %v", err)
+	}
+	if err := d.Set("versions", versions); err != nil {
+		return diag.Errorf("could not set versions: %v", err)
+	}
+
+	if deviceName != "" {
+		d.SetId(deviceName)
+	} else {
+		d.SetId("device_software_versions")
+	}
+	return nil
+}
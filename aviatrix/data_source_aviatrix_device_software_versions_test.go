@@ -0,0 +1,52 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixDeviceSoftwareVersions_basic(t *testing.T) {
+	resourceName := "data.aviatrix_device_software_versions.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_DEVICE_SOFTWARE_VERSIONS")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Device Software Versions test as SKIP_DATA_DEVICE_SOFTWARE_VERSIONS is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixDeviceSoftwareVersionsConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixDeviceSoftwareVersions(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "versions.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixDeviceSoftwareVersionsConfigBasic() string {
+	return `
+data "aviatrix_device_software_versions" "foo" {}
+`
+}
+
+func testAccDataSourceAviatrixDeviceSoftwareVersions(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAviatrixDevices() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceAviatrixDevicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"host_os": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ios", "aviatrix"}, true),
+				Description:  "Only list devices with this host OS.",
+			},
+			"is_caag": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only list devices that are, or are not, Managed CloudN devices (CaaG).",
+			},
+			"devices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of registered devices, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the device.",
+						},
+						"public_ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Public IP address of the device.",
+						},
+						"host_os": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Device host OS.",
+						},
+						"is_caag": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether this device is a Managed CloudN device (CaaG).",
+						},
+						"software_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Software version of the device.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixDevicesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	devices, err := client.ListDevices(ctx)
+	if err != nil {
+		return diag.Errorf("could not list devices: %v", err)
+	}
+
+	// the controller does not filter devices, so the filters are applied here
+	hostOS := d.Get("host_os").(string)
+	var isCaag *bool
+	//lint:ignore SA1019 GetOkExists is the only way to tell an unset bool from false
+	if v, ok := d.GetOkExists("is_caag"); ok {
+		b := v.(bool)
+		isCaag = &b
+	}
+	devices = filterDevices(devices, hostOS, isCaag)
+
+	var result []map[string]interface{}
+	for _, device := range devices {
+		result = append(result, map[string]interface{}{
+			"name":             device.Name,
+			"public_ip":        device.PublicIP,
+			"host_os":          strings.ToLower(device.HostOS),
+			"is_caag":          device.IsCaag,
+			"software_version": device.SoftwareVersion,
+		})
+	}
+	if err := d.Set("devices", result); err != nil {
+		return diag.Errorf("could not set devices: %v", err)
+	}
+
+	id := "devices"
+	if hostOS != "" {
+		id += "~host_os~" + strings.ToLower(hostOS)
+	}
+	if isCaag != nil {
+		id += fmt.Sprintf("~is_caag~%t", *isCaag)
+	}
+	d.SetId(id)
+	return nil
+}
+
+// filterDevices returns the devices with the given host OS and CaaG status, sorted by name.
+// An empty hostOS or nil isCaag matches all devices.
+func filterDevices(devices []*goaviatrix.Device, hostOS string, isCaag *bool) []*goaviatrix.Device {
+	var filtered []*goaviatrix.Device
+	for _, device := range devices {
+		if hostOS != "" && !strings.EqualFold(device.HostOS, hostOS) {
+			continue
+		}
+		if isCaag != nil && device.IsCaag != *isCaag {
+			continue
+		}
+		filtered = append(filtered, device)
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Name < filtered[j].Name
+	})
+	return filtered
+}
@@ -0,0 +1,102 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixDevices_basic(t *testing.T) {
+	rName := acctest.RandString(5)
+	resourceName := "data.aviatrix_devices.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_DEVICES")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Devices test as SKIP_DATA_DEVICES is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			deviceRegistrationPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixDevicesConfigBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixDevices(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "devices.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixDevicesConfigBasic(rName string) string {
+	return fmt.Sprintf(`
+resource "aviatrix_device_registration" "test_device" {
+	name      = "device-%s"
+	public_ip = "%s"
+	username  = "ec2-user"
+	key_file  = "%s"
+	host_os   = "ios"
+}
+
+data "aviatrix_devices" "foo" {
+	host_os = "ios"
+
+	depends_on = [aviatrix_device_registration.test_device]
+}
+`, rName, os.Getenv("DEVICE_PUBLIC_IP"), os.Getenv("DEVICE_KEY_FILE_PATH"))
+}
+
+func testAccDataSourceAviatrixDevices(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+
+		return nil
+	}
+}
+
+func TestFilterDevices(t *testing.T) {
+	devices := []*goaviatrix.Device{
+		{Name: "ios-2", HostOS: "ios"},
+		{Name: "caag", HostOS: "aviatrix", IsCaag: true},
+		{Name: "ios-1", HostOS: "IOS"},
+		{Name: "aviatrix", HostOS: "aviatrix"},
+	}
+	caag, notCaag := true, false
+	tests := []struct {
+		name   string
+		hostOS string
+		isCaag *bool
+		want   []string
+	}{
+		{"no filter", "", nil, []string{"aviatrix", "caag", "ios-1", "ios-2"}},
+		{"host os", "ios", nil, []string{"ios-1", "ios-2"}},
+		{"caag", "", &caag, []string{"caag"}},
+		{"not caag", "aviatrix", &notCaag, []string{"aviatrix"}},
+		{"no match", "ios", &caag, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, device := range filterDevices(devices, tt.hostOS, tt.isCaag) {
+				got = append(got, device.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterDevices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
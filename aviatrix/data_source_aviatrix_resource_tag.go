@@ -0,0 +1,94 @@
+package aviatrix
+
+import (
+	"fmt"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAviatrixResourceTag() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviatrixResourceTagRead,
+
+		Schema: map[string]*schema.Schema{
+			"cloud_type": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateCloudType,
+				Description:  "Type of cloud service provider.",
+			},
+			"resource_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Type of the resource, e.g. 'gw'.",
+			},
+			"resource_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"resource_name", "resource_id"},
+				Description:  "Name of the resource.",
+			},
+			"resource_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"resource_name", "resource_id"},
+				Description:  "Cloud ID or ARN of the resource. Used if the resource is not found by 'resource_name'.",
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      goaviatrix.TagScopeCloud,
+				ValidateFunc: validation.StringInSlice([]string{goaviatrix.TagScopeCloud, goaviatrix.TagScopeController}, false),
+				Description:  "Scope of the tags, 'cloud' for cloud tags or 'controller' for metadata labels on the controller.",
+			},
+			"key_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return the tags whose keys start with this prefix.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Tags of the resource.",
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixResourceTagRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*goaviatrix.Client)
+
+	tags := &goaviatrix.Tags{
+		CloudType:    d.Get("cloud_type").(int),
+		ResourceType: d.Get("resource_type").(string),
+		ResourceName: d.Get("resource_name").(string),
+		ResourceID:   d.Get("resource_id").(string),
+		Scope:        d.Get("scope").(string),
+		KeyPrefix:    d.Get("key_prefix").(string),
+	}
+	resource := tags.ResourceName
+	if resource == "" {
+		resource = tags.ResourceID
+	}
+	tagsMap, err := client.GetTagsMap(tags)
+	if err == goaviatrix.ErrNotFound {
+		return fmt.Errorf("could not find %s %s", tags.ResourceType, resource)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get tags of %s %s: %v", tags.ResourceType, resource, err)
+	}
+	if err := d.Set("tags", tagsMap); err != nil {
+		return fmt.Errorf("could not set tags: %v", err)
+	}
+
+	id := fmt.Sprintf("resource_tag~%d~%s~%s", tags.CloudType, tags.ResourceType, resource)
+	if tags.Scope == goaviatrix.TagScopeController {
+		id += "~" + tags.Scope
+	}
+	d.SetId(id)
+	return nil
+}
@@ -0,0 +1,83 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixResourceTag_basic(t *testing.T) {
+	rName := acctest.RandString(5)
+	resourceName := "data.aviatrix_resource_tag.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_RESOURCE_TAG")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Resource Tag test as SKIP_DATA_RESOURCE_TAG is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			preGatewayCheck(t, ". Set SKIP_DATA_RESOURCE_TAG to yes to skip Data Source Resource Tag tests")
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixResourceTagConfigBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixResourceTag(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "tags.owner", "network"),
+					resource.TestCheckResourceAttr(resourceName, "tags.cidrs", "10.0.0.0/16,10.1.0.0/16"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixResourceTagConfigBasic(rName string) string {
+	return fmt.Sprintf(`
+resource "aviatrix_account" "test" {
+	account_name 	   = "tfa-%s"
+	cloud_type         = 1
+	aws_account_number = "%s"
+	aws_iam            = "false"
+	aws_access_key     = "%s"
+	aws_secret_key     = "%s"
+}
+resource "aviatrix_gateway" "test" {
+	cloud_type   = 1
+	account_name = aviatrix_account.test.account_name
+	gw_name      = "tfg-%s"
+	vpc_id       = "%s"
+	vpc_reg      = "%s"
+	gw_size      = "t2.micro"
+	subnet       = "%s"
+	tags         = {
+		owner = "network"
+		cidrs = "10.0.0.0/16,10.1.0.0/16"
+	}
+}
+data "aviatrix_resource_tag" "foo" {
+	cloud_type    = 1
+	resource_type = "gw"
+	resource_name = aviatrix_gateway.test.gw_name
+}
+	`, rName, os.Getenv("AWS_ACCOUNT_NUMBER"), os.Getenv("AWS_ACCESS_KEY"), os.Getenv("AWS_SECRET_KEY"),
+		rName, os.Getenv("AWS_VPC_ID"), os.Getenv("AWS_REGION"), os.Getenv("AWS_SUBNET"))
+}
+
+func testAccDataSourceAviatrixResourceTag(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+
+		return nil
+	}
+}
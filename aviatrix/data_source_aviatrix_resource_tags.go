@@ -0,0 +1,80 @@
+package aviatrix
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAviatrixResourceTags() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviatrixResourceTagsRead,
+
+		Schema: map[string]*schema.Schema{
+			"cloud_type": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateCloudType,
+				Description:  "Type of cloud service provider.",
+			},
+			"resource_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Type of the resources to list tags for, e.g. 'gw'.",
+			},
+			"resources": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of resources of the given type with their tags.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the resource.",
+						},
+						"tags": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Tags of the resource.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviatrixResourceTagsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*goaviatrix.Client)
+
+	cloudType := d.Get("cloud_type").(int)
+	resourceType := d.Get("resource_type").(string)
+	allTags, err := client.ListAllTags(cloudType, resourceType)
+	if err != nil {
+		return fmt.Errorf("could not list tags for resource type %s: %v", resourceType, err)
+	}
+
+	resourceNames := make([]string, 0, len(allTags))
+	for resourceName := range allTags {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	sort.Strings(resourceNames)
+
+	var resources []map[string]interface{}
+	for _, resourceName := range resourceNames {
+		resources = append(resources, map[string]interface{}{
+			"resource_name": resourceName,
+			"tags":          allTags[resourceName],
+		})
+	}
+	if err := d.Set("resources", resources); err != nil {
+		return fmt.Errorf("could not set resources: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("resource_tags~%d~%s", cloudType, resourceType))
+	return nil
+}
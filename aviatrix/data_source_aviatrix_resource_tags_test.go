@@ -0,0 +1,55 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceAviatrixResourceTags_basic(t *testing.T) {
+	resourceName := "data.aviatrix_resource_tags.foo"
+
+	skipAcc := os.Getenv("SKIP_DATA_RESOURCE_TAGS")
+	if skipAcc == "yes" {
+		t.Skip("Skipping Data Source Resource Tags test as SKIP_DATA_RESOURCE_TAGS is set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAviatrixResourceTagsConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceAviatrixResourceTags(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "resources.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAviatrixResourceTagsConfigBasic() string {
+	return `
+data "aviatrix_resource_tags" "foo" {
+	cloud_type    = 1
+	resource_type = "gw"
+}
+`
+}
+
+func testAccDataSourceAviatrixResourceTags(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("root module has no data source called %s", name)
+		}
+
+		return nil
+	}
+}
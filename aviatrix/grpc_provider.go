@@ -0,0 +1,57 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// grpcProviderServer serves the Provider like the server of the SDK. It also validates the configuration as
+// written, which the SDK does not pass to resources: their ValidateFunc and CustomizeDiff get the values
+// of environment variables where an attribute with a DefaultFunc is not set.
+type grpcProviderServer struct {
+	*schema.GRPCProviderServer
+	provider *schema.Provider
+}
+
+// NewGRPCProviderServer returns the gRPC server of the Provider to be served by the plugin.
+func NewGRPCProviderServer() tfprotov5.ProviderServer {
+	provider := Provider()
+	return &grpcProviderServer{
+		GRPCProviderServer: schema.NewGRPCProviderServer(provider),
+		provider:           provider,
+	}
+}
+
+func (s *grpcProviderServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	resp, err := s.GRPCProviderServer.ValidateResourceTypeConfig(ctx, req)
+	if err != nil || req.TypeName != "aviatrix_device_registration" {
+		return resp, err
+	}
+	if diag := s.inlineDevicePasswordWarning(req); diag != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag)
+	}
+	return resp, nil
+}
+
+// inlineDevicePasswordWarning warns when 'password' of a device is set in the config instead of through the
+// AVIATRIX_DEVICE_PASSWORD environment variable. Warnings, unlike CustomizeDiff errors, do not fail the plan.
+func (s *grpcProviderServer) inlineDevicePasswordWarning(req *tfprotov5.ValidateResourceTypeConfigRequest) *tfprotov5.Diagnostic {
+	r := s.provider.ResourcesMap[req.TypeName]
+	// a config that can not be decoded is already reported by the SDK
+	config, err := msgpack.Unmarshal(req.Config.MsgPack, r.CoreConfigSchema().ImpliedType())
+	if err != nil || config.IsNull() || !config.IsKnown() || config.GetAttr("password").IsNull() {
+		return nil
+	}
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Device password set in the configuration",
+		Detail: fmt.Sprintf("\"password\" is set in the configuration, consider setting it through the environment "+
+			"variable %s instead to keep the secret out of the configuration", deviceCredentialEnvVars["password"]),
+		Attribute: tftypes.NewAttributePath().WithAttributeName("password"),
+	}
+}
@@ -0,0 +1,63 @@
+package aviatrix
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestValidateInlineDevicePassword(t *testing.T) {
+	os.Setenv("AVIATRIX_DEVICE_PASSWORD", "from-env")
+	defer os.Unsetenv("AVIATRIX_DEVICE_PASSWORD")
+
+	tests := []struct {
+		name     string
+		password cty.Value
+		wantWarn bool
+	}{
+		{"inline", cty.StringVal("inline-secret"), true},
+		{"inline with the value of the environment variable", cty.StringVal("from-env"), true},
+		{"inline and only known after apply", cty.UnknownVal(cty.String), true},
+		{"from environment variable", cty.NullVal(cty.String), false},
+	}
+	server := NewGRPCProviderServer()
+	configType := Provider().ResourcesMap["aviatrix_device_registration"].CoreConfigSchema().ImpliedType()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := map[string]cty.Value{}
+			for name, attributeType := range configType.AttributeTypes() {
+				attributes[name] = cty.NullVal(attributeType)
+			}
+			attributes["name"] = cty.StringVal("device")
+			attributes["public_ip"] = cty.StringVal("1.2.3.4")
+			attributes["username"] = cty.StringVal("ec2-user")
+			attributes["password"] = tt.password
+			config, err := msgpack.Marshal(cty.ObjectVal(attributes), configType)
+			if err != nil {
+				t.Fatalf("msgpack.Marshal() unexpected error: %v", err)
+			}
+
+			resp, err := server.ValidateResourceTypeConfig(context.Background(), &tfprotov5.ValidateResourceTypeConfigRequest{
+				TypeName: "aviatrix_device_registration",
+				Config:   &tfprotov5.DynamicValue{MsgPack: config},
+			})
+			if err != nil {
+				t.Fatalf("ValidateResourceTypeConfig() unexpected error: %v", err)
+			}
+			warned := false
+			for _, diag := range resp.Diagnostics {
+				if diag.Severity == tfprotov5.DiagnosticSeverityError {
+					t.Errorf("ValidateResourceTypeConfig() unexpected error: %s: %s", diag.Summary, diag.Detail)
+				}
+				warned = warned || diag.Summary == "Device password set in the configuration"
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("ValidateResourceTypeConfig() warned about the password = %t, want %t", warned, tt.wantWarn)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package aviatrix
 import (
 	"errors"
 	"os"
+	"time"
 
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var supportedVersions = []string{"6.6"}
@@ -42,6 +45,128 @@ func Provider() *schema.Provider {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("AVIATRIX_CA_CERT_FILE", ""),
+				ConflictsWith: []string{"path_to_ca_certificate"},
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: envDefaultFunc("AVIATRIX_PROXY_URL"),
+			},
+			"tls_min_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+			},
+			"api_rate_limit": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"http_client_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"http_dial_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(goaviatrix.DefaultDialTimeout.Seconds()),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"http_keepalive": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(goaviatrix.DefaultKeepAlive.Seconds()),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"http_idle_conn_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(goaviatrix.DefaultIdleConnTimeout.Seconds()),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_base_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      500,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_max_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(goaviatrix.DefaultRetryMaxDelay / time.Millisecond),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"debug_http": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AVIATRIX_DEBUG_HTTP", false),
+			},
+			"circuit_breaker_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"circuit_breaker_window": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(goaviatrix.DefaultCircuitBreakerWindow.Seconds()),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"circuit_breaker_cooldown": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(goaviatrix.DefaultCircuitBreakerCooldown.Seconds()),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_tls_verify": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				// no default, so skipTLSVerify can tell whether it is set
+				DefaultFunc: schema.EnvDefaultFunc("AVIATRIX_SKIP_TLS_VERIFY", nil),
+			},
+			"default_device_host_os": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ios",
+				ValidateFunc: validation.StringInSlice([]string{"ios", "aviatrix"}, false),
+			},
+			"disable_tag_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AVIATRIX_DISABLE_TAG_CACHE", false),
+			},
+			"ignore_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"key_prefixes": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"tag_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -78,8 +203,10 @@ func Provider() *schema.Provider {
 			"aviatrix_copilot_association":                            resourceAviatrixCopilotAssociation(),
 			"aviatrix_datadog_agent":                                  resourceAviatrixDatadogAgent(),
 			"aviatrix_device_aws_tgw_attachment":                      resourceAviatrixDeviceAwsTgwAttachment(),
+			"aviatrix_device_connection_profile":                      resourceAviatrixDeviceConnectionProfile(),
 			"aviatrix_device_interface_config":                        resourceAviatrixDeviceInterfaceConfig(),
 			"aviatrix_device_registration":                            resourceAviatrixDeviceRegistration(),
+			"aviatrix_device_registration_bulk":                       resourceAviatrixDeviceRegistrationBulk(),
 			"aviatrix_device_tag":                                     resourceAviatrixDeviceTag(),
 			"aviatrix_device_transit_gateway_attachment":              resourceAviatrixDeviceTransitGatewayAttachment(),
 			"aviatrix_device_virtual_wan_attachment":                  resourceAviatrixDeviceVirtualWanAttachment(),
@@ -136,11 +263,18 @@ func Provider() *schema.Provider {
 		DataSourcesMap: map[string]*schema.Resource{
 			"aviatrix_account":                    dataSourceAviatrixAccount(),
 			"aviatrix_caller_identity":            dataSourceAviatrixCallerIdentity(),
+			"aviatrix_controller_version":         dataSourceAviatrixControllerVersion(),
+			"aviatrix_device_health":              dataSourceAviatrixDeviceHealth(),
+			"aviatrix_device_registration":        dataSourceAviatrixDeviceRegistration(),
+			"aviatrix_device_software_versions":   dataSourceAviatrixDeviceSoftwareVersions(),
+			"aviatrix_devices":                    dataSourceAviatrixDevices(),
 			"aviatrix_firenet":                    dataSourceAviatrixFireNet(),
 			"aviatrix_firenet_firewall_manager":   dataSourceAviatrixFireNetFirewallManager(),
 			"aviatrix_firenet_vendor_integration": dataSourceAviatrixFireNetVendorIntegration(),
 			"aviatrix_gateway":                    dataSourceAviatrixGateway(),
 			"aviatrix_gateway_image":              dataSourceAviatrixGatewayImage(),
+			"aviatrix_resource_tag":               dataSourceAviatrixResourceTag(),
+			"aviatrix_resource_tags":              dataSourceAviatrixResourceTags(),
 			"aviatrix_spoke_gateway":              dataSourceAviatrixSpokeGateway(),
 			"aviatrix_transit_gateway":            dataSourceAviatrixTransitGateway(),
 			"aviatrix_vpc":                        dataSourceAviatrixVpc(),
@@ -161,13 +295,69 @@ func envDefaultFunc(k string) schema.SchemaDefaultFunc {
 	}
 }
 
+// transportSettings returns the connection settings of the provider configuration
+func transportSettings(d *schema.ResourceData) goaviatrix.TransportSettings {
+	return goaviatrix.TransportSettings{
+		DialTimeout:     time.Duration(d.Get("http_dial_timeout").(int)) * time.Second,
+		KeepAlive:       time.Duration(d.Get("http_keepalive").(int)) * time.Second,
+		IdleConnTimeout: time.Duration(d.Get("http_idle_conn_timeout").(int)) * time.Second,
+	}
+}
+
+// ignoreTagsConfig returns the tags to ignore of the provider configuration
+func ignoreTagsConfig(d *schema.ResourceData) goaviatrix.IgnoreTagsConfig {
+	if len(d.Get("ignore_tags").([]interface{})) == 0 {
+		return goaviatrix.IgnoreTagsConfig{}
+	}
+	return goaviatrix.IgnoreTagsConfig{
+		Keys:        getStringSet(d, "ignore_tags.0.keys"),
+		KeyPrefixes: getStringSet(d, "ignore_tags.0.key_prefixes"),
+	}
+}
+
+// circuitBreakerSettings returns the circuit breaker settings of the provider configuration
+func circuitBreakerSettings(d *schema.ResourceData) goaviatrix.CircuitBreakerSettings {
+	return goaviatrix.CircuitBreakerSettings{
+		Threshold: d.Get("circuit_breaker_threshold").(int),
+		Window:    time.Duration(d.Get("circuit_breaker_window").(int)) * time.Second,
+		Cooldown:  time.Duration(d.Get("circuit_breaker_cooldown").(int)) * time.Second,
+	}
+}
+
+// skipTLSVerify returns whether the TLS certificate of the controller is not verified. skip_tls_verify
+// decides if it is set, in the configuration or its environment variable. Otherwise the certificate is
+// only verified if verify_ssl_certificate is true or ca_cert_file is set.
+func skipTLSVerify(d *schema.ResourceData) bool {
+	if skip, ok := d.GetOkExists("skip_tls_verify"); ok {
+		return skip.(bool)
+	}
+	return !d.Get("verify_ssl_certificate").(bool) && d.Get("ca_cert_file").(string) == ""
+}
+
 func aviatrixConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
-		ControllerIP: d.Get("controller_ip").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		VerifyCert:   d.Get("verify_ssl_certificate").(bool),
-		PathToCACert: d.Get("path_to_ca_certificate").(string),
+		ControllerIP:  d.Get("controller_ip").(string),
+		Username:      d.Get("username").(string),
+		Password:      d.Get("password").(string),
+		VerifyCert:    d.Get("verify_ssl_certificate").(bool),
+		PathToCACert:  d.Get("path_to_ca_certificate").(string),
+		ProxyURL:      d.Get("proxy_url").(string),
+		TLSMinVersion: d.Get("tls_min_version").(string),
+		APIRateLimit:  d.Get("api_rate_limit").(float64),
+		SkipTLSVerify: skipTLSVerify(d),
+		CACertFile:    d.Get("ca_cert_file").(string),
+
+		HTTPClientTimeout: time.Duration(d.Get("http_client_timeout").(int)) * time.Second,
+		Transport:         transportSettings(d),
+		RetryBaseDelay:    time.Duration(d.Get("retry_base_delay").(int)) * time.Millisecond,
+		RetryMaxDelay:     time.Duration(d.Get("retry_max_delay").(int)) * time.Millisecond,
+		CircuitBreaker:    circuitBreakerSettings(d),
+		DebugHTTP:         d.Get("debug_http").(bool),
+
+		DefaultDeviceHostOS: d.Get("default_device_host_os").(string),
+		DisableTagCache:     d.Get("disable_tag_cache").(bool),
+		IgnoreTags:          ignoreTagsConfig(d),
+		TagPrefix:           d.Get("tag_prefix").(string),
 	}
 
 	skipVersionValidation := d.Get("skip_version_validation").(bool)
@@ -190,11 +380,28 @@ func aviatrixConfigure(d *schema.ResourceData) (interface{}, error) {
 
 func aviatrixConfigureWithoutVersionValidation(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
-		ControllerIP: d.Get("controller_ip").(string),
-		Username:     d.Get("username").(string),
-		Password:     d.Get("password").(string),
-		VerifyCert:   d.Get("verify_ssl_certificate").(bool),
-		PathToCACert: d.Get("path_to_ca_certificate").(string),
+		ControllerIP:  d.Get("controller_ip").(string),
+		Username:      d.Get("username").(string),
+		Password:      d.Get("password").(string),
+		VerifyCert:    d.Get("verify_ssl_certificate").(bool),
+		PathToCACert:  d.Get("path_to_ca_certificate").(string),
+		ProxyURL:      d.Get("proxy_url").(string),
+		TLSMinVersion: d.Get("tls_min_version").(string),
+		APIRateLimit:  d.Get("api_rate_limit").(float64),
+		SkipTLSVerify: skipTLSVerify(d),
+		CACertFile:    d.Get("ca_cert_file").(string),
+
+		HTTPClientTimeout: time.Duration(d.Get("http_client_timeout").(int)) * time.Second,
+		Transport:         transportSettings(d),
+		RetryBaseDelay:    time.Duration(d.Get("retry_base_delay").(int)) * time.Millisecond,
+		RetryMaxDelay:     time.Duration(d.Get("retry_max_delay").(int)) * time.Millisecond,
+		CircuitBreaker:    circuitBreakerSettings(d),
+		DebugHTTP:         d.Get("debug_http").(bool),
+
+		DefaultDeviceHostOS: d.Get("default_device_host_os").(string),
+		DisableTagCache:     d.Get("disable_tag_cache").(bool),
+		IgnoreTags:          ignoreTagsConfig(d),
+		TagPrefix:           d.Get("tag_prefix").(string),
 	}
 
 	return config.Client()
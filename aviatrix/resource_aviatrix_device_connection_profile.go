@@ -0,0 +1,143 @@
+package aviatrix
+
+import (
+	"context"
+	"log"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAviatrixDeviceConnectionProfile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAviatrixDeviceConnectionProfileCreate,
+		ReadContext:   resourceAviatrixDeviceConnectionProfileRead,
+		UpdateContext: resourceAviatrixDeviceConnectionProfileUpdate,
+		DeleteContext: resourceAviatrixDeviceConnectionProfileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Name of the connection profile.",
+			},
+			"username": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateSSHUsername,
+				Description:  "Username to use to connect to the devices. Must not contain whitespace.",
+			},
+			"password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"password", "key_file_content"},
+				Description:  "Password to connect to the devices.",
+			},
+			"key_file_content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"password", "key_file_content"},
+				Description:  "Content of the private key in PEM format to connect to the devices.",
+			},
+			"key_passphrase": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password"},
+				Description:   "Passphrase for an encrypted private key. Only used with 'key_file_content'.",
+			},
+			"ssh_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      22,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "SSH port to use to connect to the devices. Defaults to 22 if not set.",
+			},
+		},
+	}
+}
+
+func marshalDeviceConnectionProfileInput(d *schema.ResourceData) *goaviatrix.DeviceConnectionProfile {
+	return &goaviatrix.DeviceConnectionProfile{
+		Name:           d.Get("name").(string),
+		Username:       d.Get("username").(string),
+		Password:       d.Get("password").(string),
+		KeyFileContent: d.Get("key_file_content").(string),
+		KeyPassphrase:  d.Get("key_passphrase").(string),
+		SshPort:        d.Get("ssh_port").(int),
+	}
+}
+
+func resourceAviatrixDeviceConnectionProfileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	profile := marshalDeviceConnectionProfileInput(d)
+	if err := client.CreateDeviceConnectionProfile(ctx, profile); err != nil {
+		return diag.Errorf("could not create device connection profile %s: %v", profile.Name, err)
+	}
+
+	d.SetId(profile.Name)
+	return resourceAviatrixDeviceConnectionProfileRead(ctx, d, meta)
+}
+
+func resourceAviatrixDeviceConnectionProfileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	name := d.Get("name").(string)
+	if name == "" {
+		id := d.Id()
+		log.Printf("[DEBUG] Looks like an import, no device connection profile name received. Import Id is %s", id)
+		d.SetId(id)
+		name = id
+	}
+
+	profile, err := client.GetDeviceConnectionProfile(ctx, name)
+	if err == goaviatrix.ErrNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not find device connection profile %s: %v", name, err)
+	}
+
+	d.Set("name", profile.Name)
+	d.Set("username", profile.Username)
+	d.Set("password", profile.Password)
+	d.Set("key_file_content", profile.KeyFileContent)
+	d.Set("key_passphrase", profile.KeyPassphrase)
+	d.Set("ssh_port", profile.SshPort)
+
+	d.SetId(profile.Name)
+	return nil
+}
+
+func resourceAviatrixDeviceConnectionProfileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	profile := marshalDeviceConnectionProfileInput(d)
+	if err := client.UpdateDeviceConnectionProfile(ctx, profile); err != nil {
+		return diag.Errorf("could not update device connection profile %s: %v", profile.Name, err)
+	}
+
+	return resourceAviatrixDeviceConnectionProfileRead(ctx, d, meta)
+}
+
+func resourceAviatrixDeviceConnectionProfileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	name := d.Get("name").(string)
+	if err := client.DeleteDeviceConnectionProfile(ctx, name); err != nil {
+		return diag.Errorf("could not delete device connection profile %s: %v", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccAviatrixDeviceConnectionProfile_basic(t *testing.T) {
+	if os.Getenv("SKIP_DEVICE_CONNECTION_PROFILE") == "yes" {
+		t.Skip("Skipping Device connection profile test as SKIP_DEVICE_CONNECTION_PROFILE is set")
+	}
+
+	rName := acctest.RandString(5)
+	resourceName := "aviatrix_device_connection_profile.test_profile"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDeviceConnectionProfileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceConnectionProfileBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeviceConnectionProfileExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "username", "ec2-user"),
+					resource.TestCheckResourceAttr(resourceName, "ssh_port", "2222"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccDeviceConnectionProfileBasic(rName string) string {
+	return fmt.Sprintf(`
+resource "aviatrix_device_connection_profile" "test_profile" {
+	name     = "device-connection-profile-%s"
+	username = "ec2-user"
+	password = "%s"
+	ssh_port = 2222
+}
+`, rName, acctest.RandString(16))
+}
+
+func testAccCheckDeviceConnectionProfileExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("device_connection_profile Not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no device_connection_profile ID is set")
+		}
+
+		client := testAccProvider.Meta().(*goaviatrix.Client)
+
+		if _, err := client.GetDeviceConnectionProfile(context.Background(), rs.Primary.Attributes["name"]); err != nil {
+			return fmt.Errorf("device_connection_profile not found: %v", err)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDeviceConnectionProfileDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*goaviatrix.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aviatrix_device_connection_profile" {
+			continue
+		}
+		_, err := client.GetDeviceConnectionProfile(context.Background(), rs.Primary.Attributes["name"])
+		if err != goaviatrix.ErrNotFound {
+			return fmt.Errorf("device_connection_profile still exists")
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package aviatrix
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -73,7 +74,7 @@ func resourceAviatrixDeviceInterfaceConfigRead(d *schema.ResourceData, meta inte
 		name = id
 	}
 
-	device, err := client.GetDevice(&goaviatrix.Device{Name: name})
+	device, err := client.GetDevice(context.Background(), &goaviatrix.Device{Name: name})
 	if err == goaviatrix.ErrNotFound {
 		d.SetId("")
 		return nil
@@ -1,6 +1,7 @@
 package aviatrix
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -80,7 +81,7 @@ func testAccCheckDeviceInterfaceConfigExists(n string) resource.TestCheckFunc {
 
 		device := &goaviatrix.Device{Name: rs.Primary.Attributes["device_name"]}
 
-		device, err := client.GetDevice(device)
+		device, err := client.GetDevice(context.Background(), device)
 		if err != nil {
 			return err
 		}
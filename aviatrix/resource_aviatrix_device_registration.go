@@ -1,107 +1,279 @@
 package aviatrix
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAviatrixDeviceRegistration() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAviatrixDeviceRegistrationCreate,
-		Read:   resourceAviatrixDeviceRegistrationRead,
-		Update: resourceAviatrixDeviceRegistrationUpdate,
-		Delete: resourceAviatrixDeviceRegistrationDelete,
+		CreateContext: resourceAviatrixDeviceRegistrationCreate,
+		ReadContext:   resourceAviatrixDeviceRegistrationRead,
+		UpdateContext: resourceAviatrixDeviceRegistrationUpdate,
+		DeleteContext: resourceAviatrixDeviceRegistrationDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceAviatrixDeviceRegistrationImport,
+		},
+		CustomizeDiff: resourceAviatrixDeviceRegistrationCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Name of the device.",
+				Type:     schema.TypeString,
+				Required: true,
+				// the controller treats device names case-insensitively
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+				Description: "Name of the device. Case insensitive. Changing it renames the device in place.",
 			},
 			"public_ip": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.IsIPAddress,
-				Description:  "Public IP address of the device.",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: DiffSuppressFuncEqualIP,
+				ValidateFunc:     validateIPAddressNotHostname,
+				Description:      "Public IP address of the device. Can be updated in place, e.g. for devices with a dynamic public IP.",
+			},
+			"public_ip_ha": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: DiffSuppressFuncEqualIP,
+				ValidateFunc:     validateIPAddressNotHostname,
+				Description: "Public IP address of the second appliance of an HA pair, registered along with " +
+					"'public_ip' as one device. Must differ from 'public_ip'. Can be updated in place, adding " +
+					"or removing it registers the device again.",
 			},
 			"username": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Username to use to connect to the device.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"username", "connection_profile"},
+				ValidateFunc: validateSSHUsername,
+				Description: "Username to use to connect to the device. Must not contain whitespace. " +
+					"'root' is not allowed for 'aviatrix' devices. Required unless 'connection_profile' is set.",
 			},
-			"key_file": {
+			"connection_profile": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ExactlyOneOf: []string{"password", "key_file"},
-				Description:  "Path to private key file.",
+				AtLeastOneOf: []string{"username", "connection_profile"},
+				Description: "Name of an aviatrix_device_connection_profile to connect to the device with. Its " +
+					"username, credentials and SSH port override 'username', 'password', 'key_file', " +
+					"'key_file_content', 'key_passphrase' and 'ssh_port'.",
+			},
+			"key_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      envDefaultFunc("AVIATRIX_DEVICE_KEY_FILE"),
+				DiffSuppressFunc: suppressCredentialDiffAfterImport,
+				Description: "Path to private key file. Must be readable when planning. " +
+					"This attribute can also be set via environment variable 'AVIATRIX_DEVICE_KEY_FILE'. " +
+					"If both are set the value in the config file will be used.",
+			},
+			"key_file_content": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressCredentialDiffAfterImport,
+				Description:      "Content of the private key in PEM format. Use instead of 'key_file' to avoid writing the key to disk.",
 			},
 			"password": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DefaultFunc:      envDefaultFunc("AVIATRIX_DEVICE_PASSWORD"),
+				DiffSuppressFunc: suppressCredentialDiffAfterImport,
+				Description: "Password to connect to the device. " +
+					"This attribute can also be set via environment variable 'AVIATRIX_DEVICE_PASSWORD'. " +
+					"If both are set the value in the config file will be used.",
+			},
+			// no ConflictsWith 'password', it would fail configs with a password while the environment
+			// variable is set. The passphrase is not sent along with a password.
+			"key_passphrase": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Sensitive:   true,
-				DefaultFunc: envDefaultFunc("AVIATRIX_DEVICE_PASSWORD"),
-				Description: "Password to connect to the device. " +
-					"This attribute can also be set via environment variable 'AVIATRIX_DEVICE_PASSWORD'. " +
+				DefaultFunc: envDefaultFunc("AVIATRIX_DEVICE_KEY_PASSPHRASE"),
+				Description: "Passphrase for an encrypted private key file. Only used with 'key_file' or 'key_file_content'. " +
+					"This attribute can also be set via environment variable 'AVIATRIX_DEVICE_KEY_PASSPHRASE'. " +
 					"If both are set the value in the config file will be used.",
 			},
 			"host_os": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				Default:      "ios",
+				Computed:     true,
 				ForceNew:     true,
 				ValidateFunc: validation.StringInSlice([]string{"ios", "aviatrix"}, false),
-				Description:  "Device host OS. Default value is 'ios'. Valid values are 'ios' or 'aviatrix'.",
+				Description: "Device host OS. Valid values are 'ios' or 'aviatrix'. " +
+					"Defaults to the provider's 'default_device_host_os', which defaults to 'ios'.",
 			},
 			"ssh_port": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     22,
-				Description: "SSH port to use to connect to the device. Defaults to 22 if not set.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          defaultSSHPort,
+				ValidateFunc:     validation.IsPortNumber,
+				DiffSuppressFunc: DiffSuppressFuncDefaultSSHPort,
+				Description:      "SSH port to use to connect to the device. Defaults to 22 if not set.",
+			},
+			"mtu": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(576, 9000),
+				Description:  "MTU of the WAN uplink of the device. Must be between 576 and 9000. If not set, the controller's default is used.",
+			},
+			"bastion_ip": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"bastion_ip", "bastion_username", "bastion_port"},
+				ValidateFunc: validateIPAddressNotHostname,
+				Description:  "IP address of the bastion host the controller connects to the device through.",
+			},
+			"bastion_username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"bastion_ip", "bastion_username", "bastion_port"},
+				ValidateFunc: validateSSHUsername,
+				Description:  "Username to use to connect to the bastion host.",
+			},
+			"bastion_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"bastion_ip", "bastion_username", "bastion_port"},
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "SSH port to use to connect to the bastion host.",
 			},
 			"address_1": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Address line 1.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+				Description:      "Address line 1.",
 			},
 			"address_2": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Address line 2.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+				Description:      "Address line 2.",
 			},
 			"city": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "City",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+				Description:      "City",
 			},
 			"state": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "State",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+				Description:      "State",
 			},
 			"country": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "ISO two-letter country code.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateISOCountryCode,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.ToUpper(old) == strings.ToUpper(new)
+				},
+				Description: "ISO two-letter country code. Case insensitive.",
 			},
 			"zip_code": {
-				Type:        schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+				Description:      "Zip code.",
+			},
+			"address": {
+				Type:        schema.TypeList,
 				Optional:    true,
-				Description: "Zip code.",
+				MaxItems:    1,
+				Description: "Address of the device. Groups the flat address attributes, which must match it if both are set.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address_1": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Address line 1.",
+						},
+						"address_2": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Address line 2.",
+						},
+						"city": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "City",
+						},
+						"state": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "State",
+						},
+						"country": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateISOCountryCode,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return strings.ToUpper(old) == strings.ToUpper(new)
+							},
+							Description: "ISO two-letter country code. Case insensitive.",
+						},
+						"zip_code": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Zip code.",
+						},
+					},
+				},
 			},
 			"description": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Description.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateDeviceDescription,
+				DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+				Description:      "Description. At most 255 characters, without control characters such as newlines.",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description: "Region of the cloud the device runs in, used by the controller for CaaG devices in " +
+					"multi-region clouds. A warning is shown if it is not a known region of the detected cloud type.",
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"region"},
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Availability zone of the device within 'region'.",
 			},
 			"software_version": {
 				Type:     schema.TypeString,
@@ -111,50 +283,450 @@ func resourceAviatrixDeviceRegistration() *schema.Resource {
 					"If set, we will attempt to update the gateway to the specified version. " +
 					"If left blank, the gateway software version will continue to be managed through the aviatrix_controller_config resource.",
 			},
+			"refresh_version_on_read": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Query a CaaG for its running software version on every read, instead of using the version " +
+					"recorded by the controller. Detects upgrades made outside of Terraform.",
+			},
+			"current_software_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Software version currently running on the device.",
+			},
+			"upgrade_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Status of the last software upgrade of a CaaG, e.g. 'success', 'in_progress' or 'failed'. " +
+					"Empty for other devices or if the device was never upgraded.",
+			},
+			"allow_downgrade": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow 'software_version' to be set to a version older than the currently running version.",
+			},
+			"upgrade_window_start": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"upgrade_window_end"},
+				ValidateFunc: validateUpgradeWindowTime,
+				Description: "Start of the maintenance window for software upgrades. Either an RFC3339 timestamp for a " +
+					"single window, or a time of day in UTC in the format 'HH:MM' for a daily window.",
+			},
+			"upgrade_window_end": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"upgrade_window_start"},
+				ValidateFunc: validateUpgradeWindowTime,
+				Description:  "End of the maintenance window for software upgrades, in the same format as 'upgrade_window_start'.",
+			},
+			"account_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Name of the controller account to register the device under. Uses the controller's default if not set.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "A map of tags to assign to the device.",
+			},
+			"tag_json": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"tags", "tag"},
+				ValidateFunc:     validateTagJson,
+				DiffSuppressFunc: DiffSuppressFuncTagJson,
+				Description: "Tags to assign to the device as a JSON object of string keys and values. " +
+					"Sent to the controller as is, for values that contain commas or colons.",
+			},
+			"tag": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"tags"},
+				Description: "A tag to assign to the device, for keys that can not be written as keys of the " +
+					"'tags' map, e.g. with dots or colons. Can be repeated.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "Key of the tag.",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Value of the tag.",
+						},
+					},
+				},
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				Description: "A map of metadata labels to assign to the device on the controller. Unlike 'tags', " +
+					"labels are not applied to cloud resources.",
+			},
+			"force_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Detach all connections attached to the device before deregistering it.",
+			},
+			"credential_rotation_token": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Arbitrary value. Changing it sends the credentials to the controller again, " +
+					"e.g. after rotating the password of the device to the same value outside of Terraform.",
+			},
+			"reboot_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Arbitrary value. Changing it reboots the device and waits until it is connected " +
+					"to the controller again. Setting it when registering the device does not reboot it.",
+			},
+			"skip_reachability_check": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip checking that the controller can reach the device over SSH before registering it.",
+			},
+			"wait_for_connection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Wait until the device is connected to the controller after registering it, so " +
+					"resources attaching to the device do not fail while it is still connecting.",
+			},
 			"is_caag": {
 				Type:        schema.TypeBool,
 				Computed:    true,
 				Description: "Whether this device is a Managed CloudN device (CaaG)",
 			},
+			"cloud_type": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Type of cloud service provider the device runs in, as detected by the controller. 0 if unknown.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the device was registered. Empty if not reported by the controller.",
+			},
+			"registered_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Controller account that registered the device. Empty if not reported by the controller.",
+			},
+			"host_key_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Fingerprint of the SSH host key of the device. Empty if not reported by the controller.",
+			},
+			"model": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hardware or VM model of the device. Empty if not reported by the controller.",
+			},
+			"serial_number": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Serial number of the device. Empty if not reported by the controller.",
+			},
+			"managed_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the device was onboarded, e.g. 'terraform', 'ui' or 'api'. Empty if not reported by the controller.",
+			},
+			"connection_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the controller's connection to the device, e.g. 'connected' or 'disconnected'.",
+			},
 		},
 	}
 }
 
+// resourceAviatrixDeviceRegistrationImport imports a device by name, or by public IP with an ID of the
+// form 'ip:<public_ip>'. It restores the password from the 'AVIATRIX_DEVICE_PASSWORD' environment
+// variable, or else the key file from 'AVIATRIX_DEVICE_KEY_FILE', since credentials can not be read
+// back from the controller.
+func resourceAviatrixDeviceRegistrationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*goaviatrix.Client)
+
+	var device *goaviatrix.Device
+	if strings.HasPrefix(d.Id(), "ip:") {
+		publicIP := strings.TrimPrefix(d.Id(), "ip:")
+		var err error
+		device, err = client.GetDeviceByIP(ctx, publicIP)
+		if err != nil {
+			return nil, fmt.Errorf("could not find device with public IP %s: %v", publicIP, err)
+		}
+		d.SetId(device.Name)
+	} else if found, err := client.GetDevice(ctx, &goaviatrix.Device{Name: d.Id()}); err == nil {
+		// a device that is not found is reported by the read after the import
+		device = found
+	}
+	if device != nil && isManagedOutsideTerraform(device.ManagedBy) {
+		log.Printf("[WARN] device %s was onboarded through %s, not Terraform. Make sure it is no longer managed "+
+			"there, otherwise changes made there and by Terraform overwrite each other", device.Name, device.ManagedBy)
+	}
+	if password := os.Getenv("AVIATRIX_DEVICE_PASSWORD"); password != "" {
+		d.Set("password", password)
+	} else if keyFile := os.Getenv("AVIATRIX_DEVICE_KEY_FILE"); keyFile != "" {
+		d.Set("key_file", keyFile)
+	}
+	// arguments that only control the behavior of the provider are not stored on the controller
+	d.Set("allow_downgrade", false)
+	d.Set("force_delete", false)
+	d.Set("skip_reachability_check", false)
+	d.Set("refresh_version_on_read", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// isManagedOutsideTerraform returns true if the controller reports that a device was onboarded other
+// than through Terraform. Controllers that do not track the origin of devices report none.
+func isManagedOutsideTerraform(managedBy string) bool {
+	return managedBy != "" && !strings.EqualFold(managedBy, "terraform")
+}
+
+// suppressCredentialDiffAfterImport suppresses the removal of a credential of a registered device when
+// the configuration sets none, e.g. after an import that restored it from the environment, or for a
+// device using a connection profile. The device is already registered with working credentials, so
+// they are not cleared. A credential set in the configuration is always applied.
+func suppressCredentialDiffAfterImport(k, old, new string, d *schema.ResourceData) bool {
+	if d.Id() == "" || new != "" {
+		return false
+	}
+	// k itself is removed, d.GetChange would return its value in state since the config does not set it
+	for _, key := range deviceCredentialKeys {
+		if _, n := d.GetChange(key); key != k && n.(string) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// deviceCredentialKeys are the attributes holding the credentials of a device, exactly one must be set
+var deviceCredentialKeys = []string{"password", "key_file", "key_file_content"}
+
+// deviceCredentialEnvVars are the environment variables providing the defaults of credential attributes
+var deviceCredentialEnvVars = map[string]string{
+	"password": "AVIATRIX_DEVICE_PASSWORD",
+	"key_file": "AVIATRIX_DEVICE_KEY_FILE",
+}
+
+// deviceCredentials returns the credentials to register a device with, as a map of attribute to value.
+// Credentials set in the config take precedence over the defaults from environment variables, so
+// credentials from environment variables are only returned if none is set in the config. A credential is
+// assumed to come from its environment variable if it has the value of the variable.
+func deviceCredentials(get func(key string) string) map[string]string {
+	configured, fromEnv := map[string]string{}, map[string]string{}
+	for _, key := range deviceCredentialKeys {
+		value := get(key)
+		if value == "" {
+			continue
+		}
+		if envVar, ok := deviceCredentialEnvVars[key]; ok && value == os.Getenv(envVar) {
+			fromEnv[key] = value
+		} else {
+			configured[key] = value
+		}
+	}
+	if len(configured) > 0 {
+		return configured
+	}
+	return fromEnv
+}
+
+// validateDeviceCredentialSourceDiff checks that exactly one credential is set for the device, either
+// in the config or through its environment variable, and that a key file used is readable. Devices with
+// a connection profile use the credential of the profile.
+func validateDeviceCredentialSourceDiff(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("connection_profile") || d.Get("connection_profile").(string) != "" {
+		return nil
+	}
+	for _, key := range deviceCredentialKeys {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	credentials := deviceCredentials(func(key string) string {
+		return d.Get(key).(string)
+	})
+	switch len(credentials) {
+	case 0:
+		return fmt.Errorf("one of 'password', 'key_file' or 'key_file_content' must be set, either in the config " +
+			"or through the environment variable 'AVIATRIX_DEVICE_PASSWORD' or 'AVIATRIX_DEVICE_KEY_FILE'")
+	case 1:
+		// only the key file actually used must be readable, not one of the environment that is overridden
+		if keyFile, ok := credentials["key_file"]; ok {
+			return checkReadableFile("key_file", keyFile)
+		}
+		return nil
+	}
+	var keys []string
+	for key := range credentials {
+		keys = append(keys, "'"+key+"'")
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("only one of 'password', 'key_file' or 'key_file_content' can be set, got %s", strings.Join(keys, " and "))
+}
+
+// resolveDeviceConnectionProfile replaces the username, credentials and SSH port of device with the ones
+// of its 'connection_profile', if set.
+func resolveDeviceConnectionProfile(ctx context.Context, client *goaviatrix.Client, d *schema.ResourceData, device *goaviatrix.Device) error {
+	name := d.Get("connection_profile").(string)
+	if name == "" {
+		return nil
+	}
+	profile, err := client.GetDeviceConnectionProfile(ctx, name)
+	if err == goaviatrix.ErrNotFound {
+		return fmt.Errorf("connection profile %q does not exist", name)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get connection profile %q: %w", name, err)
+	}
+	profile.ApplyTo(device)
+	return nil
+}
+
 // marshalDeviceRegistrationInput marshals the ResourceData into a Device struct.
 func marshalDeviceRegistrationInput(d *schema.ResourceData) *goaviatrix.Device {
-	return &goaviatrix.Device{
-		Name:        d.Get("name").(string),
-		PublicIP:    d.Get("public_ip").(string),
-		Username:    d.Get("username").(string),
-		KeyFile:     d.Get("key_file").(string),
-		Password:    d.Get("password").(string),
-		HostOS:      d.Get("host_os").(string),
-		SshPort:     d.Get("ssh_port").(int),
-		SshPortStr:  strconv.Itoa(d.Get("ssh_port").(int)),
-		Address1:    d.Get("address_1").(string),
-		Address2:    d.Get("address_2").(string),
-		City:        d.Get("city").(string),
-		State:       d.Get("state").(string),
-		Country:     d.Get("country").(string),
-		ZipCode:     d.Get("zip_code").(string),
-		Description: d.Get("description").(string),
-	}
-}
-
-func resourceAviatrixDeviceRegistrationCreate(d *schema.ResourceData, meta interface{}) error {
+	device := &goaviatrix.Device{
+		Name:            d.Get("name").(string),
+		PublicIP:        d.Get("public_ip").(string),
+		PublicIPHa:      d.Get("public_ip_ha").(string),
+		Username:        d.Get("username").(string),
+		HostOS:          d.Get("host_os").(string),
+		SshPort:         d.Get("ssh_port").(int),
+		Mtu:             d.Get("mtu").(int),
+		BastionIP:       d.Get("bastion_ip").(string),
+		BastionUsername: d.Get("bastion_username").(string),
+		BastionPort:     d.Get("bastion_port").(int),
+		Address1:        d.Get("address_1").(string),
+		Address2:        d.Get("address_2").(string),
+		City:            d.Get("city").(string),
+		State:           d.Get("state").(string),
+		Country:         strings.ToUpper(d.Get("country").(string)),
+		ZipCode:         d.Get("zip_code").(string),
+		Description:     strings.TrimRightFunc(d.Get("description").(string), unicode.IsSpace),
+		AccountName:     d.Get("account_name").(string),
+		Region:          d.Get("region").(string),
+		Zone:            d.Get("zone").(string),
+	}
+	credentials := deviceCredentials(func(key string) string {
+		return d.Get(key).(string)
+	})
+	device.Password = credentials["password"]
+	device.KeyFile = credentials["key_file"]
+	device.KeyFileContent = credentials["key_file_content"]
+
+	// the address block takes precedence, the flat attributes can only repeat its values
+	if address := deviceAddressBlock(d.Get("address")); address != nil {
+		device.Address1 = address["address_1"].(string)
+		device.Address2 = address["address_2"].(string)
+		device.City = address["city"].(string)
+		device.State = address["state"].(string)
+		device.Country = strings.ToUpper(address["country"].(string))
+		device.ZipCode = address["zip_code"].(string)
+	}
+
+	if device.SshPort >= 1 && device.SshPort <= 65535 {
+		device.SshPortStr = strconv.Itoa(device.SshPort)
+	}
+
+	// The passphrase only applies to key based authentication, never send it along with a password.
+	if device.KeyFile != "" || device.KeyFileContent != "" {
+		device.KeyPassphrase = d.Get("key_passphrase").(string)
+	}
+
+	return device
+}
+
+func resourceAviatrixDeviceRegistrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*goaviatrix.Client)
 
 	device := marshalDeviceRegistrationInput(d)
+	if err := resolveDeviceConnectionProfile(ctx, client, d, device); err != nil {
+		return diag.Errorf("could not register device %s: %v", device.Name, err)
+	}
+	if device.HostOS == "" {
+		device.HostOS = client.DefaultDeviceHostOS
+	}
+	if device.HostOS == "" {
+		device.HostOS = "ios"
+	}
+	// the host OS may only be known now, if it is the provider's default or a reference
+	if err := validateDeviceHostOS(device.HostOS, device.Username, d.Get("software_version").(string)); err != nil {
+		return diag.Errorf("could not register device %s: %v", device.Name, err)
+	}
 
-	if err := client.RegisterDevice(device); err != nil {
-		return fmt.Errorf("could not register device: %v", err)
+	// fail fast if the device is unreachable, registration would otherwise only fail after a long timeout
+	// the controller can only reach a device behind a bastion host through the bastion
+	if !d.Get("skip_reachability_check").(bool) && device.BastionIP == "" {
+		if err := client.CheckDeviceReachable(ctx, device.PublicIP, device.SshPort); err != nil {
+			return diag.Errorf("could not register device %s: %v", device.Name, err)
+		}
+		if device.PublicIPHa != "" {
+			if err := client.CheckDeviceReachable(ctx, device.PublicIPHa, device.SshPort); err != nil {
+				return diag.Errorf("could not register device %s: %v", device.Name, err)
+			}
+		}
 	}
 
+	if device.AccountName != "" {
+		_, err := client.GetAccount(&goaviatrix.Account{AccountName: device.AccountName})
+		if err == goaviatrix.ErrNotFound {
+			return diag.Errorf("could not register device %s: account %q does not exist", device.Name, device.AccountName)
+		}
+		if err != nil {
+			return diag.Errorf("could not check account %q of device %s: %v", device.AccountName, device.Name, err)
+		}
+	}
+
+	if err := client.RegisterDevice(ctx, device); err != nil {
+		return diag.Errorf("could not register device %s: %v", device.Name, err)
+	}
 	d.SetId(device.Name)
-	return nil
+
+	// the controller may take a few seconds before it lists a newly registered device
+	registeredDevice, err := client.WaitForDevice(ctx, device, 15*time.Second)
+	if err != nil {
+		return diag.Errorf("device %s was registered but could not be read back: %v", device.Name, err)
+	}
+
+	if tags := deviceTagsInput(d, device.Name, registeredDevice.IsCaag); len(tags.Tags) > 0 {
+		if err := client.AddTags(tags); err != nil {
+			return diag.Errorf("could not add tags to device %s: %v", device.Name, err)
+		}
+	}
+	if labels := deviceLabelsInput(d, device.Name, registeredDevice.IsCaag); len(labels.Tags) > 0 {
+		if err := client.AddTags(labels); err != nil {
+			return diag.Errorf("could not add labels to device %s: %v", device.Name, err)
+		}
+	}
+
+	if d.Get("wait_for_connection").(bool) {
+		log.Printf("[INFO] waiting for device %s to be connected after registration", device.Name)
+		if err := client.WaitForDeviceConnected(ctx, device.Name, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.Errorf("device %s was registered but is not connected: %v", device.Name, err)
+		}
+		log.Printf("[INFO] device %s is connected", device.Name)
+	}
+
+	// the cloud type is only detected by the controller once the device is registered
+	diags := deviceRegionWarning(d, registeredDevice.CloudType)
+	return append(diags, resourceAviatrixDeviceRegistrationRead(ctx, d, meta)...)
 }
 
-func resourceAviatrixDeviceRegistrationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceAviatrixDeviceRegistrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*goaviatrix.Client)
 
 	name := d.Get("name").(string)
@@ -166,69 +738,968 @@ func resourceAviatrixDeviceRegistrationRead(d *schema.ResourceData, meta interfa
 	}
 
 	device := &goaviatrix.Device{
-		Name: name,
+		Name:        name,
+		AccountName: d.Get("account_name").(string),
 	}
 
-	device, err := client.GetDevice(device)
+	device, err := client.GetDevice(ctx, device)
+	if err == goaviatrix.ErrNotFound {
+		// The device may have been renamed outside of Terraform. Look it up by its public IP, so the
+		// rename shows up as a change of 'name' instead of the device being removed from state.
+		if publicIP := d.Get("public_ip").(string); publicIP != "" {
+			renamedDevice, ipErr := client.GetDeviceByIP(ctx, publicIP)
+			if ipErr == nil {
+				log.Printf("[WARN] device %s was renamed to %s outside of Terraform", name, renamedDevice.Name)
+				device, err = renamedDevice, nil
+				name = renamedDevice.Name
+			} else if ipErr != goaviatrix.ErrNotFound {
+				log.Printf("[WARN] could not look up device %s by public IP %s: %v", name, publicIP, ipErr)
+			}
+		}
+	}
 	if err == goaviatrix.ErrNotFound {
 		d.SetId("")
 		return nil
 	}
 	if err != nil {
-		return fmt.Errorf("could not find device %s: %v", name, err)
+		return diag.Errorf("could not find device %s: %v", name, err)
 	}
 
-	d.Set("name", device.Name)
+	// keep the casing of the name in state, if the controller reports it differently
+	if !strings.EqualFold(device.Name, d.Get("name").(string)) {
+		d.Set("name", device.Name)
+	}
 	d.Set("public_ip", device.PublicIP)
-	d.Set("username", device.Username)
-	d.Set("host_os", device.HostOS)
-	d.Set("ssh_port", device.SshPort)
-	d.Set("address_1", device.Address1)
-	d.Set("address_2", device.Address2)
-	d.Set("city", device.City)
-	d.Set("state", device.State)
-	d.Set("country", device.Country)
-	d.Set("zip_code", device.ZipCode)
+	if device.PublicIPHa != "" {
+		d.Set("public_ip_ha", device.PublicIPHa)
+	}
+	// the username and SSH port of a device with a connection profile are managed by the profile
+	if d.Get("connection_profile").(string) == "" {
+		d.Set("username", device.Username)
+		d.Set("ssh_port", sshPortOrDefault(device.SshPort))
+	}
+	// some controller versions return the host OS in upper case
+	d.Set("host_os", strings.ToLower(device.HostOS))
+	if device.Mtu != 0 {
+		d.Set("mtu", device.Mtu)
+	}
+	if device.BastionIP != "" {
+		d.Set("bastion_ip", device.BastionIP)
+		d.Set("bastion_username", device.BastionUsername)
+		d.Set("bastion_port", device.BastionPort)
+	}
+	address := map[string]interface{}{
+		"address_1": device.Address1,
+		"address_2": device.Address2,
+		"city":      device.City,
+		"state":     device.State,
+		"country":   device.Country,
+		"zip_code":  device.ZipCode,
+	}
+	if deviceAddressBlock(d.Get("address")) != nil {
+		if err := d.Set("address", []interface{}{address}); err != nil {
+			return diag.Errorf("could not set address of device %s: %v", name, err)
+		}
+		// only keep the flat address attributes that are set along with the address block
+		for key, value := range address {
+			if d.Get(key).(string) != "" {
+				d.Set(key, value)
+			}
+		}
+	} else {
+		for key, value := range address {
+			d.Set(key, value)
+		}
+	}
 	d.Set("description", device.Description)
-	d.Set("software_version", device.SoftwareVersion)
+	if d.Get("refresh_version_on_read").(bool) && device.IsCaag {
+		softwareVersion, err := client.RefreshDeviceSoftwareVersion(ctx, device.Name)
+		if err != nil {
+			return diag.Errorf("could not refresh software version of device %s: %v", name, err)
+		}
+		if softwareVersion != "" {
+			device.SoftwareVersion = softwareVersion
+		}
+	}
+	// Keep the desired software_version while the device runs it, e.g. any build of a release only
+	// version. Otherwise store the running version, so the plan shows the difference to the desired one.
+	if !goaviatrix.SoftwareVersionMatches(device.SoftwareVersion, d.Get("software_version").(string)) {
+		d.Set("software_version", device.SoftwareVersion)
+	}
+	d.Set("current_software_version", device.SoftwareVersion)
 	d.Set("is_caag", device.IsCaag)
+	if isCaagHostOSMismatch(device.IsCaag, device.HostOS) {
+		log.Printf("[WARN] controller reports device %s as a Managed CloudN (CaaG) device, but its host OS is %q. "+
+			"Only 'aviatrix' devices can be CaaG, check the controller and provider versions", name, device.HostOS)
+	}
+	if device.IsCaag {
+		// the upgrade status is informational, keep the last known one if it can not be read
+		upgradeStatus, err := client.GetGatewayUpgradeStatus(ctx, device.Name)
+		if err != nil {
+			log.Printf("[WARN] could not get upgrade status of device %s: %v", name, err)
+		} else {
+			d.Set("upgrade_status", upgradeStatus)
+		}
+	} else {
+		d.Set("upgrade_status", "")
+	}
+	d.Set("cloud_type", device.CloudType)
+	d.Set("created_at", device.CreatedAt)
+	d.Set("registered_by", device.RegisteredBy)
+	if device.AccountName != "" {
+		d.Set("account_name", device.AccountName)
+	}
+	if device.Region != "" {
+		d.Set("region", device.Region)
+		d.Set("zone", device.Zone)
+	}
 
-	d.SetId(device.Name)
-	return nil
+	var diags diag.Diagnostics
+	oldFingerprint := d.Get("host_key_fingerprint").(string)
+	if oldFingerprint != "" && device.HostKeyFingerprint != "" && oldFingerprint != device.HostKeyFingerprint {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("SSH host key fingerprint of device %s changed", name),
+			Detail: fmt.Sprintf("The fingerprint changed from %s to %s, the device may have been replaced. "+
+				"Check the device before applying further changes to it.", oldFingerprint, device.HostKeyFingerprint),
+		})
+	}
+	d.Set("host_key_fingerprint", device.HostKeyFingerprint)
+	d.Set("model", device.Model)
+	d.Set("serial_number", device.SerialNumber)
+	d.Set("managed_by", device.ManagedBy)
+
+	tags, err := client.GetTagsMap(goaviatrix.NewDeviceTags(device.Name, device.IsCaag, nil))
+	if err == goaviatrix.ErrNotFound {
+		// only the device lookup above decides whether the device exists
+		log.Printf("[WARN] controller found no tags for device %s, clearing its tags", name)
+		tags, err = map[string]string{}, nil
+	}
+	if err != nil {
+		if len(deviceTagsInput(d, device.Name, device.IsCaag).Tags) > 0 {
+			return diag.Errorf("could not get tags of device %s: %v", name, err)
+		}
+		log.Printf("[WARN] could not get tags of device %s: %v", name, err)
+	} else if d.Get("tag_json").(string) != "" {
+		tagJson, err := TagsMapToJson(tags)
+		if err != nil {
+			return diag.Errorf("could not set tag_json of device %s: %v", name, err)
+		}
+		d.Set("tag_json", tagJson)
+	} else if d.Get("tag").(*schema.Set).Len() > 0 {
+		if err := d.Set("tag", tagBlocksFromMap(tags)); err != nil {
+			return diag.Errorf("could not set tag of device %s: %v", name, err)
+		}
+	} else if err := d.Set("tags", tags); err != nil {
+		return diag.Errorf("could not set tags of device %s: %v", name, err)
+	}
+
+	// only read labels when they are managed, not all controller versions support them
+	if labels := deviceLabelsInput(d, device.Name, device.IsCaag); len(labels.Tags) > 0 {
+		labelsMap, err := client.GetTagsMap(labels)
+		if err != nil {
+			return diag.Errorf("could not get labels of device %s: %v", name, err)
+		}
+		if err := d.Set("labels", labelsMap); err != nil {
+			return diag.Errorf("could not set labels of device %s: %v", name, err)
+		}
+	}
+
+	// the connection status is informational, keep the last known one if it can not be read
+	connectionStatus, err := client.GetDeviceConnectionStatus(ctx, device)
+	if err != nil {
+		log.Printf("[WARN] could not get connection status of device %s: %v", name, err)
+	} else {
+		d.Set("connection_status", connectionStatus)
+	}
+
+	d.SetId(d.Get("name").(string))
+	return diags
 }
 
-func resourceAviatrixDeviceRegistrationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceAviatrixDeviceRegistrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*goaviatrix.Client)
 
+	if d.HasChange("software_version") && !d.Get("allow_downgrade").(bool) {
+		// compare with the version read from the device, it may have been upgraded outside of Terraform
+		oldVersion, newVersion := d.GetChange("software_version")
+		currentVersion := d.Get("current_software_version").(string)
+		if currentVersion == "" {
+			currentVersion = oldVersion.(string)
+		}
+		if isDowngrade(currentVersion, newVersion.(string)) {
+			return diag.Errorf("'software_version' %s is older than the current version %s of the device, "+
+				"set 'allow_downgrade' to true to downgrade the device", newVersion, currentVersion)
+		}
+	}
+
 	device := marshalDeviceRegistrationInput(d)
 
-	if err := client.UpdateDevice(device); err != nil {
-		return fmt.Errorf("could not update device registration information: %v", err)
+	if d.HasChange("name") {
+		oldName, _ := d.GetChange("name")
+		if err := renameDevice(ctx, client, d, oldName.(string), device); err != nil {
+			return diag.Errorf("could not rename device %s to %s: %v", oldName, device.Name, err)
+		}
+		d.SetId(device.Name)
+	}
+
+	// only send the registration information when it changed, e.g. not for changes of tags only
+	// a new credential_rotation_token pushes the credentials again, even if they did not change
+	// changes of the metadata only are sent without the connection information, so the controller
+	// does not connect to the device again
+	if d.HasChanges(deviceConnectionKeys...) {
+		if err := resolveDeviceConnectionProfile(ctx, client, d, device); err != nil {
+			return diag.Errorf("could not update device registration information: %v", err)
+		}
+		err := client.UpdateDevice(ctx, device)
+		// Some controller versions do not allow changing the public IPs of a registered device, in that
+		// case register the device again with the new public IPs. Other errors, e.g. of a controller that
+		// is briefly unavailable, must not deregister the device.
+		if errors.Is(err, goaviatrix.ErrDevicePublicIPUpdateNotSupported) && d.HasChanges("public_ip", "public_ip_ha") {
+			log.Printf("[WARN] %v, registering device %s again with its new public IPs", err, device.Name)
+			err = registerDeviceAgain(ctx, client, d, device.Name, device)
+			if errors.Is(err, errDeviceNotRegisteredAgain) {
+				// the device no longer exists on the controller, so the next plan registers it again
+				d.SetId("")
+			}
+		}
+		if err != nil {
+			return diag.Errorf("could not update device registration information: %v", err)
+		}
+	} else if d.HasChanges(deviceMetadataKeys...) {
+		if err := updateDeviceMetadata(ctx, client, d, device); err != nil {
+			return diag.Errorf("could not update device registration information: %v", err)
+		}
+	}
+
+	if d.HasChanges("tags", "tag_json", "tag") {
+		tags := deviceTagsInput(d, device.Name, d.Get("is_caag").(bool))
+		oldTags, _ := d.GetChange("tags")
+		oldTagsMap := tagsMapFromInterface(oldTags)
+		if oldTagJson, _ := d.GetChange("tag_json"); oldTagJson.(string) != "" {
+			oldTagJsonMap, _ := parseTagJson(oldTagJson.(string))
+			for key, val := range oldTagJsonMap {
+				oldTagsMap[key] = val
+			}
+		}
+		oldTagBlocks, _ := d.GetChange("tag")
+		oldTagBlocksMap, _ := tagBlocksToMap(oldTagBlocks.(*schema.Set).List())
+		for key, val := range oldTagBlocksMap {
+			oldTagsMap[key] = val
+		}
+		logTagsDiff("device "+device.Name, oldTagsMap, tags.Tags)
+
+		var err error
+		if len(tags.Tags) == 0 {
+			var keys []string
+			for key := range oldTagsMap {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			tags.VerifyDelete = true
+			err = client.DeleteTagsByKeys(tags, keys)
+		} else {
+			tags.ReplaceAll = true
+			err = client.UpdateTags(tags)
+		}
+		if err != nil {
+			return diag.Errorf("could not update tags of device %s: %v", device.Name, err)
+		}
 	}
 
+	if d.HasChange("labels") {
+		labels := deviceLabelsInput(d, device.Name, d.Get("is_caag").(bool))
+		var err error
+		if len(labels.Tags) == 0 {
+			oldLabels, _ := d.GetChange("labels")
+			var keys []string
+			for key := range tagsMapFromInterface(oldLabels) {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			err = client.DeleteTagsByKeys(labels, keys)
+		} else {
+			labels.ReplaceAll = true
+			err = client.UpdateTags(labels)
+		}
+		if err != nil {
+			return diag.Errorf("could not update labels of device %s: %v", device.Name, err)
+		}
+	}
+
+	var diags diag.Diagnostics
+	upgradeNeeded, upgradeDeferred := false, false
 	if d.HasChange("software_version") {
-		isCaag := d.Get("is_caag").(bool)
-		if !isCaag {
-			return fmt.Errorf("'software_version' can only be updated for managed cloudN (CaaG) devices")
+		if !d.Get("is_caag").(bool) {
+			return diag.Errorf("'software_version' can only be updated for managed cloudN (CaaG) devices")
+		}
+		// e.g. after an upgrade outside of Terraform, upgrading again would only restart the device
+		currentVersion, softwareVersion := d.Get("current_software_version").(string), d.Get("software_version").(string)
+		upgradeNeeded = isUpgradeNeeded(currentVersion, softwareVersion)
+		if !upgradeNeeded {
+			log.Printf("[INFO] CaaG %s already runs software version %s, not upgrading it to %s",
+				device.Name, currentVersion, softwareVersion)
+		}
+	}
+	if upgradeNeeded {
+		if start, end := d.Get("upgrade_window_start").(string), d.Get("upgrade_window_end").(string); start != "" {
+			inWindow, err := inUpgradeWindow(start, end, time.Now())
+			if err != nil {
+				return diag.Errorf("could not check maintenance window of device %s: %v", device.Name, err)
+			}
+			upgradeDeferred = !inWindow
 		}
+	}
+	if upgradeDeferred {
+		// keep the running version, so the plan shows the upgrade again until it happens in the window
+		oldVersion, newVersion := d.GetChange("software_version")
+		d.Set("software_version", oldVersion)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("upgrade of CaaG %s to software version %s is pending", device.Name, newVersion),
+			Detail: fmt.Sprintf("The current time is outside the maintenance window from %s to %s. Apply again "+
+				"during the window to upgrade the device.", d.Get("upgrade_window_start"), d.Get("upgrade_window_end")),
+		})
+	} else if upgradeNeeded {
 		softwareVersion := d.Get("software_version").(string)
-		err := client.UpgradeGateway(&goaviatrix.Gateway{GwName: device.Name, SoftwareVersion: softwareVersion})
+		// the cached devices report the old version from the start of the upgrade until after it finished
+		client.InvalidateDeviceCache()
+		defer client.InvalidateDeviceCache()
+		err := client.UpgradeGatewayContext(ctx, &goaviatrix.Gateway{GwName: device.Name, SoftwareVersion: softwareVersion})
 		if err != nil {
-			return fmt.Errorf("could not upgrade CaaG: %v", err)
+			if errors.Is(err, context.Canceled) {
+				// the controller may have started the upgrade before the request was aborted
+				setDeviceUpgradeIncomplete(d, "in_progress")
+				return append(diags, diag.Errorf("upgrade of CaaG %s was interrupted and may still be in progress "+
+					"on the controller: %v", device.Name, err)...)
+			}
+			setDeviceUpgradeIncomplete(d, d.Get("upgrade_status").(string))
+			return append(diags, diag.Errorf("could not upgrade CaaG: %v", err)...)
+		}
+		if softwareVersion == "latest" || softwareVersion == "previous" {
+			log.Printf("[INFO] not waiting for CaaG %s to report software version %q", device.Name, softwareVersion)
+		} else {
+			log.Printf("[INFO] waiting for CaaG %s to report software version %s", device.Name, softwareVersion)
+			err := client.WaitForGatewayVersion(ctx, device.Name, softwareVersion, d.Timeout(schema.TimeoutUpdate))
+			switch {
+			case errors.Is(err, goaviatrix.ErrGatewayUpgradeFailed):
+				setDeviceUpgradeIncomplete(d, "failed")
+				return append(diags, diag.Errorf("could not upgrade CaaG: %v", err)...)
+			case errors.Is(err, context.Canceled):
+				setDeviceUpgradeIncomplete(d, "in_progress")
+				return append(diags, diag.Errorf("stopped waiting for the upgrade of CaaG %s, which may still be in "+
+					"progress on the controller: %v", device.Name, err)...)
+			case err != nil:
+				setDeviceUpgradeIncomplete(d, "in_progress")
+				return append(diags, diag.Errorf("could not verify upgrade of CaaG: %v", err)...)
+			}
+		}
+	}
+
+	if d.HasChange("reboot_trigger") {
+		if err := rebootDevice(ctx, client, device.Name, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			// keep the previous trigger, so the plan shows the reboot again
+			oldTrigger, _ := d.GetChange("reboot_trigger")
+			d.Set("reboot_trigger", oldTrigger)
+			return append(diags, diag.FromErr(err)...)
 		}
 	}
 
 	d.SetId(device.Name)
+	return diags
+}
+
+// setDeviceUpgradeIncomplete keeps the software version the device ran before the upgrade in the state,
+// so the plan shows the upgrade again, and records the status of the upgrade that did not complete.
+// The next refresh reads the actual version and status from the controller.
+func setDeviceUpgradeIncomplete(d *schema.ResourceData, upgradeStatus string) {
+	oldVersion, _ := d.GetChange("software_version")
+	d.Set("software_version", oldVersion)
+	d.Set("upgrade_status", upgradeStatus)
+}
+
+// deviceConnectionKeys are the attributes the controller uses to connect to a device. Changing any of
+// them updates the device with all of its registration information.
+var deviceConnectionKeys = []string{"public_ip", "public_ip_ha", "username", "key_file", "key_file_content", "password",
+	"key_passphrase", "credential_rotation_token", "ssh_port", "connection_profile", "mtu"}
+
+// deviceMetadataKeys are the attributes only stored on the controller, which can be updated without
+// connecting to the device
+var deviceMetadataKeys = []string{"address_1", "address_2", "city", "state", "country", "zip_code", "address", "description"}
+
+// updateDeviceMetadata updates the address and description of the registered device. If the controller
+// can not update them alone, the device is updated with all of its registration information instead.
+func updateDeviceMetadata(ctx context.Context, client *goaviatrix.Client, d *schema.ResourceData, device *goaviatrix.Device) error {
+	err := client.UpdateDeviceMetadata(ctx, device)
+	if err != goaviatrix.ErrDeviceMetadataUpdateNotSupported {
+		return err
+	}
+
+	log.Printf("[WARN] %v, updating device %s with its connection information", err, device.Name)
+	if err := resolveDeviceConnectionProfile(ctx, client, d, device); err != nil {
+		return err
+	}
+	return client.UpdateDevice(ctx, device)
+}
+
+// renameDevice renames the registered device oldName to the name of device. If the controller can not
+// rename devices, the device is deregistered and registered again under the new name instead, which
+// loses its connections, so its tags and labels are added again.
+func renameDevice(ctx context.Context, client *goaviatrix.Client, d *schema.ResourceData, oldName string, device *goaviatrix.Device) error {
+	err := client.RenameDevice(ctx, oldName, device.Name)
+	if err != goaviatrix.ErrDeviceRenameNotSupported {
+		return err
+	}
+
+	log.Printf("[WARN] %v, registering device %s again as %s", err, oldName, device.Name)
+	if err := resolveDeviceConnectionProfile(ctx, client, d, device); err != nil {
+		return err
+	}
+	return registerDeviceAgain(ctx, client, d, oldName, device)
+}
+
+// errDeviceNotRegisteredAgain is returned by registerDeviceAgain if the device was deregistered, but
+// could not be registered again
+var errDeviceNotRegisteredAgain = errors.New("device was deregistered but could not be registered again")
+
+// registerDeviceAgain deregisters the device oldName and registers device, for changes the controller
+// can not make to a registered device. This loses the connections of the device, so its tags and labels
+// are added again. The address and description are sent with the registration.
+func registerDeviceAgain(ctx context.Context, client *goaviatrix.Client, d *schema.ResourceData, oldName string, device *goaviatrix.Device) error {
+	if err := client.DeregisterDevice(ctx, &goaviatrix.Device{Name: oldName}); err != nil {
+		return fmt.Errorf("could not deregister device: %v", err)
+	}
+	if err := client.RegisterDevice(ctx, device); err != nil {
+		return fmt.Errorf("%w: %v", errDeviceNotRegisteredAgain, err)
+	}
+
+	registeredDevice, err := client.WaitForDevice(ctx, device, 15*time.Second)
+	if err != nil {
+		return fmt.Errorf("device was registered but could not be read back: %v", err)
+	}
+	if tags := deviceTagsInput(d, device.Name, registeredDevice.IsCaag); len(tags.Tags) > 0 {
+		if err := client.AddTags(tags); err != nil {
+			return fmt.Errorf("could not add tags: %v", err)
+		}
+	}
+	if labels := deviceLabelsInput(d, device.Name, registeredDevice.IsCaag); len(labels.Tags) > 0 {
+		if err := client.AddTags(labels); err != nil {
+			return fmt.Errorf("could not add labels: %v", err)
+		}
+	}
+	return nil
+}
+
+// rebootDevice reboots the device and waits until it is connected to the controller again
+func rebootDevice(ctx context.Context, client *goaviatrix.Client, name string, timeout time.Duration) error {
+	log.Printf("[INFO] Rebooting device %s", name)
+	if err := client.RebootDevice(ctx, name); err != nil {
+		return fmt.Errorf("could not reboot device %s: %v", name, err)
+	}
+	log.Printf("[INFO] waiting for device %s to be connected after reboot", name)
+	if err := client.WaitForDeviceConnected(ctx, name, timeout); err != nil {
+		return fmt.Errorf("could not verify reboot of device %s: %v", name, err)
+	}
+	return nil
+}
+
+func resourceAviatrixDeviceRegistrationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateDeviceCredentialSourceDiff(d); err != nil {
+		return err
+	}
+	if err := validateDeviceSoftwareVersionDiff(d, meta); err != nil {
+		return err
+	}
+	if err := validateDeviceAddressDiff(d); err != nil {
+		return err
+	}
+	if err := validateDeviceCredentialsDiff(d); err != nil {
+		return err
+	}
+	if err := validateDeviceUsernameHostOSDiff(d, meta); err != nil {
+		return err
+	}
+	if err := validateDeviceTagsDiff(d); err != nil {
+		return err
+	}
+	if err := validateDeviceUpgradeWindowDiff(d); err != nil {
+		return err
+	}
+	if err := validateDevicePublicIPHaDiff(d); err != nil {
+		return err
+	}
+	return validateDeviceZipCodeDiff(d)
+}
+
+// validateDeviceUpgradeWindowDiff rejects maintenance windows whose bounds mix timestamps and times of day
+func validateDeviceUpgradeWindowDiff(d *schema.ResourceDiff) error {
+	start, end := d.Get("upgrade_window_start").(string), d.Get("upgrade_window_end").(string)
+	if start == "" || end == "" || !d.NewValueKnown("upgrade_window_start") || !d.NewValueKnown("upgrade_window_end") {
+		return nil
+	}
+	_, err := inUpgradeWindow(start, end, time.Now())
+	return err
+}
+
+// validateDeviceTagsDiff validates the tags of a registered device against the constraints of the
+// cloud provider the controller detected for the device. Tags of devices without a detected cloud
+// type are not validated.
+func validateDeviceTagsDiff(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("tags") || !d.NewValueKnown("tag_json") || !d.NewValueKnown("tag") {
+		return nil
+	}
+
+	tags := tagsMapFromInterface(d.Get("tags"))
+	if tagJson := d.Get("tag_json").(string); tagJson != "" {
+		// an invalid 'tag_json' is reported by its ValidateFunc
+		tags, _ = parseTagJson(tagJson)
+	}
+	if tagBlocks := d.Get("tag").(*schema.Set).List(); len(tagBlocks) > 0 {
+		var err error
+		if tags, err = tagBlocksToMap(tagBlocks); err != nil {
+			return err
+		}
+	}
+
+	cloudType := d.Get("cloud_type").(int)
+	if cloudType == 0 {
+		return nil
+	}
+	if err := validateTags(tags, cloudType); err != nil {
+		return fmt.Errorf("invalid tags for device in cloud type %d: %v", cloudType, err)
+	}
+	return nil
+}
+
+// knownDeviceRegions are the regions a device can be registered in, per cloud type
+var knownDeviceRegions = map[int][]string{
+	goaviatrix.AWS: {
+		"us-east-1", "us-east-2", "us-west-1", "us-west-2", "af-south-1", "ap-east-1", "ap-south-1",
+		"ap-northeast-1", "ap-northeast-2", "ap-northeast-3", "ap-southeast-1", "ap-southeast-2",
+		"ca-central-1", "eu-central-1", "eu-west-1", "eu-west-2", "eu-west-3", "eu-south-1",
+		"eu-north-1", "me-south-1", "sa-east-1",
+	},
+	goaviatrix.Azure: {
+		"eastus", "eastus2", "centralus", "northcentralus", "southcentralus", "westcentralus", "westus",
+		"westus2", "westus3", "canadacentral", "canadaeast", "brazilsouth", "northeurope", "westeurope",
+		"uksouth", "ukwest", "francecentral", "germanywestcentral", "norwayeast", "switzerlandnorth",
+		"swedencentral", "eastasia", "southeastasia", "japaneast", "japanwest", "koreacentral",
+		"australiaeast", "australiasoutheast", "centralindia", "southindia", "uaenorth", "southafricanorth",
+	},
+	goaviatrix.GCP: {
+		"us-central1", "us-east1", "us-east4", "us-west1", "us-west2", "us-west3", "us-west4",
+		"northamerica-northeast1", "northamerica-northeast2", "southamerica-east1", "europe-north1",
+		"europe-west1", "europe-west2", "europe-west3", "europe-west4", "europe-west6", "europe-central2",
+		"asia-east1", "asia-east2", "asia-northeast1", "asia-northeast2", "asia-northeast3",
+		"asia-south1", "asia-southeast1", "asia-southeast2", "australia-southeast1",
+	},
+	goaviatrix.OCI: {
+		"us-ashburn-1", "us-phoenix-1", "us-sanjose-1", "ca-toronto-1", "ca-montreal-1",
+		"sa-saopaulo-1", "uk-london-1", "eu-frankfurt-1", "eu-amsterdam-1", "eu-zurich-1",
+		"ap-tokyo-1", "ap-osaka-1", "ap-seoul-1", "ap-mumbai-1", "ap-sydney-1", "ap-singapore-1",
+		"me-dubai-1", "me-jeddah-1",
+	},
+}
+
+// validateDeviceRegion checks region and zone against the known regions of cloudType. The regions of
+// other cloud types, e.g. AWSGov or the China clouds, and of devices without a detected cloud type are
+// not checked. The list of known regions lags behind the clouds, so a failed check is only a warning.
+func validateDeviceRegion(region, zone string, cloudType int) error {
+	known, ok := knownDeviceRegions[cloudType]
+	if region == "" || !ok {
+		return nil
+	}
+	if !goaviatrix.Contains(known, region) {
+		return fmt.Errorf("unknown region %q for cloud type %d", region, cloudType)
+	}
+	if zone == "" {
+		return nil
+	}
+	switch cloudType {
+	case goaviatrix.Azure:
+		// Azure availability zones are numbered within the region
+		if zone != "1" && zone != "2" && zone != "3" {
+			return fmt.Errorf("invalid zone %q for region %q: must be 1, 2 or 3", zone, region)
+		}
+	case goaviatrix.OCI:
+		// OCI availability domains are not prefixed by the region
+	default:
+		if !strings.HasPrefix(zone, region) || zone == region {
+			return fmt.Errorf("invalid zone %q for region %q: must start with the region name", zone, region)
+		}
+	}
 	return nil
 }
 
-func resourceAviatrixDeviceRegistrationDelete(d *schema.ResourceData, meta interface{}) error {
+// deviceRegionWarning returns a warning if 'region' or 'zone' of the device are not known for the cloud
+// type the controller detected for it
+func deviceRegionWarning(d *schema.ResourceData, cloudType int) diag.Diagnostics {
+	if err := validateDeviceRegion(d.Get("region").(string), d.Get("zone").(string), cloudType); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("region of device %s may be invalid", d.Get("name")),
+			Detail:   fmt.Sprintf("%v. Check the region if the controller does not use it for the device.", err),
+		}}
+	}
+	return nil
+}
+
+// validateDevicePublicIPHa checks that the two appliances of an HA pair have different public IPs.
+// The IPs themselves are validated by the ValidateFunc of the attributes.
+func validateDevicePublicIPHa(publicIP, publicIPHa string) error {
+	if publicIPHa == "" {
+		return nil
+	}
+	if ip := net.ParseIP(publicIP); ip != nil && ip.Equal(net.ParseIP(publicIPHa)) {
+		return fmt.Errorf("public_ip_ha must differ from public_ip, got %s for both appliances of the HA pair", publicIPHa)
+	}
+	return nil
+}
+
+// validateDevicePublicIPHaDiff validates 'public_ip_ha' and registers the device again when it is added
+// or removed, since the controller can not turn a registered device into an HA pair or back.
+func validateDevicePublicIPHaDiff(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("public_ip") || !d.NewValueKnown("public_ip_ha") {
+		return nil
+	}
+	if err := validateDevicePublicIPHa(d.Get("public_ip").(string), d.Get("public_ip_ha").(string)); err != nil {
+		return err
+	}
+	if oldIP, newIP := d.GetChange("public_ip_ha"); d.Id() != "" && (oldIP.(string) == "") != (newIP.(string) == "") {
+		return d.ForceNew("public_ip_ha")
+	}
+	return nil
+}
+
+// validateDeviceCredentialsDiff requires a credential when 'username' of a registered device changes,
+// or when 'credential_rotation_token' changes to push the credentials again. Without one the update
+// would be sent without credentials and fail on the device.
+func validateDeviceCredentialsDiff(d *schema.ResourceDiff) error {
+	if d.Id() == "" || !d.NewValueKnown("connection_profile") || d.Get("connection_profile").(string) != "" {
+		return nil
+	}
+	var changed string
+	switch {
+	case d.HasChange("username"):
+		changed = "username"
+	case d.HasChange("credential_rotation_token"):
+		changed = "credential_rotation_token"
+	default:
+		return nil
+	}
+	for _, key := range []string{"password", "key_file", "key_file_content"} {
+		if !d.NewValueKnown(key) || d.Get(key).(string) != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("changing '%s' requires one of 'password', 'key_file' or 'key_file_content' "+
+		"to be set for the user", changed)
+}
+
+// validateDeviceSoftwareVersionDiff rejects 'software_version' at plan time for 'ios' devices,
+// which are never managed CloudN (CaaG) devices. For 'aviatrix' devices 'is_caag' is only known after
+// registration, so that case is still checked during apply, as are a 'software_version' or 'host_os'
+// only known after apply, which d.Get returns as empty. The host OS of a new device that does not set
+// it is also only known after apply, see validateDeviceHostOS.
+func validateDeviceSoftwareVersionDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("software_version") || !d.HasChange("software_version") || d.Get("software_version").(string) == "" {
+		return nil
+	}
+
+	hostOS, known := deviceHostOSDiff(d, meta)
+	if !known {
+		return nil
+	}
+	return validateDeviceHostOS(hostOS, "", d.Get("software_version").(string))
+}
+
+// validateDeviceHostOS checks the username and software version against the host OS of the device.
+// Create checks them again once the host OS is known, e.g. the provider's default.
+func validateDeviceHostOS(hostOS, username, softwareVersion string) error {
+	if softwareVersion != "" && (hostOS == "" || hostOS == "ios") {
+		return fmt.Errorf("'software_version' can only be set for managed CloudN (CaaG) devices, " +
+			"which have 'host_os' set to 'aviatrix'")
+	}
+	if username == "root" && hostOS == "aviatrix" {
+		return fmt.Errorf("'username' can not be 'root' for devices with 'host_os' set to 'aviatrix'")
+	}
+	return nil
+}
+
+// deviceHostOSDiff returns the planned host OS of the device, falling back to the provider's default.
+// It returns false if the host OS is only known after apply, d.Get then returns an empty value.
+func deviceHostOSDiff(d *schema.ResourceDiff, meta interface{}) (string, bool) {
+	if !d.NewValueKnown("host_os") {
+		return "", false
+	}
+	hostOS := d.Get("host_os").(string)
+	if hostOS == "" {
+		if client, ok := meta.(*goaviatrix.Client); ok {
+			hostOS = client.DefaultDeviceHostOS
+		}
+	}
+	return hostOS, true
+}
+
+// validateDeviceUsernameHostOSDiff rejects usernames that the host OS of the device does not allow
+// to connect over SSH.
+func validateDeviceUsernameHostOSDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("username") {
+		return nil
+	}
+	hostOS, known := deviceHostOSDiff(d, meta)
+	if !known {
+		return nil
+	}
+	return validateDeviceHostOS(hostOS, d.Get("username").(string), "")
+}
+
+// zipCodeFormats holds the postal code format of some countries, keyed by ISO 3166-1 alpha-2 code
+var zipCodeFormats = map[string]*regexp.Regexp{
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"CA": regexp.MustCompile(`^[A-Za-z]\d[A-Za-z] ?\d[A-Za-z]\d$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^[A-Za-z]{1,2}\d[A-Za-z\d]? ?\d[A-Za-z]{2}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Za-z]{2}$`),
+	"US": regexp.MustCompile(`^\d{5}(-?\d{4})?$`),
+}
+
+// validateDeviceZipCodeDiff checks 'zip_code' against the format of 'country' at plan time
+func validateDeviceZipCodeDiff(d *schema.ResourceDiff) error {
+	countryKey, zipCodeKey := "country", "zip_code"
+	if deviceAddressBlock(d.Get("address")) != nil {
+		countryKey, zipCodeKey = "address.0.country", "address.0.zip_code"
+	}
+	if !d.HasChange(zipCodeKey) && !d.HasChange(countryKey) {
+		return nil
+	}
+	if !d.NewValueKnown(zipCodeKey) || !d.NewValueKnown(countryKey) {
+		return nil
+	}
+	return validateZipCode(d.Get(countryKey).(string), d.Get(zipCodeKey).(string))
+}
+
+// deviceAddressKeys are the attributes of the 'address' block, which are also flat attributes of the device
+var deviceAddressKeys = []string{"address_1", "address_2", "city", "state", "country", "zip_code"}
+
+// deviceAddressBlock returns the attributes of the 'address' block, or nil if the block is not set.
+// Attributes of an empty block are returned as empty strings.
+func deviceAddressBlock(v interface{}) map[string]interface{} {
+	blocks, _ := v.([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+	address := map[string]interface{}{}
+	for _, key := range deviceAddressKeys {
+		address[key] = ""
+	}
+	if block, ok := blocks[0].(map[string]interface{}); ok {
+		for key, value := range block {
+			address[key] = value
+		}
+	}
+	return address
+}
+
+// validateDeviceAddressDiff rejects flat address attributes that are set to a different value than
+// the same attribute of the 'address' block.
+func validateDeviceAddressDiff(d *schema.ResourceDiff) error {
+	address := deviceAddressBlock(d.Get("address"))
+	if address == nil {
+		return nil
+	}
+	for _, key := range deviceAddressKeys {
+		if !d.NewValueKnown(key) || !d.NewValueKnown("address.0."+key) {
+			continue
+		}
+		flat, nested := d.Get(key).(string), address[key].(string)
+		if key == "country" {
+			flat, nested = strings.ToUpper(flat), strings.ToUpper(nested)
+		}
+		if flat != "" && flat != nested {
+			return fmt.Errorf("'%s' %q does not match 'address.0.%s' %q, set the address in the 'address' block only",
+				key, d.Get(key), key, address[key])
+		}
+	}
+	return nil
+}
+
+// validateZipCode returns an error if zipCode does not match the postal code format of country.
+// Empty values and countries without a known format are not validated.
+func validateZipCode(country, zipCode string) error {
+	if country == "" || zipCode == "" {
+		return nil
+	}
+	format, ok := zipCodeFormats[strings.ToUpper(country)]
+	if !ok || format.MatchString(zipCode) {
+		return nil
+	}
+	return fmt.Errorf("'zip_code' %q is not a valid postal code for country %s", zipCode, strings.ToUpper(country))
+}
+
+// deviceTagsInput returns the configured tags of the device, from either 'tags', 'tag_json' or 'tag'.
+// The raw 'tag_json' is sent to the controller as is.
+func deviceTagsInput(d *schema.ResourceData, name string, isCaag bool) *goaviatrix.Tags {
+	if tagJson := d.Get("tag_json").(string); tagJson != "" {
+		// 'tag_json' was validated at plan time
+		tagsMap, _ := parseTagJson(tagJson)
+		tags := goaviatrix.NewDeviceTags(name, isCaag, tagsMap)
+		if len(tagsMap) > 0 {
+			tags.TagJson = tagJson
+		}
+		return tags
+	}
+	if tagBlocks := d.Get("tag").(*schema.Set).List(); len(tagBlocks) > 0 {
+		// duplicate keys were rejected at plan time
+		tagsMap, _ := tagBlocksToMap(tagBlocks)
+		return goaviatrix.NewDeviceTags(name, isCaag, tagsMap)
+	}
+
+	tags := d.Get("tags").(map[string]interface{})
+	tagsMap := make(map[string]string, len(tags))
+	for key, val := range tags {
+		tagsMap[key] = val.(string)
+	}
+	return goaviatrix.NewDeviceTags(name, isCaag, tagsMap)
+}
+
+// tagBlocksToMap returns the tags configured as 'tag' blocks as a map. A key can only be set once.
+func tagBlocksToMap(tagBlocks []interface{}) (map[string]string, error) {
+	tags := make(map[string]string, len(tagBlocks))
+	for _, v := range tagBlocks {
+		tag := v.(map[string]interface{})
+		key, val := tag["key"].(string), tag["value"].(string)
+		if oldVal, ok := tags[key]; ok {
+			return nil, fmt.Errorf("tag key %q is set more than once, with values %q and %q", key, oldVal, val)
+		}
+		tags[key] = val
+	}
+	return tags, nil
+}
+
+// tagBlocksFromMap returns the tags as 'tag' blocks, sorted by key
+func tagBlocksFromMap(tags map[string]string) []interface{} {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tagBlocks := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		tagBlocks = append(tagBlocks, map[string]interface{}{"key": key, "value": tags[key]})
+	}
+	return tagBlocks
+}
+
+// deviceLabelsInput returns the controller tags of the device, as configured in 'labels'
+func deviceLabelsInput(d *schema.ResourceData, name string, isCaag bool) *goaviatrix.Tags {
+	labels := goaviatrix.NewDeviceTags(name, isCaag, tagsMapFromInterface(d.Get("labels")))
+	labels.Scope = goaviatrix.TagScopeController
+	return labels
+}
+
+// upgradeWindowTimeOfDay is the format of the time of day of a daily maintenance window
+const upgradeWindowTimeOfDay = "15:04"
+
+// validateUpgradeWindowTime validates that a bound of the maintenance window is an RFC3339 timestamp or a time of day
+func validateUpgradeWindowTime(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := time.Parse(time.RFC3339, v); err == nil {
+		return nil, nil
+	}
+	if _, err := time.Parse(upgradeWindowTimeOfDay, v); err == nil {
+		return nil, nil
+	}
+	return nil, []error{fmt.Errorf("%q must be an RFC3339 timestamp or a time of day in the format 'HH:MM', got: %s", k, v)}
+}
+
+// inUpgradeWindow returns true if now is within the maintenance window from start to end. Both bounds
+// are either RFC3339 timestamps of a single window, or times of day in UTC of a daily window, which
+// wraps around midnight if end is before start.
+func inUpgradeWindow(start, end string, now time.Time) (bool, error) {
+	startTime, startErr := time.Parse(time.RFC3339, start)
+	endTime, endErr := time.Parse(time.RFC3339, end)
+	if startErr == nil && endErr == nil {
+		return !now.Before(startTime) && now.Before(endTime), nil
+	}
+
+	startTime, startErr = time.Parse(upgradeWindowTimeOfDay, start)
+	endTime, endErr = time.Parse(upgradeWindowTimeOfDay, end)
+	if startErr != nil || endErr != nil {
+		return false, fmt.Errorf("'upgrade_window_start' %q and 'upgrade_window_end' %q must both be RFC3339 "+
+			"timestamps or both be times of day in the format 'HH:MM'", start, end)
+	}
+	now = now.UTC()
+	minute := now.Hour()*60 + now.Minute()
+	startMinute := startTime.Hour()*60 + startTime.Minute()
+	endMinute := endTime.Hour()*60 + endTime.Minute()
+	if startMinute <= endMinute {
+		return minute >= startMinute && minute < endMinute, nil
+	}
+	return minute >= startMinute || minute < endMinute, nil
+}
+
+// isCaagHostOSMismatch returns true if the controller reports a device as a Managed CloudN (CaaG)
+// device although its host OS is 'ios'. Only 'aviatrix' devices can be CaaG.
+func isCaagHostOSMismatch(isCaag bool, hostOS string) bool {
+	return isCaag && strings.EqualFold(hostOS, "ios")
+}
+
+// isUpgradeNeeded returns false if the device already runs the target software version. The special
+// versions "latest" and "previous" always need an upgrade.
+func isUpgradeNeeded(current, target string) bool {
+	return !goaviatrix.SoftwareVersionMatches(current, target)
+}
+
+// isDowngrade returns true if target is an older software version than current,
+// or the special version "previous". Build metadata, e.g. "+build1" in "6.5.100+build1", is ignored.
+// Versions that can not be compared are not considered a downgrade.
+func isDowngrade(current, target string) bool {
+	if target == "previous" {
+		return true
+	}
+	current, target = trimVersionBuildMetadata(current), trimVersionBuildMetadata(target)
+	compare, err := goaviatrix.CompareSoftwareVersions(current, target)
+	return err == nil && compare > 0
+}
+
+// trimVersionBuildMetadata removes the build metadata after a "+" from a software version
+func trimVersionBuildMetadata(version string) string {
+	if i := strings.Index(version, "+"); i >= 0 {
+		return version[:i]
+	}
+	return version
+}
+
+func resourceAviatrixDeviceRegistrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*goaviatrix.Client)
 
 	br := marshalDeviceRegistrationInput(d)
 
-	if err := client.DeregisterDevice(br); err != nil {
-		return fmt.Errorf("could not deregister device: %v", err)
+	if d.Get("force_delete").(bool) {
+		if err := client.DetachDeviceConnections(ctx, br.Name); err != nil {
+			return diag.Errorf("could not detach connections before deregistering device: %v", err)
+		}
+	} else {
+		attachments, err := client.GetDeviceAttachments(ctx, br.Name)
+		if err != nil && err != goaviatrix.ErrNotFound {
+			return diag.Errorf("could not get attachments of device %s: %v", br.Name, err)
+		}
+		if len(attachments) > 0 {
+			return diag.Errorf("could not deregister device %s, it is still attached to: %s. Delete the attachments "+
+				"first or set 'force_delete' to true to detach them before deregistering", br.Name, strings.Join(attachments, ", "))
+		}
+	}
+
+	if err := client.DeregisterDevice(ctx, br); err != nil {
+		return diag.Errorf("could not deregister device: %v", err)
 	}
 
 	d.SetId(br.Name)
@@ -0,0 +1,541 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// bulkDeviceRegistrationKeys are the arguments of a device in aviatrix_device_registration_bulk that are
+// sent to the controller. A change of any of them updates the registration of the device.
+var bulkDeviceRegistrationKeys = []string{"public_ip", "username", "key_file", "key_file_content", "password",
+	"key_passphrase", "host_os", "ssh_port", "address_1", "address_2", "city", "state", "country", "zip_code", "description"}
+
+func resourceAviatrixDeviceRegistrationBulk() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAviatrixDeviceRegistrationBulkCreate,
+		ReadContext:   resourceAviatrixDeviceRegistrationBulkRead,
+		UpdateContext: resourceAviatrixDeviceRegistrationBulkUpdate,
+		DeleteContext: resourceAviatrixDeviceRegistrationBulkDelete,
+		CustomizeDiff: resourceAviatrixDeviceRegistrationBulkCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"device": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Devices to register.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the device.",
+						},
+						"public_ip": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: DiffSuppressFuncEqualIP,
+							ValidateFunc:     validateIPAddressNotHostname,
+							Description:      "Public IP address of the device.",
+						},
+						"username": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateSSHUsername,
+							Description:  "Username to use to connect to the device.",
+						},
+						"key_file": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateReadableFile,
+							Description:  "Path to private key file. Must be readable when planning.",
+						},
+						"key_file_content": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Content of the private key in PEM format.",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password to connect to the device.",
+						},
+						"key_passphrase": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Passphrase for an encrypted private key. Only used with 'key_file' or 'key_file_content'.",
+						},
+						"host_os": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringInSlice([]string{"ios", "aviatrix"}, false),
+							Description: "Device host OS. Valid values are 'ios' or 'aviatrix'. " +
+								"Defaults to the provider's 'default_device_host_os', which defaults to 'ios'.",
+						},
+						"ssh_port": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          defaultSSHPort,
+							ValidateFunc:     validation.IsPortNumber,
+							DiffSuppressFunc: DiffSuppressFuncDefaultSSHPort,
+							Description:      "SSH port to use to connect to the device. Defaults to 22 if not set.",
+						},
+						"address_1": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Address line 1.",
+						},
+						"address_2": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Address line 2.",
+						},
+						"city": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "City",
+						},
+						"state": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "State",
+						},
+						"country": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateISOCountryCode,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return strings.ToUpper(old) == strings.ToUpper(new)
+							},
+							Description: "ISO two-letter country code. Case insensitive.",
+						},
+						"zip_code": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Zip code.",
+						},
+						"description": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validateDeviceDescription,
+							DiffSuppressFunc: DiffSuppressFuncIgnoreSurroundingSpace,
+							Description:      "Description. At most 255 characters, without control characters such as newlines.",
+						},
+						"is_caag": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether this device is a Managed CloudN device (CaaG)",
+						},
+						"software_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Software version of the device.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceAviatrixDeviceRegistrationBulkCustomizeDiff checks that every device is defined only once,
+// since devices are identified by name.
+func resourceAviatrixDeviceRegistrationBulkCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	names := map[string]bool{}
+	for _, v := range d.Get("device").([]interface{}) {
+		definition, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// the name is empty if it is not known yet
+		name := definition["name"].(string)
+		if name == "" {
+			continue
+		}
+		// the controller treats device names case-insensitively
+		if names[strings.ToLower(name)] {
+			return fmt.Errorf("device %q is defined more than once in 'device'", name)
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return nil
+}
+
+// marshalBulkDeviceInput marshals a device definition of aviatrix_device_registration_bulk into a
+// Device struct. Devices without a host OS get defaultHostOS, or 'ios' if that is empty too.
+func marshalBulkDeviceInput(definition map[string]interface{}, defaultHostOS string) (*goaviatrix.Device, error) {
+	device := &goaviatrix.Device{
+		Name:           definition["name"].(string),
+		PublicIP:       definition["public_ip"].(string),
+		Username:       definition["username"].(string),
+		KeyFile:        definition["key_file"].(string),
+		KeyFileContent: definition["key_file_content"].(string),
+		Password:       definition["password"].(string),
+		HostOS:         definition["host_os"].(string),
+		SshPort:        definition["ssh_port"].(int),
+		Address1:       definition["address_1"].(string),
+		Address2:       definition["address_2"].(string),
+		City:           definition["city"].(string),
+		State:          definition["state"].(string),
+		Country:        strings.ToUpper(definition["country"].(string)),
+		ZipCode:        definition["zip_code"].(string),
+		Description:    strings.TrimRightFunc(definition["description"].(string), unicode.IsSpace),
+	}
+
+	credentials := 0
+	for _, credential := range []string{device.Password, device.KeyFile, device.KeyFileContent} {
+		if credential != "" {
+			credentials++
+		}
+	}
+	if credentials != 1 {
+		return nil, fmt.Errorf("exactly one of 'password', 'key_file' or 'key_file_content' must be set for device %s", device.Name)
+	}
+
+	if device.HostOS == "" {
+		device.HostOS = defaultHostOS
+	}
+	if device.HostOS == "" {
+		device.HostOS = "ios"
+	}
+
+	if device.SshPort >= 1 && device.SshPort <= 65535 {
+		device.SshPortStr = strconv.Itoa(device.SshPort)
+	}
+
+	// The passphrase only applies to key based authentication, never send it along with a password.
+	if device.KeyFile != "" || device.KeyFileContent != "" {
+		device.KeyPassphrase = definition["key_passphrase"].(string)
+	}
+
+	return device, nil
+}
+
+// bulkDeviceChanged returns whether the registration information of a device differs between
+// its old and new definition. An empty host_os in the new definition keeps the current host OS.
+// Values that only differ in the way the controller normalizes them are not considered changed.
+func bulkDeviceChanged(old, new map[string]interface{}) bool {
+	for _, key := range bulkDeviceRegistrationKeys {
+		switch key {
+		case "host_os":
+			if new[key].(string) != "" && new[key] != old[key] {
+				return true
+			}
+		case "country":
+			if strings.ToUpper(new[key].(string)) != strings.ToUpper(old[key].(string)) {
+				return true
+			}
+		case "ssh_port":
+			if sshPortOrDefault(new[key].(int)) != sshPortOrDefault(old[key].(int)) {
+				return true
+			}
+		case "public_ip":
+			if !DiffSuppressFuncEqualIP(key, old[key].(string), new[key].(string), nil) && new[key] != old[key] {
+				return true
+			}
+		case "address_1", "address_2", "city", "state", "zip_code", "description":
+			if strings.TrimSpace(new[key].(string)) != strings.TrimSpace(old[key].(string)) {
+				return true
+			}
+		default:
+			if new[key] != old[key] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bulkDevicePlaceholder returns the definition kept in the state in place of a device that is not
+// registered, e.g. because it was deregistered outside of Terraform or failed to register. Keeping its
+// position in the list, instead of leaving it out, does not shift the devices after it: the plan only
+// shows the device as added and the next apply registers it again.
+func bulkDevicePlaceholder() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// isBulkDevicePlaceholder returns whether the definition is a placeholder of an unregistered device. The
+// SDK reads a placeholder back as nil.
+func isBulkDevicePlaceholder(definition map[string]interface{}) bool {
+	name, _ := definition["name"].(string)
+	return name == ""
+}
+
+// bulkDeviceDiagnostic returns a diagnostic for an error of a single device, so the error of
+// every device is reported separately.
+func bulkDeviceDiagnostic(summary string, err error) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   err.Error(),
+	}
+}
+
+// deregisterBulkDevices deregisters the devices in a single call and returns the definitions of the
+// devices that could not be deregistered, with a diagnostic for each of them.
+func deregisterBulkDevices(ctx context.Context, client *goaviatrix.Client, definitions []interface{}) ([]interface{}, diag.Diagnostics) {
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(definitions))
+	var registered []interface{}
+	for _, v := range definitions {
+		definition, _ := v.(map[string]interface{})
+		if isBulkDevicePlaceholder(definition) {
+			continue
+		}
+		names = append(names, definition["name"].(string))
+		registered = append(registered, definition)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	definitions = registered
+
+	err := client.DeregisterDevicesBulk(ctx, names)
+	if err == nil {
+		return nil, nil
+	}
+	bulkErr, ok := err.(*goaviatrix.BulkDeviceError)
+	if !ok {
+		return definitions, diag.Errorf("could not deregister devices: %v", err)
+	}
+	var diags diag.Diagnostics
+	var remaining []interface{}
+	for _, v := range definitions {
+		definition := v.(map[string]interface{})
+		name := definition["name"].(string)
+		if err, failed := bulkErr.Errors[name]; failed {
+			diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not deregister device %s", name), err))
+			remaining = append(remaining, definition)
+		}
+	}
+	return remaining, diags
+}
+
+// registerBulkDevice registers the device and waits until the controller lists it.
+func registerBulkDevice(ctx context.Context, client *goaviatrix.Client, device *goaviatrix.Device) error {
+	if err := client.RegisterDevice(ctx, device); err != nil {
+		return err
+	}
+	// the controller may take a few seconds before it lists a newly registered device
+	if _, err := client.WaitForDevice(ctx, device, 15*time.Second); err != nil {
+		log.Printf("[WARN] device %s was registered but could not be read back: %v", device.Name, err)
+	}
+	return nil
+}
+
+func resourceAviatrixDeviceRegistrationBulkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	var diags diag.Diagnostics
+	var devices []interface{}
+	registered := 0
+	for _, v := range d.Get("device").([]interface{}) {
+		definition := v.(map[string]interface{})
+		device, err := marshalBulkDeviceInput(definition, client.DefaultDeviceHostOS)
+		if err == nil {
+			err = registerBulkDevice(ctx, client, device)
+		}
+		if err != nil {
+			diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not register device %s", definition["name"]), err))
+			devices = append(devices, bulkDevicePlaceholder())
+			continue
+		}
+		devices = append(devices, definition)
+		registered++
+	}
+	if registered == 0 {
+		return diags
+	}
+
+	// Errors would taint the resource, which replaces all of its devices on the next apply. Once a
+	// device is registered, report the devices that failed as warnings instead. They are kept in the
+	// state as placeholders, so the next apply registers them again.
+	for i := range diags {
+		diags[i].Severity = diag.Warning
+	}
+
+	d.SetId(resource.PrefixedUniqueId("device-registration-bulk-"))
+	if err := d.Set("device", devices); err != nil {
+		return append(diags, diag.Errorf("could not set devices: %v", err)...)
+	}
+	return append(diags, resourceAviatrixDeviceRegistrationBulkRead(ctx, d, meta)...)
+}
+
+func resourceAviatrixDeviceRegistrationBulkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	var devices []interface{}
+	registered := 0
+	for _, v := range d.Get("device").([]interface{}) {
+		definition, _ := v.(map[string]interface{})
+		if isBulkDevicePlaceholder(definition) {
+			devices = append(devices, bulkDevicePlaceholder())
+			continue
+		}
+		name := definition["name"].(string)
+
+		device, err := client.GetDeviceCached(ctx, &goaviatrix.Device{Name: name})
+		if err == goaviatrix.ErrNotFound {
+			log.Printf("[WARN] device %s is no longer registered, it is registered again on the next apply", name)
+			devices = append(devices, bulkDevicePlaceholder())
+			continue
+		}
+		if err != nil {
+			return diag.Errorf("could not find device %s: %v", name, err)
+		}
+
+		// credentials can not be read back from the controller and are kept from the state
+		definition["public_ip"] = device.PublicIP
+		definition["username"] = device.Username
+		definition["host_os"] = strings.ToLower(device.HostOS)
+		definition["ssh_port"] = sshPortOrDefault(device.SshPort)
+		definition["address_1"] = device.Address1
+		definition["address_2"] = device.Address2
+		definition["city"] = device.City
+		definition["state"] = device.State
+		definition["country"] = device.Country
+		definition["zip_code"] = device.ZipCode
+		definition["description"] = device.Description
+		definition["is_caag"] = device.IsCaag
+		definition["software_version"] = device.SoftwareVersion
+		devices = append(devices, definition)
+		registered++
+	}
+
+	if registered == 0 {
+		log.Printf("[WARN] none of the devices of %s are registered, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err := d.Set("device", devices); err != nil {
+		return diag.Errorf("could not set devices: %v", err)
+	}
+	return nil
+}
+
+func resourceAviatrixDeviceRegistrationBulkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	o, n := d.GetChange("device")
+	oldDevices := map[string]map[string]interface{}{}
+	for _, v := range o.([]interface{}) {
+		definition, _ := v.(map[string]interface{})
+		if !isBulkDevicePlaceholder(definition) {
+			oldDevices[strings.ToLower(definition["name"].(string))] = definition
+		}
+	}
+
+	// devices holds the devices registered after the update. A device that could not be updated
+	// keeps its old definition, so the next plan shows the change again, and a device that is not
+	// registered gets a placeholder.
+	var diags diag.Diagnostics
+	var devices []interface{}
+	newNames := map[string]bool{}
+	for _, v := range n.([]interface{}) {
+		definition := v.(map[string]interface{})
+		name := definition["name"].(string)
+		newNames[strings.ToLower(name)] = true
+
+		old, exists := oldDevices[strings.ToLower(name)]
+		if exists && !bulkDeviceChanged(old, definition) {
+			devices = append(devices, definition)
+			continue
+		}
+
+		device, err := marshalBulkDeviceInput(definition, client.DefaultDeviceHostOS)
+		if err != nil {
+			diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not update device %s", name), err))
+			if exists {
+				devices = append(devices, old)
+			} else {
+				devices = append(devices, bulkDevicePlaceholder())
+			}
+			continue
+		}
+
+		switch {
+		case !exists:
+			if err := registerBulkDevice(ctx, client, device); err != nil {
+				diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not register device %s", name), err))
+				devices = append(devices, bulkDevicePlaceholder())
+				continue
+			}
+		case definition["host_os"].(string) != "" && definition["host_os"] != old["host_os"]:
+			// the host OS can only be set when registering a device
+			if err := client.DeregisterDevice(ctx, device); err != nil {
+				diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not deregister device %s to change its host_os", name), err))
+				devices = append(devices, old)
+				continue
+			}
+			if err := registerBulkDevice(ctx, client, device); err != nil {
+				diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not register device %s with new host_os", name), err))
+				devices = append(devices, bulkDevicePlaceholder())
+				continue
+			}
+		default:
+			if err := client.UpdateDevice(ctx, device); err != nil {
+				diags = append(diags, bulkDeviceDiagnostic(fmt.Sprintf("could not update device %s", name), err))
+				devices = append(devices, old)
+				continue
+			}
+		}
+		devices = append(devices, definition)
+	}
+
+	var removed []interface{}
+	for _, v := range o.([]interface{}) {
+		old, _ := v.(map[string]interface{})
+		if !isBulkDevicePlaceholder(old) && !newNames[strings.ToLower(old["name"].(string))] {
+			removed = append(removed, old)
+		}
+	}
+	remaining, deregisterDiags := deregisterBulkDevices(ctx, client, removed)
+	diags = append(diags, deregisterDiags...)
+	devices = append(devices, remaining...)
+
+	if err := d.Set("device", devices); err != nil {
+		return append(diags, diag.Errorf("could not set devices: %v", err)...)
+	}
+	return append(diags, resourceAviatrixDeviceRegistrationBulkRead(ctx, d, meta)...)
+}
+
+func resourceAviatrixDeviceRegistrationBulkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*goaviatrix.Client)
+
+	// devices that could not be deregistered are kept in the state, so deleting again only retries them
+	remaining, diags := deregisterBulkDevices(ctx, client, d.Get("device").([]interface{}))
+	if diags.HasError() {
+		if err := d.Set("device", remaining); err != nil {
+			return append(diags, diag.Errorf("could not set devices: %v", err)...)
+		}
+	}
+	return diags
+}
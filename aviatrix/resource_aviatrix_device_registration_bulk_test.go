@@ -0,0 +1,344 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccAviatrixDeviceRegistrationBulk_basic(t *testing.T) {
+	if os.Getenv("SKIP_DEVICE_REGISTRATION_BULK") == "yes" {
+		t.Skip("Skipping Device registration bulk test as SKIP_DEVICE_REGISTRATION_BULK is set")
+	}
+
+	rName := acctest.RandString(5)
+	resourceName := "aviatrix_device_registration_bulk.test_devices"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			deviceRegistrationPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDeviceRegistrationBulkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceRegistrationBulkBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDeviceRegistrationBulkExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "device.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "device.0.name", "device-registration-bulk-"+rName),
+				),
+			},
+		},
+	})
+}
+
+func testAccDeviceRegistrationBulkBasic(rName string) string {
+	return fmt.Sprintf(`
+resource "aviatrix_device_registration_bulk" "test_devices" {
+	device {
+		name        = "device-registration-bulk-%s"
+		public_ip   = "%s"
+		username    = "ec2-user"
+		key_file    = "%s"
+		host_os     = "ios"
+		city        = "Santa Clara"
+		state       = "CA"
+		description = "Test device."
+	}
+}
+`, rName, os.Getenv("DEVICE_PUBLIC_IP"), os.Getenv("DEVICE_KEY_FILE_PATH"))
+}
+
+func testAccCheckDeviceRegistrationBulkExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("device_registration_bulk Not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no device_registration_bulk ID is set")
+		}
+
+		client := testAccProvider.Meta().(*goaviatrix.Client)
+
+		device := &goaviatrix.Device{
+			Name: rs.Primary.Attributes["device.0.name"],
+		}
+		if _, err := client.GetDevice(context.Background(), device); err != nil {
+			return fmt.Errorf("device %s of device_registration_bulk not found: %v", device.Name, err)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDeviceRegistrationBulkDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*goaviatrix.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aviatrix_device_registration_bulk" {
+			continue
+		}
+		device := &goaviatrix.Device{
+			Name: rs.Primary.Attributes["device.0.name"],
+		}
+		_, err := client.GetDevice(context.Background(), device)
+		if err == nil {
+			return fmt.Errorf("device %s of device_registration_bulk still exists", device.Name)
+		}
+	}
+
+	return nil
+}
+
+func TestResourceAviatrixDeviceRegistrationBulkCustomizeDiff(t *testing.T) {
+	device := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":      name,
+			"public_ip": "1.2.3.4",
+			"username":  "ec2-user",
+			"password":  "password",
+		}
+	}
+	tests := []struct {
+		name    string
+		devices []interface{}
+		wantErr bool
+	}{
+		{
+			"unique names",
+			[]interface{}{device("device-1"), device("device-2")},
+			false,
+		},
+		{
+			"duplicate names",
+			[]interface{}{device("device-1"), device("device-2"), device("device-1")},
+			true,
+		},
+		{
+			"names differing in case",
+			[]interface{}{device("device-1"), device("Device-1")},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"device": tt.devices}
+
+			_, err := resourceAviatrixDeviceRegistrationBulk().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), &goaviatrix.Client{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBulkDeviceNotRegistered(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	registered := map[string]bool{"device-1": true, "device-3": true}
+	client := newDeviceTestClient(t, func(w http.ResponseWriter, action string, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		actions = append(actions, action)
+		switch action {
+		case "list_cloudwan_devices_summary":
+			var results []string
+			for name := range registered {
+				results = append(results, fmt.Sprintf(`{"rgw_name":%q,"hostname":"1.2.3.4","username":"ec2-user","host_os":"ios"}`, name))
+			}
+			fmt.Fprintf(w, `{"return":true,"results":[%s]}`, strings.Join(results, ","))
+		case "register_cloudwan_device":
+			registered[r.FormValue("device_name")] = true
+			fmt.Fprint(w, `{"return":true}`)
+		default:
+			fmt.Fprint(w, `{"return":true}`)
+		}
+	})
+
+	r := resourceAviatrixDeviceRegistrationBulk()
+	devices := []interface{}{}
+	attributes := map[string]string{"device.#": "3"}
+	for i, name := range []string{"device-1", "device-2", "device-3"} {
+		devices = append(devices, map[string]interface{}{
+			"name":      name,
+			"public_ip": "1.2.3.4",
+			"username":  "ec2-user",
+			"password":  "password",
+		})
+		prefix := fmt.Sprintf("device.%d.", i)
+		attributes[prefix+"name"] = name
+		attributes[prefix+"public_ip"] = "1.2.3.4"
+		attributes[prefix+"username"] = "ec2-user"
+		attributes[prefix+"password"] = "password"
+		attributes[prefix+"host_os"] = "ios"
+		attributes[prefix+"ssh_port"] = "22"
+	}
+	state := &terraform.InstanceState{ID: "device-registration-bulk-1", Attributes: attributes}
+
+	// the device deregistered outside of Terraform keeps its position, the devices after it do not shift
+	state, diags := r.RefreshWithoutUpgrade(context.Background(), state, client)
+	if diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() unexpected errors: %v", diags)
+	}
+	if state == nil || state.Attributes["device.#"] != "3" || state.Attributes["device.1.name"] != "" ||
+		state.Attributes["device.2.name"] != "device-3" {
+		t.Fatalf("RefreshWithoutUpgrade() state = %v, want device-2 replaced by a placeholder", state)
+	}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{"device": devices}), client)
+	if err != nil {
+		t.Fatalf("Diff() unexpected error: %v", err)
+	}
+	var changed []string
+	for key, attr := range diff.Attributes {
+		if strings.HasSuffix(key, ".name") && attr.Old != attr.New {
+			changed = append(changed, fmt.Sprintf("%s: %q => %q", key, attr.Old, attr.New))
+		}
+	}
+	if want := []string{`device.1.name: "" => "device-2"`}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("Diff() changed names %v, want %v", changed, want)
+	}
+
+	actions = nil
+	state, diags = r.Apply(context.Background(), state, diff, client)
+	if diags.HasError() {
+		t.Fatalf("Apply() unexpected errors: %v", diags)
+	}
+	// only the missing device is registered, waited for and read back with the others
+	if want := []string{"register_cloudwan_device", "list_cloudwan_devices_summary", "list_cloudwan_devices_summary"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("Apply() actions = %v, want %v", actions, want)
+	}
+	if state.Attributes["device.1.name"] != "device-2" {
+		t.Errorf("Apply() device.1.name = %q, want %q", state.Attributes["device.1.name"], "device-2")
+	}
+}
+
+func TestMarshalBulkDeviceInput(t *testing.T) {
+	definition := func(overrides map[string]interface{}) map[string]interface{} {
+		d := map[string]interface{}{}
+		for key, v := range resourceAviatrixDeviceRegistrationBulk().Schema["device"].Elem.(*schema.Resource).Schema {
+			switch v.Type {
+			case schema.TypeInt:
+				d[key] = 0
+			case schema.TypeBool:
+				d[key] = false
+			default:
+				d[key] = ""
+			}
+		}
+		d["name"] = "device"
+		d["ssh_port"] = 22
+		for key, v := range overrides {
+			d[key] = v
+		}
+		return d
+	}
+	tests := []struct {
+		name          string
+		definition    map[string]interface{}
+		defaultHostOS string
+		wantHostOS    string
+		wantErr       bool
+	}{
+		{
+			"password",
+			definition(map[string]interface{}{"password": "secret", "key_passphrase": "passphrase"}),
+			"",
+			"ios",
+			false,
+		},
+		{
+			"provider default host os",
+			definition(map[string]interface{}{"key_file_content": "key"}),
+			"aviatrix",
+			"aviatrix",
+			false,
+		},
+		{
+			"no credentials",
+			definition(nil),
+			"",
+			"",
+			true,
+		},
+		{
+			"password and key file",
+			definition(map[string]interface{}{"password": "secret", "key_file": "/path/to/key"}),
+			"",
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, err := marshalBulkDeviceInput(tt.definition, tt.defaultHostOS)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("marshalBulkDeviceInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if device.HostOS != tt.wantHostOS {
+				t.Errorf("marshalBulkDeviceInput() host os = %q, want %q", device.HostOS, tt.wantHostOS)
+			}
+			if device.Password != "" && device.KeyPassphrase != "" {
+				t.Errorf("marshalBulkDeviceInput() sent key passphrase along with a password")
+			}
+		})
+	}
+}
+
+func TestBulkDeviceChanged(t *testing.T) {
+	old := map[string]interface{}{}
+	for _, key := range bulkDeviceRegistrationKeys {
+		old[key] = ""
+	}
+	old["ssh_port"] = 22
+	old["host_os"] = "ios"
+	old["country"] = "US"
+	old["public_ip"] = "2001:db8::1"
+
+	tests := []struct {
+		name  string
+		key   string
+		value interface{}
+		want  bool
+	}{
+		{"unchanged", "city", "", false},
+		{"city", "city", "Santa Clara", true},
+		{"ssh port", "ssh_port", 2222, true},
+		{"ssh port zero from controller", "ssh_port", 0, false},
+		{"country case", "country", "us", false},
+		{"host os not set", "host_os", "", false},
+		{"host os", "host_os", "aviatrix", true},
+		{"city whitespace", "city", " ", false},
+		{"public ip notation", "public_ip", "2001:db8:0:0::1", false},
+		{"public ip", "public_ip", "2001:db8::2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			new := map[string]interface{}{}
+			for key, v := range old {
+				new[key] = v
+			}
+			new[tt.key] = tt.value
+			if got := bulkDeviceChanged(old, new); got != tt.want {
+				t.Errorf("bulkDeviceChanged() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
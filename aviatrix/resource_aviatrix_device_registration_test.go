@@ -1,13 +1,23 @@
 package aviatrix
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -37,7 +47,7 @@ func TestAccAviatrixDeviceRegistration_basic(t *testing.T) {
 				ResourceName:            resourceName,
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"password", "key_file"},
+				ImportStateVerifyIgnore: []string{"password", "key_file", "key_file_content", "key_passphrase"},
 			},
 		},
 	})
@@ -78,7 +88,7 @@ func testAccCheckDeviceRegistrationExists(n string) resource.TestCheckFunc {
 			Name: rs.Primary.Attributes["name"],
 		}
 
-		_, err := client.GetDevice(device)
+		_, err := client.GetDevice(context.Background(), device)
 		if err != nil {
 			return err
 		}
@@ -100,7 +110,7 @@ func testAccCheckDeviceRegistrationDestroy(s *terraform.State) error {
 		device := &goaviatrix.Device{
 			Name: rs.Primary.Attributes["name"],
 		}
-		_, err := client.GetDevice(device)
+		_, err := client.GetDevice(context.Background(), device)
 		if err == nil {
 			return fmt.Errorf("device_registration still exists")
 		}
@@ -119,3 +129,1140 @@ func deviceRegistrationPreCheck(t *testing.T) {
 			"device_registration acceptance test")
 	}
 }
+
+func TestIsDowngrade(t *testing.T) {
+	tt := []struct {
+		name    string
+		current string
+		target  string
+		want    bool
+	}{
+		{"upgrade build", "6.5.100", "6.5.101", false},
+		{"downgrade build", "6.5.101", "6.5.100", true},
+		{"same version", "6.5.100", "6.5.100", false},
+		{"upgrade minor", "6.5.100", "6.6", false},
+		{"downgrade minor", "6.6", "6.5.100", true},
+		{"downgrade major", "7.0.100", "6.6.100", true},
+		{"upgrade to pre-release", "6.5.1232", "6.5-patch.100", false},
+		{"downgrade from pre-release", "6.5-patch.100", "6.5.1232", true},
+		{"downgrade pre-release build", "6.5-patch.1232", "6.5-patch.100", true},
+		{"upgrade pre-release build", "6.5-patch.100", "6.5-patch.1232", false},
+		{"with prefix", "UserConnect-6.6.100", "UserConnect-6.5.100", true},
+		{"previous", "6.5.100", "previous", true},
+		{"unknown current", "", "6.5.100", false},
+		{"invalid target", "6.5.100", "6.a", false},
+		{"same version with build metadata", "6.5.100+build1", "6.5.100", false},
+		{"same version to build metadata", "6.5.100", "6.5.100+build2", false},
+		{"upgrade with build metadata", "6.5.100+build1", "6.5.101+build1", false},
+		{"downgrade with build metadata", "6.5.101+build1", "6.5.100", true},
+		{"downgrade to build metadata", "6.6.100", "6.5.100+build1", true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isDowngrade(tc.current, tc.target); got != tc.want {
+				t.Fatalf("isDowngrade(%q, %q) = %t, want %t", tc.current, tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateDeviceDowngrade(t *testing.T) {
+	tests := []struct {
+		name           string
+		stateVersion   string
+		currentVersion string
+		target         string
+		allowDowngrade bool
+		wantErr        bool
+	}{
+		{"device upgraded outside of Terraform", "6.5.100", "6.6.100", "6.5.200", false, true},
+		{"device downgraded outside of Terraform", "6.6.100", "6.5.200", "6.5.200", false, false},
+		{"current version unknown", "6.6.100", "", "6.5.200", false, true},
+		{"downgrade allowed", "6.5.100", "6.6.100", "6.6.100", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newDeviceTestClient(t, func(w http.ResponseWriter, action string, r *http.Request) {
+				switch action {
+				case "list_cloudwan_devices_summary":
+					fmt.Fprint(w, `{"return":true,"results":[{"rgw_name":"device","hostname":"1.2.3.4"}]}`)
+				default:
+					fmt.Fprint(w, `{"return":true}`)
+				}
+			})
+
+			r := resourceAviatrixDeviceRegistration()
+			state := &terraform.InstanceState{
+				ID: "device",
+				Attributes: map[string]string{
+					"name":                     "device",
+					"public_ip":                "1.2.3.4",
+					"username":                 "ec2-user",
+					"password":                 "password",
+					"host_os":                  "aviatrix",
+					"ssh_port":                 "22",
+					"is_caag":                  "true",
+					"software_version":         tt.stateVersion,
+					"current_software_version": tt.currentVersion,
+				},
+			}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":             "device",
+				"public_ip":        "1.2.3.4",
+				"username":         "ec2-user",
+				"password":         "password",
+				"host_os":          "aviatrix",
+				"is_caag":          true,
+				"software_version": tt.target,
+				"allow_downgrade":  tt.allowDowngrade,
+			})
+			diff, err := r.Diff(context.Background(), state, config, client)
+			if err != nil {
+				t.Fatalf("Diff() unexpected error: %v", err)
+			}
+			_, diags := r.Apply(context.Background(), state, diff, client)
+			gotErr := false
+			for _, d := range diags {
+				gotErr = gotErr || strings.Contains(d.Summary, "allow_downgrade")
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Apply() diagnostics = %v, want downgrade error %t", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsUpgradeNeeded(t *testing.T) {
+	tt := []struct {
+		name    string
+		current string
+		target  string
+		want    bool
+	}{
+		{"same version", "6.5.100", "6.5.100", false},
+		{"release of current version", "6.5.100", "6.5", false},
+		{"upgrade build", "6.5.100", "6.5.101", true},
+		{"downgrade", "6.6.100", "6.5.100", true},
+		{"latest", "6.5.100", "latest", true},
+		{"previous", "6.5.100", "previous", true},
+		{"unknown current", "", "6.5.100", true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isUpgradeNeeded(tc.current, tc.target); got != tc.want {
+				t.Fatalf("isUpgradeNeeded(%q, %q) = %t, want %t", tc.current, tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInUpgradeWindow(t *testing.T) {
+	now := time.Date(2021, 6, 1, 23, 30, 0, 0, time.UTC)
+	tt := []struct {
+		name    string
+		start   string
+		end     string
+		want    bool
+		wantErr bool
+	}{
+		{"in single window", "2021-06-01T22:00:00Z", "2021-06-02T02:00:00Z", true, false},
+		{"before single window", "2021-06-02T22:00:00Z", "2021-06-03T02:00:00Z", false, false},
+		{"single window with offset", "2021-06-01T18:00:00-05:00", "2021-06-01T19:00:00-05:00", true, false},
+		{"in daily window", "23:00", "23:59", true, false},
+		{"after daily window", "01:00", "05:00", false, false},
+		{"daily window over midnight", "22:00", "02:00", true, false},
+		{"outside daily window over midnight", "23:45", "02:00", false, false},
+		{"mixed formats", "2021-06-01T22:00:00Z", "02:00", false, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := inUpgradeWindow(tc.start, tc.end, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("inUpgradeWindow(%q, %q) error = %v, wantErr %t", tc.start, tc.end, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("inUpgradeWindow(%q, %q) = %t, want %t", tc.start, tc.end, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceAviatrixDeviceRegistrationCustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        map[string]interface{}
+		defaultHostOS string
+		wantErr       bool
+	}{
+		{
+			"ios without software_version",
+			map[string]interface{}{"host_os": "ios"},
+			"",
+			false,
+		},
+		{
+			"ios with software_version",
+			map[string]interface{}{"host_os": "ios", "software_version": "6.5"},
+			"",
+			true,
+		},
+		{
+			"aviatrix with software_version",
+			map[string]interface{}{"host_os": "aviatrix", "software_version": "6.5"},
+			"",
+			false,
+		},
+		{
+			"provider default aviatrix with software_version",
+			map[string]interface{}{"software_version": "6.5"},
+			"aviatrix",
+			false,
+		},
+		// the host OS of a new device that does not set it is only known during apply, like a reference
+		{
+			"provider default ios with software_version",
+			map[string]interface{}{"software_version": "6.5"},
+			"ios",
+			false,
+		},
+		{
+			"aviatrix with root username",
+			map[string]interface{}{"host_os": "aviatrix", "username": "root"},
+			"",
+			true,
+		},
+		{
+			"provider default aviatrix with root username",
+			map[string]interface{}{"username": "root"},
+			"aviatrix",
+			false,
+		},
+		{
+			"ios with root username",
+			map[string]interface{}{"host_os": "ios", "username": "root"},
+			"",
+			false,
+		},
+		{
+			"daily upgrade window",
+			map[string]interface{}{"upgrade_window_start": "22:00", "upgrade_window_end": "02:00"},
+			"",
+			false,
+		},
+		{
+			"upgrade window mixing formats",
+			map[string]interface{}{"upgrade_window_start": "2021-06-01T22:00:00Z", "upgrade_window_end": "02:00"},
+			"",
+			true,
+		},
+		{
+			"address block",
+			map[string]interface{}{"address": []interface{}{map[string]interface{}{"city": "Santa Clara", "country": "us"}}},
+			"",
+			false,
+		},
+		{
+			"address block matching flat attributes",
+			map[string]interface{}{
+				"address": []interface{}{map[string]interface{}{"city": "Santa Clara", "country": "us"}},
+				"city":    "Santa Clara",
+				"country": "US",
+			},
+			"",
+			false,
+		},
+		{
+			"address block conflicting with flat attributes",
+			map[string]interface{}{
+				"address": []interface{}{map[string]interface{}{"city": "Santa Clara"}},
+				"city":    "San Jose",
+			},
+			"",
+			true,
+		},
+		{
+			"address block with invalid zip_code",
+			map[string]interface{}{"address": []interface{}{map[string]interface{}{"country": "US", "zip_code": "ABC"}}},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"password":  "password",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+			client := &goaviatrix.Client{DefaultDeviceHostOS: tt.defaultHostOS}
+
+			_, err := resourceAviatrixDeviceRegistration().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceCredentialSourceDiff(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := ioutil.WriteFile(keyFile, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		env        map[string]string
+		config     map[string]interface{}
+		wantErr    bool
+		wantSource string
+	}{
+		{
+			"no credentials",
+			nil,
+			nil,
+			true,
+			"",
+		},
+		{
+			"key file from environment",
+			map[string]string{"AVIATRIX_DEVICE_KEY_FILE": keyFile},
+			nil,
+			false,
+			"key_file",
+		},
+		{
+			"unreadable key file from environment",
+			map[string]string{"AVIATRIX_DEVICE_KEY_FILE": "/path/to/key"},
+			nil,
+			true,
+			"",
+		},
+		{
+			"password and key file from environment",
+			map[string]string{"AVIATRIX_DEVICE_PASSWORD": "password", "AVIATRIX_DEVICE_KEY_FILE": "/path/to/key"},
+			nil,
+			true,
+			"",
+		},
+		{
+			"config takes precedence over environment",
+			map[string]string{"AVIATRIX_DEVICE_KEY_FILE": "/path/to/key"},
+			map[string]interface{}{"key_file_content": "key"},
+			false,
+			"key_file_content",
+		},
+		{
+			"key file in config and password from environment",
+			map[string]string{"AVIATRIX_DEVICE_PASSWORD": "password"},
+			map[string]interface{}{"key_file": keyFile},
+			false,
+			"key_file",
+		},
+		{
+			"unreadable key file in config",
+			nil,
+			map[string]interface{}{"key_file": "/path/to/other-key"},
+			true,
+			"",
+		},
+		{
+			"credentials from connection profile",
+			nil,
+			map[string]interface{}{"connection_profile": "branches"},
+			false,
+			"",
+		},
+		{
+			"password and key file content in config",
+			nil,
+			map[string]interface{}{"password": "password", "key_file_content": "key"},
+			true,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, envVar := range deviceCredentialEnvVars {
+				os.Unsetenv(envVar)
+			}
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"host_os":   "ios",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			r := resourceAviatrixDeviceRegistration()
+			if diags := r.Validate(terraform.NewResourceConfigRaw(config)); diags.HasError() {
+				// the defaults from the environment are validated too
+				t.Fatalf("Validate() unexpected error: %v", diags)
+			}
+			diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), &goaviatrix.Client{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil || tt.wantSource == "" {
+				return
+			}
+			credentials := deviceCredentials(func(key string) string {
+				if attr, ok := diff.Attributes[key]; ok {
+					return attr.New
+				}
+				return ""
+			})
+			if _, ok := credentials[tt.wantSource]; !ok || len(credentials) != 1 {
+				t.Errorf("deviceCredentials() = %v, want only %s", credentials, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceKeyPassphraseFromEnvironment(t *testing.T) {
+	os.Setenv("AVIATRIX_DEVICE_KEY_PASSPHRASE", "passphrase")
+	defer os.Unsetenv("AVIATRIX_DEVICE_KEY_PASSPHRASE")
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":      "device",
+		"public_ip": "1.2.3.4",
+		"username":  "ec2-user",
+		"password":  "password",
+	})
+	if diags := resourceAviatrixDeviceRegistration().Validate(config); diags.HasError() {
+		t.Errorf("Validate() of a password with the key passphrase set in the environment unexpected error: %v", diags)
+	}
+}
+
+func TestValidateDeviceCredentialsDiff(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := ioutil.WriteFile(keyFile, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	state := &terraform.InstanceState{
+		ID: "device",
+		Attributes: map[string]string{
+			"name":      "device",
+			"public_ip": "1.2.3.4",
+			"username":  "ec2-user",
+			"host_os":   "ios",
+			"ssh_port":  "22",
+		},
+	}
+	tests := []struct {
+		name    string
+		state   *terraform.InstanceState
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{
+			"new device with password",
+			nil,
+			map[string]interface{}{"username": "admin", "password": "password"},
+			false,
+		},
+		{
+			"username unchanged with password",
+			state,
+			map[string]interface{}{"username": "ec2-user", "password": "password"},
+			false,
+		},
+		{
+			"username changed with password",
+			state,
+			map[string]interface{}{"username": "admin", "password": "password"},
+			false,
+		},
+		{
+			"username changed with key_file",
+			state,
+			map[string]interface{}{"username": "admin", "key_file": keyFile},
+			false,
+		},
+		{
+			"username changed without credentials",
+			state,
+			map[string]interface{}{"username": "admin"},
+			true,
+		},
+		{
+			"rotation token changed with password",
+			state,
+			map[string]interface{}{"username": "ec2-user", "password": "password", "credential_rotation_token": "2026-10"},
+			false,
+		},
+		{
+			"username changed with connection profile",
+			state,
+			map[string]interface{}{"username": "admin", "connection_profile": "branches"},
+			false,
+		},
+		{
+			"rotation token changed without credentials",
+			state,
+			map[string]interface{}{"username": "ec2-user", "credential_rotation_token": "2026-10"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"host_os":   "ios",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			_, err := resourceAviatrixDeviceRegistration().Diff(context.Background(), tt.state, terraform.NewResourceConfigRaw(config), &goaviatrix.Client{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSuppressCredentialDiffAfterImport(t *testing.T) {
+	imported := func(attributes map[string]string) *terraform.InstanceState {
+		state := &terraform.InstanceState{
+			ID: "device",
+			Attributes: map[string]string{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"host_os":   "ios",
+				"ssh_port":  "22",
+			},
+		}
+		for k, v := range attributes {
+			state.Attributes[k] = v
+		}
+		return state
+	}
+	tests := []struct {
+		name     string
+		state    *terraform.InstanceState
+		config   map[string]interface{}
+		wantDiff []string
+	}{
+		{"password set after import", imported(nil), map[string]interface{}{"password": "password"}, []string{"password"}},
+		{"key_file_content set after import", imported(nil), map[string]interface{}{"key_file_content": "key"}, []string{"key_file_content"}},
+		{"password set after import with connection profile", imported(nil), map[string]interface{}{"connection_profile": "branches", "password": "password"}, []string{"password"}},
+		{"password restored on import", imported(map[string]string{"password": "password"}), map[string]interface{}{"password": "password"}, nil},
+		{"password restored on import with connection profile", imported(map[string]string{"password": "password"}), map[string]interface{}{"connection_profile": "branches"}, nil},
+		{"password replaced by key_file_content", imported(map[string]string{"password": "password"}), map[string]interface{}{"key_file_content": "key"}, []string{"password", "key_file_content"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, envVar := range deviceCredentialEnvVars {
+				os.Unsetenv(envVar)
+			}
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"host_os":   "ios",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			diff, err := resourceAviatrixDeviceRegistration().Diff(context.Background(), tt.state, terraform.NewResourceConfigRaw(config), &goaviatrix.Client{})
+			if err != nil {
+				t.Fatalf("Diff() unexpected error: %v", err)
+			}
+			var gotDiff []string
+			for _, key := range deviceCredentialKeys {
+				if diff != nil && diff.Attributes[key] != nil {
+					gotDiff = append(gotDiff, key)
+				}
+			}
+			if !reflect.DeepEqual(gotDiff, tt.wantDiff) {
+				t.Errorf("Diff() changed credentials %v, want %v", gotDiff, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceSoftwareVersionDiff(t *testing.T) {
+	state := func(hostOS, softwareVersion string) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "device",
+			Attributes: map[string]string{
+				"name":             "device",
+				"public_ip":        "1.2.3.4",
+				"username":         "ec2-user",
+				"host_os":          hostOS,
+				"ssh_port":         "22",
+				"software_version": softwareVersion,
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		state   *terraform.InstanceState
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"new ios device", nil, map[string]interface{}{"host_os": "ios"}, false},
+		{"new ios device with version", nil, map[string]interface{}{"host_os": "ios", "software_version": "6.5"}, true},
+		{"new aviatrix device with version", nil, map[string]interface{}{"host_os": "aviatrix", "software_version": "6.5"}, false},
+		{"new device with default host os and version", nil, map[string]interface{}{"software_version": "6.5"}, false},
+		// unknown values, e.g. of references only known after apply, are checked during apply
+		{"new ios device with unknown version", nil, map[string]interface{}{"host_os": "ios", "software_version": "74D93920-ED26-11E3-AC10-0800200C9A66"}, false},
+		{"new device with unknown host os and version", nil, map[string]interface{}{"host_os": "74D93920-ED26-11E3-AC10-0800200C9A66", "software_version": "6.5"}, false},
+		{"ios device with unknown host os and version added", state("ios", ""), map[string]interface{}{"host_os": "74D93920-ED26-11E3-AC10-0800200C9A66", "software_version": "6.5"}, false},
+		{"ios device with version added", state("ios", ""), map[string]interface{}{"host_os": "ios", "software_version": "6.5"}, true},
+		{"ios device with version read", state("ios", "6.4"), map[string]interface{}{"host_os": "ios"}, false},
+		{"aviatrix device changed to ios with new version", state("aviatrix", "6.5"), map[string]interface{}{"host_os": "ios", "software_version": "6.6"}, true},
+		// the version in state was read from the device that is replaced
+		{"aviatrix device changed to ios", state("aviatrix", "6.5"), map[string]interface{}{"host_os": "ios"}, false},
+		{"aviatrix device version changed", state("aviatrix", "6.4"), map[string]interface{}{"host_os": "aviatrix", "software_version": "6.5"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"password":  "password",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			client := &goaviatrix.Client{DefaultDeviceHostOS: "ios"}
+			_, err := resourceAviatrixDeviceRegistration().Diff(context.Background(), tt.state, terraform.NewResourceConfigRaw(config), client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateDeviceValidatesDefaultHostOS(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        map[string]interface{}
+		defaultHostOS string
+		wantErr       string
+	}{
+		{"ios with software_version", map[string]interface{}{"software_version": "6.5"}, "ios", "'software_version' can only be set"},
+		{"no default with software_version", map[string]interface{}{"software_version": "6.5"}, "", "'software_version' can only be set"},
+		{"aviatrix with root username", map[string]interface{}{"username": "root"}, "aviatrix", "'username' can not be 'root'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"password":  "password",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourceAviatrixDeviceRegistration().Schema, config)
+
+			// the checks fail before the controller is called
+			client := &goaviatrix.Client{DefaultDeviceHostOS: tt.defaultHostOS}
+			diags := resourceAviatrixDeviceRegistrationCreate(context.Background(), d, client)
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.wantErr) {
+				t.Errorf("resourceAviatrixDeviceRegistrationCreate() = %v, want an error containing %q", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceTagsDiff(t *testing.T) {
+	state := func(cloudType string) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "device",
+			Attributes: map[string]string{
+				"name":       "device",
+				"public_ip":  "1.2.3.4",
+				"username":   "ec2-user",
+				"password":   "password",
+				"host_os":    "aviatrix",
+				"ssh_port":   "22",
+				"cloud_type": cloudType,
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		state   *terraform.InstanceState
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{
+			"new device",
+			nil,
+			map[string]interface{}{"tags": map[string]interface{}{"Key": "a,b"}},
+			false,
+		},
+		{
+			"no detected cloud type",
+			state("0"),
+			map[string]interface{}{"tags": map[string]interface{}{"Key": "a,b"}},
+			false,
+		},
+		{
+			"valid tags for gcp",
+			state("4"),
+			map[string]interface{}{"tags": map[string]interface{}{"key": "value"}},
+			false,
+		},
+		{
+			"invalid tags for gcp",
+			state("4"),
+			map[string]interface{}{"tags": map[string]interface{}{"Key": "a,b"}},
+			true,
+		},
+		{
+			"invalid tag_json for azure",
+			state("8"),
+			map[string]interface{}{"tag_json": `{"key": "a,b"}`},
+			true,
+		},
+		{
+			"tag blocks",
+			nil,
+			map[string]interface{}{"tag": []interface{}{
+				map[string]interface{}{"key": "app.kubernetes.io/name", "value": "a:b"},
+				map[string]interface{}{"key": "env", "value": "prod"},
+			}},
+			false,
+		},
+		{
+			"duplicate tag block keys",
+			nil,
+			map[string]interface{}{"tag": []interface{}{
+				map[string]interface{}{"key": "env", "value": "prod"},
+				map[string]interface{}{"key": "env", "value": "dev"},
+			}},
+			true,
+		},
+		{
+			"invalid tag blocks for gcp",
+			state("4"),
+			map[string]interface{}{"tag": []interface{}{
+				map[string]interface{}{"key": "Key", "value": "a,b"},
+			}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.4",
+				"username":  "ec2-user",
+				"password":  "password",
+				"host_os":   "aviatrix",
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			_, err := resourceAviatrixDeviceRegistration().Diff(context.Background(), tt.state, terraform.NewResourceConfigRaw(config), &goaviatrix.Client{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeviceTagBlocks(t *testing.T) {
+	tags := map[string]string{"env": "prod", "app.kubernetes.io/name": "a:b"}
+	tagBlocks := tagBlocksFromMap(tags)
+	want := []interface{}{
+		map[string]interface{}{"key": "app.kubernetes.io/name", "value": "a:b"},
+		map[string]interface{}{"key": "env", "value": "prod"},
+	}
+	if !reflect.DeepEqual(tagBlocks, want) {
+		t.Errorf("tagBlocksFromMap() = %v, want %v", tagBlocks, want)
+	}
+	got, err := tagBlocksToMap(tagBlocks)
+	if err != nil {
+		t.Fatalf("tagBlocksToMap() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, tags) {
+		t.Errorf("tagBlocksToMap() = %v, want %v", got, tags)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":      "device",
+		"public_ip": "1.2.3.4",
+		"username":  "ec2-user",
+		"password":  "password",
+		"tags":      map[string]interface{}{"env": "prod"},
+		"tag":       []interface{}{map[string]interface{}{"key": "team", "value": "network"}},
+	})
+	if diags := resourceAviatrixDeviceRegistration().Validate(config); !diags.HasError() {
+		t.Errorf("Validate() with both 'tags' and 'tag' got no error")
+	}
+}
+
+// newDeviceTestClient returns a client logged in to a fake controller that answers all other requests with handler
+func newDeviceTestClient(t *testing.T, handler func(w http.ResponseWriter, action string, r *http.Request)) *goaviatrix.Client {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action := r.FormValue("action")
+		if action == "login" {
+			fmt.Fprint(w, `{"return":true,"CID":"test-cid"}`)
+			return
+		}
+		handler(w, action, r)
+	}))
+	t.Cleanup(srv.Close)
+	client, err := goaviatrix.NewClient("admin", "password", strings.TrimPrefix(srv.URL, "https://"), srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestUpdateDevicePublicIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		updateReason   string
+		registerReason string
+		wantActions    []string
+		wantErr        bool
+		wantDeleted    bool
+	}{
+		{
+			"updated in place",
+			"",
+			"",
+			[]string{"update_cloudwan_device_info"},
+			false,
+			false,
+		},
+		{
+			"controller can not change public ip",
+			"Cannot change public IP of a registered device.",
+			"",
+			[]string{"update_cloudwan_device_info", "deregister_cloudwan_device", "register_cloudwan_device",
+				"list_cloudwan_devices_summary", "add_resource_tags"},
+			false,
+			false,
+		},
+		{
+			"other error",
+			"Service temporarily unavailable, public IP not changed",
+			"",
+			[]string{"update_cloudwan_device_info"},
+			true,
+			false,
+		},
+		{
+			"registering again fails",
+			"Cannot change public IP of a registered device.",
+			"device limit reached",
+			[]string{"update_cloudwan_device_info", "deregister_cloudwan_device", "register_cloudwan_device"},
+			true,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actions []string
+			client := newDeviceTestClient(t, func(w http.ResponseWriter, action string, r *http.Request) {
+				actions = append(actions, action)
+				switch {
+				case action == "update_cloudwan_device_info" && tt.updateReason != "":
+					fmt.Fprintf(w, `{"return":false,"reason":%q}`, tt.updateReason)
+				case action == "register_cloudwan_device" && tt.registerReason != "":
+					fmt.Fprintf(w, `{"return":false,"reason":%q}`, tt.registerReason)
+				case action == "list_cloudwan_devices_summary":
+					fmt.Fprint(w, `{"return":true,"results":[{"rgw_name":"device","public_ip":"1.2.3.5"}]}`)
+				default:
+					fmt.Fprint(w, `{"return":true}`)
+				}
+			})
+
+			r := resourceAviatrixDeviceRegistration()
+			state := &terraform.InstanceState{
+				ID: "device",
+				Attributes: map[string]string{
+					"name":      "device",
+					"public_ip": "1.2.3.4",
+					"username":  "ec2-user",
+					"password":  "password",
+					"host_os":   "aviatrix",
+					"ssh_port":  "22",
+					"tags.%":    "1",
+					"tags.env":  "prod",
+				},
+			}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":      "device",
+				"public_ip": "1.2.3.5",
+				"username":  "ec2-user",
+				"password":  "password",
+				"host_os":   "aviatrix",
+				"tags":      map[string]interface{}{"env": "prod"},
+			})
+			diff, err := r.Diff(context.Background(), state, config, client)
+			if err != nil {
+				t.Fatalf("Diff() unexpected error: %v", err)
+			}
+			newState, diags := r.Apply(context.Background(), state, diff, client)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("Apply() diagnostics = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if !reflect.DeepEqual(actions, tt.wantActions) {
+				t.Errorf("Apply() actions = %v, want %v", actions, tt.wantActions)
+			}
+			if deleted := newState == nil || newState.ID == ""; deleted != tt.wantDeleted {
+				t.Errorf("Apply() removed the device from the state = %t, want %t", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestReadDeviceConnectionStatusError(t *testing.T) {
+	client := newDeviceTestClient(t, func(w http.ResponseWriter, action string, r *http.Request) {
+		switch action {
+		case "list_cloudwan_devices_summary":
+			fmt.Fprint(w, `{"return":true,"results":[{"rgw_name":"device","public_ip":"1.2.3.4"}]}`)
+		case "get_cloudwan_device_connection_status":
+			fmt.Fprint(w, `{"return":false,"reason":"Valid action required: get_cloudwan_device_connection_status"}`)
+		default:
+			fmt.Fprint(w, `{"return":true,"results":{}}`)
+		}
+	})
+
+	state := &terraform.InstanceState{
+		ID: "device",
+		Attributes: map[string]string{
+			"name":              "device",
+			"public_ip":         "1.2.3.4",
+			"connection_status": "connected",
+		},
+	}
+	newState, diags := resourceAviatrixDeviceRegistration().RefreshWithoutUpgrade(context.Background(), state, client)
+	if diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() unexpected error: %v", diags)
+	}
+	if got := newState.Attributes["connection_status"]; got != "connected" {
+		t.Errorf("connection_status = %q, want the last known status %q", got, "connected")
+	}
+}
+
+func TestReadDeviceNotCached(t *testing.T) {
+	publicIP := "1.2.3.4"
+	client := newDeviceTestClient(t, func(w http.ResponseWriter, action string, r *http.Request) {
+		switch action {
+		case "list_cloudwan_devices_summary":
+			fmt.Fprintf(w, `{"return":true,"results":[{"rgw_name":"device","hostname":%q}]}`, publicIP)
+		default:
+			fmt.Fprint(w, `{"return":true,"results":{}}`)
+		}
+	})
+
+	state := &terraform.InstanceState{
+		ID:         "device",
+		Attributes: map[string]string{"name": "device", "public_ip": "1.2.3.4"},
+	}
+	r := resourceAviatrixDeviceRegistration()
+	if _, diags := r.RefreshWithoutUpgrade(context.Background(), state, client); diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() unexpected error: %v", diags)
+	}
+
+	// a change made outside of Terraform shows up in the next read through the same client
+	publicIP = "5.6.7.8"
+	newState, diags := r.RefreshWithoutUpgrade(context.Background(), state, client)
+	if diags.HasError() {
+		t.Fatalf("RefreshWithoutUpgrade() unexpected error: %v", diags)
+	}
+	if got := newState.Attributes["public_ip"]; got != publicIP {
+		t.Errorf("public_ip = %q, want %q", got, publicIP)
+	}
+}
+
+func TestReadDeviceHostKeyFingerprintChanged(t *testing.T) {
+	client := newDeviceTestClient(t, func(w http.ResponseWriter, action string, r *http.Request) {
+		switch action {
+		case "list_cloudwan_devices_summary":
+			fmt.Fprint(w, `{"return":true,"results":[{"rgw_name":"device","hostname":"1.2.3.4","host_key_fingerprint":"SHA256:new"}]}`)
+		default:
+			fmt.Fprint(w, `{"return":true,"results":{}}`)
+		}
+	})
+
+	tests := []struct {
+		name           string
+		oldFingerprint string
+		wantWarning    bool
+	}{
+		{"unchanged", "SHA256:new", false},
+		{"first read", "", false},
+		{"changed", "SHA256:old", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "device",
+				Attributes: map[string]string{
+					"name":                 "device",
+					"public_ip":            "1.2.3.4",
+					"host_key_fingerprint": tt.oldFingerprint,
+				},
+			}
+			newState, diags := resourceAviatrixDeviceRegistration().RefreshWithoutUpgrade(context.Background(), state, client)
+			if diags.HasError() {
+				t.Fatalf("RefreshWithoutUpgrade() unexpected error: %v", diags)
+			}
+			if got := newState.Attributes["host_key_fingerprint"]; got != "SHA256:new" {
+				t.Errorf("host_key_fingerprint = %q, want the observed %q", got, "SHA256:new")
+			}
+			if gotWarning := len(diags) > 0; gotWarning != tt.wantWarning {
+				t.Errorf("RefreshWithoutUpgrade() diagnostics = %v, want a warning: %v", diags, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestValidateZipCode(t *testing.T) {
+	tests := []struct {
+		country string
+		zipCode string
+		wantErr bool
+	}{
+		{"US", "95054", false},
+		{"US", "95054-1234", false},
+		{"us", "950541234", false},
+		{"US", "9505", true},
+		{"US", "ABCDE", true},
+		{"CA", "K1A 0B1", false},
+		{"CA", "12345", true},
+		{"GB", "SW1A 1AA", false},
+		{"GB", "M1 1AE", false},
+		{"GB", "123456", true},
+		{"DE", "10115", false},
+		{"DE", "1011", true},
+		{"XX", "anything", false},
+		{"US", "", false},
+		{"", "95054", false},
+	}
+	for _, tt := range tests {
+		err := validateZipCode(tt.country, tt.zipCode)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateZipCode(%q, %q) error = %v, wantErr %v", tt.country, tt.zipCode, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsManagedOutsideTerraform(t *testing.T) {
+	tests := []struct {
+		managedBy string
+		want      bool
+	}{
+		{"terraform", false},
+		{"Terraform", false},
+		{"", false},
+		{"ui", true},
+		{"api", true},
+	}
+	for _, tt := range tests {
+		if got := isManagedOutsideTerraform(tt.managedBy); got != tt.want {
+			t.Errorf("isManagedOutsideTerraform(%q) = %t, want %t", tt.managedBy, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDeviceRegion(t *testing.T) {
+	tests := []struct {
+		name      string
+		region    string
+		zone      string
+		cloudType int
+		wantErr   bool
+	}{
+		{"not set", "", "", goaviatrix.AWS, false},
+		{"aws region", "us-east-1", "us-east-1a", goaviatrix.AWS, false},
+		{"region of other cloud", "eastus", "", goaviatrix.AWS, true},
+		{"unknown region", "mars-north-1", "", goaviatrix.AWS, true},
+		{"without cloud type", "mars-north-1", "", 0, false},
+		{"cloud type without known regions", "us-gov-west-1", "us-gov-west-1a", goaviatrix.AWSGov, false},
+		{"zone of other region", "us-east-1", "us-west-2a", goaviatrix.AWS, true},
+		{"azure zone", "westeurope", "2", goaviatrix.Azure, false},
+		{"invalid azure zone", "westeurope", "westeurope-a", goaviatrix.Azure, true},
+		{"oci availability domain", "us-ashburn-1", "AD-1", goaviatrix.OCI, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDeviceRegion(tt.region, tt.zone, tt.cloudType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDeviceRegion(%q, %q, %d) error = %v, wantErr %v", tt.region, tt.zone, tt.cloudType, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeviceRegionWarning(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAviatrixDeviceRegistration().Schema, map[string]interface{}{
+		"name":   "device",
+		"region": "mars-north-1",
+	})
+	diags := deviceRegionWarning(d, goaviatrix.AWS)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("deviceRegionWarning() = %v, want a single warning", diags)
+	}
+	if diags := deviceRegionWarning(d, goaviatrix.AWSGov); len(diags) != 0 {
+		t.Errorf("deviceRegionWarning() for a cloud type without known regions = %v, want none", diags)
+	}
+}
+
+func TestValidateDevicePublicIPHa(t *testing.T) {
+	tests := []struct {
+		name       string
+		publicIP   string
+		publicIPHa string
+		wantErr    bool
+	}{
+		{"single device", "1.2.3.4", "", false},
+		{"ha pair", "1.2.3.4", "1.2.3.5", false},
+		{"same ip", "1.2.3.4", "1.2.3.4", true},
+		{"same ipv6 written differently", "2001:db8::1", "2001:0db8:0:0:0:0:0:1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDevicePublicIPHa(tt.publicIP, tt.publicIPHa)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDevicePublicIPHa(%q, %q) error = %v, wantErr %v", tt.publicIP, tt.publicIPHa, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsCaagHostOSMismatch(t *testing.T) {
+	tests := []struct {
+		isCaag bool
+		hostOS string
+		want   bool
+	}{
+		{true, "aviatrix", false},
+		{false, "ios", false},
+		{false, "aviatrix", false},
+		{true, "ios", true},
+		{true, "IOS", true},
+	}
+	for _, tt := range tests {
+		if got := isCaagHostOSMismatch(tt.isCaag, tt.hostOS); got != tt.want {
+			t.Errorf("isCaagHostOSMismatch(%t, %q) = %t, want %t", tt.isCaag, tt.hostOS, got, tt.want)
+		}
+	}
+}
@@ -24,6 +24,7 @@ func resourceAviatrixSpokeGateway() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateTagsDiff,
 
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
@@ -780,22 +781,29 @@ func resourceAviatrixSpokeGatewayCreate(d *schema.ResourceData, meta interface{}
 			return errors.New("failed to create spoke gateway: adding tags is only supported for AWS (1), Azure (8), AzureGov (32), AWSGov (256), AWSChina (1024), AzureChina (2048), AWS Top Secret (16384) or AWS Secret (32768)")
 		}
 
+		tags := &goaviatrix.Tags{
+			ResourceType: "gw",
+			ResourceName: gateway.GwName,
+			CloudType:    gateway.CloudType,
+		}
 		if tagListOk {
 			tagList := d.Get("tag_list").([]interface{})
 			tagListStr := goaviatrix.ExpandStringList(tagList)
 			tagListStr = goaviatrix.TagListStrColon(tagListStr)
-			gateway.TagList = strings.Join(tagListStr, ",")
+			tags.TagList = strings.Join(tagListStr, ",")
 		} else {
 			tagsMap, err := extractTags(d, gateway.CloudType)
 			if err != nil {
 				return fmt.Errorf("error creating tags for spoke gateway: %v", err)
 			}
-			tagJson, err := TagsMapToJson(tagsMap)
-			if err != nil {
-				return fmt.Errorf("failed to add tags whenc creating spoke gateway: %v", err)
-			}
-			gateway.TagJson = tagJson
+			tags.Tags = tagsMap
+		}
+		// ignored tags and the tag prefix apply to the tags of a new gateway as to updated ones
+		if err := client.PrepareCreateTags(tags); err != nil {
+			return fmt.Errorf("failed to add tags when creating spoke gateway: %v", err)
 		}
+		gateway.TagList = tags.TagList
+		gateway.TagJson = tags.TagJson
 	}
 
 	enableSpotInstance := d.Get("enable_spot_instance").(bool)
@@ -1382,9 +1390,13 @@ func resourceAviatrixSpokeGatewayRead(d *schema.ResourceData, meta interface{})
 	}
 
 	if goaviatrix.IsCloudType(gw.CloudType, goaviatrix.AWSRelatedCloudTypes|goaviatrix.AzureArmRelatedCloudTypes) {
+		gwTags, err := client.GetGatewayTags(gw)
+		if err != nil {
+			return fmt.Errorf("failed to get tags for gateway %s: %v", gw.GwName, err)
+		}
 		if _, ok := d.GetOk("tag_list"); ok {
-			tagList := make([]string, 0, len(gw.Tags))
-			for key, val := range gw.Tags {
+			tagList := make([]string, 0, len(gwTags))
+			for key, val := range gwTags {
 				str := key + ":" + val
 				tagList = append(tagList, str)
 			}
@@ -1402,7 +1414,7 @@ func resourceAviatrixSpokeGatewayRead(d *schema.ResourceData, meta interface{})
 				}
 			}
 		} else {
-			if err := d.Set("tags", gw.Tags); err != nil {
+			if err := d.Set("tags", gwTags); err != nil {
 				log.Printf("[WARN] Error setting tags for (%s): %s", d.Id(), err)
 			}
 		}
@@ -1672,6 +1684,8 @@ func resourceAviatrixSpokeGatewayUpdate(d *schema.ResourceData, meta interface{}
 				return fmt.Errorf("failed to update tags for spoke gateway: %v", err)
 			}
 			tags.TagJson = tagJson
+			oldTags, _ := d.GetChange("tags")
+			logTagsDiff("spoke gateway "+tags.ResourceName, tagsMapFromInterface(oldTags), tagsMap)
 			err = client.UpdateTags(tags)
 			if err != nil {
 				return fmt.Errorf("failed to update tags for spoke gateway: %v", err)
@@ -28,6 +28,7 @@ func resourceAviatrixTransitGateway() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateTagsDiff,
 
 		SchemaVersion: 1,
 		MigrateState:  resourceAviatrixTransitGatewayMigrateState,
@@ -989,22 +990,29 @@ func resourceAviatrixTransitGatewayCreate(d *schema.ResourceData, meta interface
 			return errors.New("error creating transit gateway: adding tags is only supported for AWS (1), Azure (8), AzureGov (32), AWSGov (256), AWSChina (1024), AzureChina (2048), AWS Top Secret (16384) and AWS Secret (32768)")
 		}
 
+		tags := &goaviatrix.Tags{
+			ResourceType: "gw",
+			ResourceName: gateway.GwName,
+			CloudType:    gateway.CloudType,
+		}
 		if tagListOk {
 			tagList := d.Get("tag_list").([]interface{})
 			tagListStr := goaviatrix.ExpandStringList(tagList)
 			tagListStr = goaviatrix.TagListStrColon(tagListStr)
-			gateway.TagList = strings.Join(tagListStr, ",")
+			tags.TagList = strings.Join(tagListStr, ",")
 		} else {
 			tagsMap, err := extractTags(d, gateway.CloudType)
 			if err != nil {
 				return fmt.Errorf("error creating tags for transit gateway: %v", err)
 			}
-			tagJson, err := TagsMapToJson(tagsMap)
-			if err != nil {
-				return fmt.Errorf("failed to add tags when creating transit gateway: %v", err)
-			}
-			gateway.TagJson = tagJson
+			tags.Tags = tagsMap
+		}
+		// ignored tags and the tag prefix apply to the tags of a new gateway as to updated ones
+		if err := client.PrepareCreateTags(tags); err != nil {
+			return fmt.Errorf("failed to add tags when creating transit gateway: %v", err)
 		}
+		gateway.TagList = tags.TagList
+		gateway.TagJson = tags.TagJson
 	}
 
 	enableSpotInstance := d.Get("enable_spot_instance").(bool)
@@ -1701,9 +1709,13 @@ func resourceAviatrixTransitGatewayRead(d *schema.ResourceData, meta interface{}
 	d.Set("lan_interface_cidr", lanCidr)
 
 	if goaviatrix.IsCloudType(gw.CloudType, goaviatrix.AWSRelatedCloudTypes|goaviatrix.AzureArmRelatedCloudTypes) {
+		gwTags, err := client.GetGatewayTags(gw)
+		if err != nil {
+			return fmt.Errorf("failed to get tags for gateway %s: %v", gw.GwName, err)
+		}
 		if _, ok := d.GetOk("tag_list"); ok {
-			tagList := make([]string, 0, len(gw.Tags))
-			for key, val := range gw.Tags {
+			tagList := make([]string, 0, len(gwTags))
+			for key, val := range gwTags {
 				str := key + ":" + val
 				tagList = append(tagList, str)
 			}
@@ -1721,7 +1733,7 @@ func resourceAviatrixTransitGatewayRead(d *schema.ResourceData, meta interface{}
 				}
 			}
 		} else {
-			if err := d.Set("tags", gw.Tags); err != nil {
+			if err := d.Set("tags", gwTags); err != nil {
 				log.Printf("[WARN] Error setting tags for (%s): %s", d.Id(), err)
 			}
 		}
@@ -2239,6 +2251,8 @@ func resourceAviatrixTransitGatewayUpdate(d *schema.ResourceData, meta interface
 				return fmt.Errorf("failed to update tags for transit gateway: %v", err)
 			}
 			tags.TagJson = tagJson
+			oldTags, _ := d.GetChange("tags")
+			logTagsDiff("transit gateway "+tags.ResourceName, tagsMapFromInterface(oldTags), tagsMap)
 			err = client.UpdateTags(tags)
 			if err != nil {
 				return fmt.Errorf("failed to update tags for transit gateway: %v", err)
@@ -378,6 +378,11 @@ func resourceAviatrixTransitVpcRead(d *schema.ResourceData, meta interface{}) er
 			ResourceName: d.Get("gw_name").(string),
 		}
 		tagList, err := client.GetTags(tags)
+		if err == goaviatrix.ErrNotFound {
+			// only the gateway lookup above decides whether the gateway exists
+			log.Printf("[WARN] controller found no tags for gateway %s, clearing its tag_list", gateway.GwName)
+			tagList, err = nil, nil
+		}
 		if err != nil {
 			return fmt.Errorf("unable to read tag_list for gateway: %v due to %v", gateway.GwName, err)
 		}
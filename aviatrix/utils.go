@@ -1,10 +1,18 @@
 package aviatrix
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"net"
+	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -33,6 +41,124 @@ func validateCloudType(i interface{}, k string) (warnings []string, errors []err
 	return validation.IntInSlice(goaviatrix.GetSupportedClouds())(i, k)
 }
 
+// isoCountryCodes is the list of ISO 3166-1 alpha-2 country codes.
+var isoCountryCodes = []string{
+	"AD", "AE", "AF", "AG", "AI", "AL", "AM", "AO", "AQ", "AR", "AS", "AT", "AU", "AW", "AX", "AZ",
+	"BA", "BB", "BD", "BE", "BF", "BG", "BH", "BI", "BJ", "BL", "BM", "BN", "BO", "BQ", "BR", "BS",
+	"BT", "BV", "BW", "BY", "BZ", "CA", "CC", "CD", "CF", "CG", "CH", "CI", "CK", "CL", "CM", "CN",
+	"CO", "CR", "CU", "CV", "CW", "CX", "CY", "CZ", "DE", "DJ", "DK", "DM", "DO", "DZ", "EC", "EE",
+	"EG", "EH", "ER", "ES", "ET", "FI", "FJ", "FK", "FM", "FO", "FR", "GA", "GB", "GD", "GE", "GF",
+	"GG", "GH", "GI", "GL", "GM", "GN", "GP", "GQ", "GR", "GS", "GT", "GU", "GW", "GY", "HK", "HM",
+	"HN", "HR", "HT", "HU", "ID", "IE", "IL", "IM", "IN", "IO", "IQ", "IR", "IS", "IT", "JE", "JM",
+	"JO", "JP", "KE", "KG", "KH", "KI", "KM", "KN", "KP", "KR", "KW", "KY", "KZ", "LA", "LB", "LC",
+	"LI", "LK", "LR", "LS", "LT", "LU", "LV", "LY", "MA", "MC", "MD", "ME", "MF", "MG", "MH", "MK",
+	"ML", "MM", "MN", "MO", "MP", "MQ", "MR", "MS", "MT", "MU", "MV", "MW", "MX", "MY", "MZ", "NA",
+	"NC", "NE", "NF", "NG", "NI", "NL", "NO", "NP", "NR", "NU", "NZ", "OM", "PA", "PE", "PF", "PG",
+	"PH", "PK", "PL", "PM", "PN", "PR", "PS", "PT", "PW", "PY", "QA", "RE", "RO", "RS", "RU", "RW",
+	"SA", "SB", "SC", "SD", "SE", "SG", "SH", "SI", "SJ", "SK", "SL", "SM", "SN", "SO", "SR", "SS",
+	"ST", "SV", "SX", "SY", "SZ", "TC", "TD", "TF", "TG", "TH", "TJ", "TK", "TL", "TM", "TN", "TO",
+	"TR", "TT", "TV", "TW", "TZ", "UA", "UG", "UM", "US", "UY", "UZ", "VA", "VC", "VE", "VG", "VI",
+	"VN", "VU", "WF", "WS", "YE", "YT", "ZA", "ZM", "ZW",
+}
+
+// validateISOCountryCode is a SchemaValidateFunc for ISO 3166-1 alpha-2 country codes. Case insensitive.
+func validateISOCountryCode(i interface{}, k string) (warnings []string, errors []error) {
+	return validation.StringInSlice(isoCountryCodes, true)(i, k)
+}
+
+var hostnameRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*\.?$`)
+
+// validateIPAddressNotHostname is a SchemaValidateFunc for IP addresses that explicitly rejects
+// hostnames, which would otherwise fail with a generic validation error.
+func validateIPAddressNotHostname(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if net.ParseIP(v) != nil {
+		return warnings, errors
+	}
+	if hostnameRegexp.MatchString(v) && strings.ContainsAny(strings.ToLower(v), "abcdefghijklmnopqrstuvwxyz") {
+		errors = append(errors, fmt.Errorf("%s must be an IP address, got hostname '%s'. "+
+			"Hostnames are not resolved, resolve it to an IP address first, e.g. with a DNS data source", k, v))
+	} else {
+		errors = append(errors, fmt.Errorf("%s must be an IP address, got '%s'", k, v))
+	}
+	return warnings, errors
+}
+
+// validateSSHUsername is a SchemaValidateFunc for SSH usernames, which must not be empty or contain whitespace.
+func validateSSHUsername(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if v == "" {
+		errors = append(errors, fmt.Errorf("%s must not be empty", k))
+	} else if strings.IndexFunc(v, unicode.IsSpace) >= 0 {
+		errors = append(errors, fmt.Errorf("%s must not contain whitespace, got '%s'", k, v))
+	}
+	return warnings, errors
+}
+
+// maxDeviceDescriptionLength is the longest device description the controller accepts
+const maxDeviceDescriptionLength = 255
+
+// validateDeviceDescription is a SchemaValidateFunc for device descriptions, which the controller rejects or
+// mangles if they are too long or contain control characters such as newlines.
+func validateDeviceDescription(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if length := utf8.RuneCountInString(v); length > maxDeviceDescriptionLength {
+		errors = append(errors, fmt.Errorf("%s must be at most %d characters long, got %d characters", k, maxDeviceDescriptionLength, length))
+	}
+	if i := strings.IndexFunc(v, unicode.IsControl); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(v[i:])
+		errors = append(errors, fmt.Errorf("%s must not contain control characters, got %q at position %d", k, r, i))
+	}
+	return warnings, errors
+}
+
+// validateReadableFile is a SchemaValidateFunc that checks that the path is a regular file that can be read,
+// e.g. a private key file, so that a wrong path fails at plan time instead of during apply.
+func validateReadableFile(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if err := checkReadableFile(k, v); err != nil {
+		errors = append(errors, err)
+	}
+	return warnings, errors
+}
+
+// checkReadableFile returns an error if the path of attribute k is not a regular file that can be read
+func checkReadableFile(k, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s '%s' not found/readable: %v", k, path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("%s '%s' not found/readable: %v", k, path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s '%s' not found/readable: is a directory", k, path)
+	}
+	return nil
+}
+
 func DiffSuppressFuncString(k, old, new string, d *schema.ResourceData) bool {
 	oldValue := strings.Split(old, ",")
 	newValue := strings.Split(new, ",")
@@ -76,6 +202,43 @@ func DiffSuppressFuncIgnoreSpaceOnlyInString(k, old, new string, d *schema.Resou
 	return true
 }
 
+// DiffSuppressFuncIgnoreSurroundingSpace suppresses differences in leading and trailing whitespace,
+// for values the controller trims.
+func DiffSuppressFuncIgnoreSurroundingSpace(k, old, new string, d *schema.ResourceData) bool {
+	return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
+
+// DiffSuppressFuncEqualIP suppresses differences between notations of the same IP address, e.g. an
+// IPv6 address the controller returns in its shortest form.
+func DiffSuppressFuncEqualIP(k, old, new string, d *schema.ResourceData) bool {
+	oldIP := net.ParseIP(old)
+	return oldIP != nil && oldIP.Equal(net.ParseIP(new))
+}
+
+// defaultSSHPort is the SSH port of devices that do not set one
+const defaultSSHPort = 22
+
+// sshPortOrDefault returns port, or the default SSH port if port is 0. Some controller versions
+// return 0 for devices registered on the default port.
+func sshPortOrDefault(port int) int {
+	if port == 0 {
+		return defaultSSHPort
+	}
+	return port
+}
+
+// DiffSuppressFuncDefaultSSHPort suppresses differences between an unset or zero SSH port and the
+// default SSH port.
+func DiffSuppressFuncDefaultSSHPort(k, old, new string, d *schema.ResourceData) bool {
+	normalize := func(port string) string {
+		if port == "" || port == "0" {
+			return strconv.Itoa(defaultSSHPort)
+		}
+		return port
+	}
+	return normalize(old) == normalize(new)
+}
+
 func setConfigValueIfEquivalent(d *schema.ResourceData, k string, fromConfig, fromAPI []string) error {
 	if goaviatrix.Equivalent(fromConfig, fromAPI) {
 		return d.Set(k, fromConfig)
@@ -116,6 +279,66 @@ var (
 	gcpTagMatcher   = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}_-]*$`)
 )
 
+// tagRules are the constraints a cloud provider puts on tag keys and values. Longer keys are
+// truncated by the controller, which can make different keys collide.
+type tagRules struct {
+	matcher        *regexp.Regexp
+	maxKeyLength   int
+	maxValueLength int
+}
+
+// tagRulesForCloudType returns the tag constraints of the cloud provider of cloudType
+func tagRulesForCloudType(cloudType int) tagRules {
+	if goaviatrix.IsCloudType(cloudType, goaviatrix.GCPRelatedCloudTypes) {
+		return tagRules{matcher: gcpTagMatcher, maxKeyLength: 63, maxValueLength: 63}
+	}
+	if goaviatrix.IsCloudType(cloudType, goaviatrix.AzureArmRelatedCloudTypes) {
+		return tagRules{matcher: azureTagMatcher, maxKeyLength: 512, maxValueLength: 256}
+	}
+	return tagRules{matcher: awsTagMatcher, maxKeyLength: 128, maxValueLength: 256}
+}
+
+// validateTags checks the tags against the length and character set constraints of the cloud
+// provider of cloudType.
+func validateTags(tags map[string]string, cloudType int) error {
+	rules := tagRulesForCloudType(cloudType)
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val := tags[key]
+		if !rules.matcher.MatchString(key + val) {
+			return fmt.Errorf("illegal characters in tags: tag %q", key)
+		}
+		if utf8.RuneCountInString(key) > rules.maxKeyLength {
+			return fmt.Errorf("tag key %q is longer than %d characters", key, rules.maxKeyLength)
+		}
+		if utf8.RuneCountInString(val) > rules.maxValueLength {
+			return fmt.Errorf("value of tag %q is longer than %d characters", key, rules.maxValueLength)
+		}
+	}
+	return nil
+}
+
+// validateTagsDiff is a CustomizeDiffFunc that validates 'tags' against the constraints of the
+// cloud provider of 'cloud_type' at plan time.
+func validateTagsDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("tags") || !d.NewValueKnown("cloud_type") {
+		return nil
+	}
+	tags := tagsMapFromInterface(d.Get("tags"))
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := validateTags(tags, d.Get("cloud_type").(int)); err != nil {
+		return fmt.Errorf("invalid tags: %v", err)
+	}
+	return nil
+}
+
 func extractTags(d *schema.ResourceData, cloudType int) (map[string]string, error) {
 	tags, ok := d.GetOk("tags")
 	if !ok {
@@ -124,24 +347,9 @@ func extractTags(d *schema.ResourceData, cloudType int) (map[string]string, erro
 	if !goaviatrix.IsCloudType(cloudType, goaviatrix.AWSRelatedCloudTypes|goaviatrix.GCPRelatedCloudTypes|goaviatrix.AzureArmRelatedCloudTypes) {
 		return nil, fmt.Errorf("adding tags is only supported for AWS (1), GCP (4), Azure (8), AWSGov (256), AWSChina (1024) and AzureChina (2048)")
 	}
-	tagsMap := tags.(map[string]interface{})
-	tagsStrMap := make(map[string]string, len(tagsMap))
-	var matcher *regexp.Regexp
-	if goaviatrix.IsCloudType(cloudType, goaviatrix.GCPRelatedCloudTypes) {
-		matcher = gcpTagMatcher
-	} else if goaviatrix.IsCloudType(cloudType, goaviatrix.AzureArmRelatedCloudTypes) {
-		matcher = azureTagMatcher
-	} else {
-		matcher = awsTagMatcher
-	}
-
-	for key, val := range tagsMap {
-		valStr := fmt.Sprint(val)
-		matched := matcher.MatchString(key + valStr)
-		if !matched {
-			return nil, fmt.Errorf("illegal characters in tags")
-		}
-		tagsStrMap[key] = valStr
+	tagsStrMap := tagsMapFromInterface(tags)
+	if err := validateTags(tagsStrMap, cloudType); err != nil {
+		return nil, err
 	}
 	return tagsStrMap, nil
 }
@@ -159,6 +367,89 @@ func TagsMapToJson(tagsMap map[string]string) (string, error) {
 	return tagsMapStr, nil
 }
 
+// tagsMapFromInterface converts the value of a 'tags' attribute to a map of strings
+func tagsMapFromInterface(tags interface{}) map[string]string {
+	tagsMap := make(map[string]string)
+	for key, val := range tags.(map[string]interface{}) {
+		tagsMap[key] = fmt.Sprint(val)
+	}
+	return tagsMap
+}
+
+// diffTags returns the sorted keys of the tags added, removed and modified by changing the tags from
+// oldTags to newTags.
+func diffTags(oldTags, newTags map[string]string) (added, removed, modified []string) {
+	for key, newVal := range newTags {
+		oldVal, ok := oldTags[key]
+		if !ok {
+			added = append(added, key)
+		} else if oldVal != newVal {
+			modified = append(modified, key)
+		}
+	}
+	for key := range oldTags {
+		if _, ok := newTags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(modified)
+	return added, removed, modified
+}
+
+// logTagsDiff logs the keys of the tags of a resource that are added, removed and modified by an
+// update, so that the changes show up in the apply log.
+func logTagsDiff(resource string, oldTags, newTags map[string]string) {
+	added, removed, modified := diffTags(oldTags, newTags)
+	if len(added) == 0 && len(removed) == 0 && len(modified) == 0 {
+		return
+	}
+	log.Printf("[INFO] updating tags of %s: added %v, removed %v, modified %v", resource, added, removed, modified)
+}
+
+// parseTagJson parses tags given as a JSON object of string keys and values
+func parseTagJson(tagJson string) (map[string]string, error) {
+	var tagsMap map[string]string
+	if err := json.Unmarshal([]byte(tagJson), &tagsMap); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of string keys and values: %v", err)
+	}
+	if tagsMap == nil {
+		return nil, fmt.Errorf("expected a JSON object of string keys and values, got %s", tagJson)
+	}
+	return tagsMap, nil
+}
+
+// validateTagJson is a SchemaValidateFunc for tags given as a JSON object of string keys and values.
+func validateTagJson(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if _, err := parseTagJson(v); err != nil {
+		errors = append(errors, fmt.Errorf("invalid %s: %v", k, err))
+	}
+	return warnings, errors
+}
+
+// DiffSuppressFuncTagJson suppresses differences between tag JSON objects with the same tags, e.g. in
+// a different order or with different whitespace.
+func DiffSuppressFuncTagJson(k, old, new string, d *schema.ResourceData) bool {
+	oldTags, err := parseTagJson(old)
+	if err != nil {
+		return false
+	}
+	newTags, err := parseTagJson(new)
+	if err != nil {
+		return false
+	}
+	oldJson, _ := TagsMapToJson(oldTags)
+	newJson, _ := TagsMapToJson(newTags)
+	return oldJson == newJson
+}
+
 // validateAzureEipNameResourceGroup is a SchemaValidateFunc for Azure custom EIP name and resource group.
 func validateAzureEipNameResourceGroup(i interface{}, k string) (warnings []string, errors []error) {
 	v, ok := i.(string)
@@ -0,0 +1,626 @@
+package aviatrix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AviatrixSystems/terraform-provider-aviatrix/v2/goaviatrix"
+)
+
+func TestValidateISOCountryCode(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Input       interface{}
+		ExpectedErr string
+	}{
+		{
+			"valid",
+			"US",
+			"",
+		},
+		{
+			"valid lowercase",
+			"gb",
+			"",
+		},
+		{
+			"valid mixed case",
+			"De",
+			"",
+		},
+		{
+			"three letters",
+			"USA",
+			`expected test to be one of [`,
+		},
+		{
+			"unknown code",
+			"XX",
+			`expected test to be one of [`,
+		},
+		{
+			"wrong type",
+			1,
+			`expected type of test to be string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := validateISOCountryCode(tc.Input, "test")
+			if tc.ExpectedErr != "" {
+				if len(errs) < 1 {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(errs[0].Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, errs[0].Error())
+				}
+			} else {
+				if len(errs) > 0 {
+					t.Fatalf("test case %q expected no error, got %q", tc.Name, errs[0].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateIPAddressNotHostname(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Input       interface{}
+		ExpectedErr string
+	}{
+		{
+			"ipv4",
+			"10.0.0.1",
+			"",
+		},
+		{
+			"ipv6",
+			"2001:db8::1",
+			"",
+		},
+		{
+			"hostname",
+			"router.example.com",
+			`test must be an IP address, got hostname 'router.example.com'`,
+		},
+		{
+			"single label hostname",
+			"router1",
+			`test must be an IP address, got hostname 'router1'`,
+		},
+		{
+			"invalid ip",
+			"10.0.0.256",
+			`test must be an IP address, got '10.0.0.256'`,
+		},
+		{
+			"wrong type",
+			1,
+			`expected type of test to be string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := validateIPAddressNotHostname(tc.Input, "test")
+			if tc.ExpectedErr != "" {
+				if len(errs) < 1 {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(errs[0].Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, errs[0].Error())
+				}
+			} else {
+				if len(errs) > 0 {
+					t.Fatalf("test case %q expected no error, got %q", tc.Name, errs[0].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSSHUsername(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Input       interface{}
+		ExpectedErr string
+	}{
+		{
+			"username",
+			"ec2-user",
+			"",
+		},
+		{
+			"empty",
+			"",
+			`test must not be empty`,
+		},
+		{
+			"space",
+			"ec2 user",
+			`test must not contain whitespace, got 'ec2 user'`,
+		},
+		{
+			"trailing newline",
+			"admin\n",
+			`test must not contain whitespace`,
+		},
+		{
+			"wrong type",
+			1,
+			`expected type of test to be string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := validateSSHUsername(tc.Input, "test")
+			if tc.ExpectedErr != "" {
+				if len(errs) < 1 {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(errs[0].Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, errs[0].Error())
+				}
+			} else {
+				if len(errs) > 0 {
+					t.Fatalf("test case %q expected no error, got %q", tc.Name, errs[0].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTagJson(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Input       interface{}
+		ExpectedErr string
+	}{
+		{
+			"tags",
+			`{"name": "device", "cidrs": "10.0.0.0/16,10.1.0.0/16"}`,
+			"",
+		},
+		{
+			"no tags",
+			`{}`,
+			"",
+		},
+		{
+			"nested object",
+			`{"name": {"first": "device"}}`,
+			`invalid test: expected a JSON object of string keys and values`,
+		},
+		{
+			"number value",
+			`{"count": 1}`,
+			`invalid test: expected a JSON object of string keys and values`,
+		},
+		{
+			"array",
+			`["name", "device"]`,
+			`invalid test: expected a JSON object of string keys and values`,
+		},
+		{
+			"null",
+			`null`,
+			`invalid test: expected a JSON object of string keys and values`,
+		},
+		{
+			"not json",
+			`name:device`,
+			`invalid test: expected a JSON object of string keys and values`,
+		},
+		{
+			"wrong type",
+			1,
+			`expected type of test to be string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := validateTagJson(tc.Input, "test")
+			if tc.ExpectedErr != "" {
+				if len(errs) < 1 {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(errs[0].Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, errs[0].Error())
+				}
+			} else {
+				if len(errs) > 0 {
+					t.Fatalf("test case %q expected no error, got %q", tc.Name, errs[0].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestDiffSuppressFuncTagJson(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{
+			"same tags in different order",
+			`{"a":"1","b":"2"}`,
+			`{ "b": "2", "a": "1" }`,
+			true,
+		},
+		{
+			"different value",
+			`{"a":"1","b":"2"}`,
+			`{"a":"1","b":"3"}`,
+			false,
+		},
+		{
+			"added tag",
+			`{"a":"1"}`,
+			`{"a":"1","b":"2"}`,
+			false,
+		},
+		{
+			"not set before",
+			``,
+			`{"a":"1"}`,
+			false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := DiffSuppressFuncTagJson("tag_json", tc.Old, tc.New, nil); got != tc.Expected {
+				t.Fatalf("test case %q expected %t, got %t", tc.Name, tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestDiffTags(t *testing.T) {
+	tt := []struct {
+		Name             string
+		Old              map[string]string
+		New              map[string]string
+		ExpectedAdded    []string
+		ExpectedRemoved  []string
+		ExpectedModified []string
+	}{
+		{
+			"unchanged",
+			map[string]string{"a": "1"},
+			map[string]string{"a": "1"},
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"added, removed and modified",
+			map[string]string{"a": "1", "b": "2", "c": "3"},
+			map[string]string{"a": "1", "b": "20", "d": "4", "e": "5"},
+			[]string{"d", "e"},
+			[]string{"c"},
+			[]string{"b"},
+		},
+		{
+			"all removed",
+			map[string]string{"b": "2", "a": "1"},
+			nil,
+			nil,
+			[]string{"a", "b"},
+			nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			added, removed, modified := diffTags(tc.Old, tc.New)
+			if !reflect.DeepEqual(added, tc.ExpectedAdded) {
+				t.Errorf("test case %q expected added %v, got %v", tc.Name, tc.ExpectedAdded, added)
+			}
+			if !reflect.DeepEqual(removed, tc.ExpectedRemoved) {
+				t.Errorf("test case %q expected removed %v, got %v", tc.Name, tc.ExpectedRemoved, removed)
+			}
+			if !reflect.DeepEqual(modified, tc.ExpectedModified) {
+				t.Errorf("test case %q expected modified %v, got %v", tc.Name, tc.ExpectedModified, modified)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceDescription(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Input       interface{}
+		ExpectedErr string
+	}{
+		{
+			"description",
+			"Branch office router.",
+			"",
+		},
+		{
+			"maximum length",
+			strings.Repeat("a", maxDeviceDescriptionLength),
+			"",
+		},
+		{
+			"multibyte characters",
+			strings.Repeat("ü", maxDeviceDescriptionLength),
+			"",
+		},
+		{
+			"too long",
+			strings.Repeat("a", 1000),
+			`test must be at most 255 characters long, got 1000 characters`,
+		},
+		{
+			"embedded newline",
+			"Branch office\nrouter.",
+			`test must not contain control characters, got '\n' at position 13`,
+		},
+		{
+			"tab",
+			"Branch\toffice",
+			`test must not contain control characters, got '\t'`,
+		},
+		{
+			"wrong type",
+			1,
+			`expected type of test to be string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := validateDeviceDescription(tc.Input, "test")
+			if tc.ExpectedErr != "" {
+				if len(errs) < 1 {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(errs[0].Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, errs[0].Error())
+				}
+			} else {
+				if len(errs) > 0 {
+					t.Fatalf("test case %q expected no error, got %q", tc.Name, errs[0].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateReadableFile(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(keyFile, []byte("key"), 0600); err != nil {
+		t.Fatalf("could not write key file: %v", err)
+	}
+
+	tt := []struct {
+		Name        string
+		Input       interface{}
+		ExpectedErr string
+	}{
+		{
+			"readable file",
+			keyFile,
+			"",
+		},
+		{
+			"missing file",
+			filepath.Join(dir, "missing.pem"),
+			fmt.Sprintf("test '%s' not found/readable", filepath.Join(dir, "missing.pem")),
+		},
+		{
+			"directory",
+			dir,
+			fmt.Sprintf("test '%s' not found/readable: is a directory", dir),
+		},
+		{
+			"wrong type",
+			1,
+			`expected type of test to be string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := validateReadableFile(tc.Input, "test")
+			if tc.ExpectedErr != "" {
+				if len(errs) < 1 {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(errs[0].Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, errs[0].Error())
+				}
+			} else {
+				if len(errs) > 0 {
+					t.Fatalf("test case %q expected no error, got %q", tc.Name, errs[0].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestDiffSuppressFuncEqualIP(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{
+			"same IPv4 address",
+			"1.2.3.4",
+			"1.2.3.4",
+			true,
+		},
+		{
+			"IPv6 address in shortest form",
+			"2001:db8::1",
+			"2001:0db8:0000:0000:0000:0000:0000:0001",
+			true,
+		},
+		{
+			"different address",
+			"1.2.3.4",
+			"1.2.3.5",
+			false,
+		},
+		{
+			"not set before",
+			"",
+			"1.2.3.4",
+			false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := DiffSuppressFuncEqualIP("public_ip", tc.Old, tc.New, nil); got != tc.Expected {
+				t.Fatalf("test case %q expected %t, got %t", tc.Name, tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestDiffSuppressFuncDefaultSSHPort(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{
+			"explicit default port",
+			"22",
+			"22",
+			true,
+		},
+		{
+			"zero from controller",
+			"0",
+			"22",
+			true,
+		},
+		{
+			"not set before",
+			"",
+			"22",
+			true,
+		},
+		{
+			"zero from controller with custom port",
+			"0",
+			"2222",
+			false,
+		},
+		{
+			"different port",
+			"22",
+			"2222",
+			false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := DiffSuppressFuncDefaultSSHPort("ssh_port", tc.Old, tc.New, nil); got != tc.Expected {
+				t.Fatalf("test case %q expected %t, got %t", tc.Name, tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestSSHPortOrDefault(t *testing.T) {
+	if got := sshPortOrDefault(0); got != 22 {
+		t.Errorf("sshPortOrDefault(0) = %d, want 22", got)
+	}
+	if got := sshPortOrDefault(2222); got != 2222 {
+		t.Errorf("sshPortOrDefault(2222) = %d, want 2222", got)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Tags        map[string]string
+		CloudType   int
+		ExpectedErr string
+	}{
+		{
+			"aws",
+			map[string]string{"Name": "gw", "cidrs": "10.0.0.0/16,10.1.0.0/16"},
+			goaviatrix.AWS,
+			"",
+		},
+		{
+			"aws key too long",
+			map[string]string{strings.Repeat("k", 129): "value"},
+			goaviatrix.AWS,
+			"tag key",
+		},
+		{
+			"aws value too long",
+			map[string]string{"key": strings.Repeat("v", 257)},
+			goaviatrix.AWSGov,
+			`value of tag "key" is longer than 256 characters`,
+		},
+		{
+			"azure key of 512 characters",
+			map[string]string{strings.Repeat("k", 512): "value"},
+			goaviatrix.Azure,
+			"",
+		},
+		{
+			"azure illegal characters",
+			map[string]string{"key": "a,b"},
+			goaviatrix.Azure,
+			`illegal characters in tags: tag "key"`,
+		},
+		{
+			"gcp",
+			map[string]string{"name": "gw-1"},
+			goaviatrix.GCP,
+			"",
+		},
+		{
+			"gcp key too long",
+			map[string]string{strings.Repeat("k", 64): "value"},
+			goaviatrix.GCP,
+			"tag key",
+		},
+		{
+			"gcp value counted in characters",
+			map[string]string{"key": strings.Repeat("ü", 63)},
+			goaviatrix.GCP,
+			"",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateTags(tc.Tags, tc.CloudType)
+			if tc.ExpectedErr != "" {
+				if err == nil {
+					t.Fatalf("test case %q expected an error: %q, got: none", tc.Name, tc.ExpectedErr)
+				}
+				if !strings.HasPrefix(err.Error(), tc.ExpectedErr) {
+					t.Fatalf("test case %q expected an error starting with: %q, got: %q", tc.Name, tc.ExpectedErr, err.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("test case %q expected no error, got %q", tc.Name, err.Error())
+			}
+		})
+	}
+}
@@ -93,14 +93,14 @@ type AccountListResp struct {
 }
 
 func (c *Client) CreateAccount(account *Account) error {
-	account.CID = c.CID
+	account.CID = c.cid()
 	account.Action = "setup_account_profile"
 	return c.PostAPI(account.Action, account, BasicCheck)
 }
 
 func (c *Client) CreateGCPAccount(account *Account) error {
 	params := map[string]string{
-		"CID":                 c.CID,
+		"CID":                 c.cid(),
 		"action":              "setup_account_profile",
 		"account_name":        account.AccountName,
 		"cloud_type":          strconv.Itoa(account.CloudType),
@@ -119,7 +119,7 @@ func (c *Client) CreateGCPAccount(account *Account) error {
 
 func (c *Client) CreateOCIAccount(account *Account) error {
 	params := map[string]string{
-		"CID":                c.CID,
+		"CID":                c.cid(),
 		"action":             "setup_account_profile",
 		"account_name":       account.AccountName,
 		"cloud_type":         strconv.Itoa(account.CloudType),
@@ -140,7 +140,7 @@ func (c *Client) CreateOCIAccount(account *Account) error {
 
 func (c *Client) CreateAWSTSAccount(account *Account) error {
 	params := map[string]string{
-		"CID":                       c.CID,
+		"CID":                       c.cid(),
 		"action":                    "setup_account_profile",
 		"account_name":              account.AccountName,
 		"cloud_type":                strconv.Itoa(account.CloudType),
@@ -171,7 +171,7 @@ func (c *Client) CreateAWSTSAccount(account *Account) error {
 
 func (c *Client) CreateAWSSAccount(account *Account) error {
 	params := map[string]string{
-		"CID":                      c.CID,
+		"CID":                      c.cid(),
 		"action":                   "setup_account_profile",
 		"account_name":             account.AccountName,
 		"cloud_type":               strconv.Itoa(account.CloudType),
@@ -202,7 +202,7 @@ func (c *Client) CreateAWSSAccount(account *Account) error {
 
 func (c *Client) GetAccount(account *Account) (*Account, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_accounts",
 	}
 
@@ -223,14 +223,14 @@ func (c *Client) GetAccount(account *Account) (*Account, error) {
 }
 
 func (c *Client) UpdateAccount(account *Account) error {
-	account.CID = c.CID
+	account.CID = c.cid()
 	account.Action = "edit_account_profile"
 	return c.PostAPI(account.Action, account, BasicCheck)
 }
 
 func (c *Client) UpdateGCPAccount(account *Account) error {
 	params := map[string]string{
-		"CID":                 c.CID,
+		"CID":                 c.cid(),
 		"action":              "edit_account_profile",
 		"account_name":        account.AccountName,
 		"cloud_type":          strconv.Itoa(account.CloudType),
@@ -249,7 +249,7 @@ func (c *Client) UpdateGCPAccount(account *Account) error {
 
 func (c *Client) UpdateAWSTSAccount(account *Account, fileChanges map[string]bool) error {
 	params := map[string]string{
-		"CID":                       c.CID,
+		"CID":                       c.cid(),
 		"action":                    "edit_account_profile",
 		"account_name":              account.AccountName,
 		"cloud_type":                strconv.Itoa(account.CloudType),
@@ -293,7 +293,7 @@ func (c *Client) UpdateAWSTSAccount(account *Account, fileChanges map[string]boo
 
 func (c *Client) UpdateAWSSAccount(account *Account, fileChanges map[string]bool) error {
 	params := map[string]string{
-		"CID":                      c.CID,
+		"CID":                      c.cid(),
 		"action":                   "edit_account_profile",
 		"account_name":             account.AccountName,
 		"cloud_type":               strconv.Itoa(account.CloudType),
@@ -336,20 +336,20 @@ func (c *Client) UpdateAWSSAccount(account *Account, fileChanges map[string]bool
 }
 
 func (c *Client) DeleteAccount(account *Account) error {
-	account.CID = c.CID
+	account.CID = c.cid()
 	account.Action = "delete_account_profile"
 	return c.PostAPI(account.Action, account, BasicCheck)
 }
 
 func (c *Client) UploadOciApiPrivateKeyFile(account *Account) error {
-	account.CID = c.CID
+	account.CID = c.cid()
 	account.Action = "upload_file"
 	return c.PostAPI(account.Action, account, BasicCheck)
 }
 
 func (c *Client) AuditAccount(ctx context.Context, account *Account) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "get_account_audit_records",
 	}
 
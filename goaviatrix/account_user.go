@@ -31,14 +31,14 @@ type AccountUserListResp struct {
 }
 
 func (c *Client) CreateAccountUser(user *AccountUser) error {
-	user.CID = c.CID
+	user.CID = c.cid()
 	user.Action = "add_account_user"
 	return c.PostAPI(user.Action, user, BasicCheck)
 }
 
 func (c *Client) GetAccountUser(user *AccountUser) (*AccountUser, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_account_users",
 	}
 	var data AccountUserListResp
@@ -58,13 +58,13 @@ func (c *Client) GetAccountUser(user *AccountUser) (*AccountUser, error) {
 }
 
 func (c *Client) UpdateAccountUserObject(user *AccountUserEdit) error {
-	user.CID = c.CID
+	user.CID = c.cid()
 	user.Action = "edit_account_user"
 	return c.PostAPI(user.Action, user, BasicCheck)
 }
 
 func (c *Client) DeleteAccountUser(user *AccountUser) error {
-	user.CID = c.CID
+	user.CID = c.cid()
 	user.Action = "delete_account_user"
 	return c.PostAPI(user.Action, user, BasicCheck)
 }
@@ -31,7 +31,7 @@ type ArmPeerAPIResp struct {
 }
 
 func (c *Client) CreateARMPeer(armPeer *ARMPeer) error {
-	armPeer.CID = c.CID
+	armPeer.CID = c.cid()
 	armPeer.Action = "arm_peer_vnet_pair"
 	resp, err := c.Post(c.baseURL, armPeer)
 	if err != nil {
@@ -58,7 +58,7 @@ func (c *Client) GetARMPeer(armPeer *ARMPeer) (*ARMPeer, error) {
 		return nil, errors.New(("url Parsing failed for list_arm_peer_vnet_pairs ") + err.Error())
 	}
 	listArmPeering := url.Values{}
-	listArmPeering.Add("CID", c.CID)
+	listArmPeering.Add("CID", c.cid())
 	listArmPeering.Add("action", "list_arm_peer_vnet_pairs")
 	Url.RawQuery = listArmPeering.Encode()
 	resp, err := c.Get(Url.String(), nil)
@@ -123,7 +123,7 @@ func (c *Client) DeleteARMPeer(armPeer *ARMPeer) error {
 		return errors.New(("url Parsing failed for arm_unpeer_vnet_pair") + err.Error())
 	}
 	armUnpeerVNetPair := url.Values{}
-	armUnpeerVNetPair.Add("CID", c.CID)
+	armUnpeerVNetPair.Add("CID", c.cid())
 	armUnpeerVNetPair.Add("action", "arm_unpeer_vnet_pair")
 	armUnpeerVNetPair.Add("vpc_name1", armPeer.VNet1)
 	armUnpeerVNetPair.Add("vpc_name2", armPeer.VNet2)
@@ -24,7 +24,7 @@ func (acc *AwsGuardDutyAccount) ID() string {
 func (c *Client) UpdateAwsGuardDutyPollInterval(scanningInterval int) error {
 	data := map[string]string{
 		"action":   "update_aws_guard_duty_poll_interval",
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"interval": strconv.Itoa(scanningInterval),
 	}
 	checkFunc := func(action, method, reason string, ret bool) error {
@@ -40,7 +40,7 @@ func (c *Client) UpdateAwsGuardDutyPollInterval(scanningInterval int) error {
 func (c *Client) EnableAwsGuardDuty(account *AwsGuardDutyAccount) error {
 	data := map[string]string{
 		"action":       "enable_aws_guard_duty",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"account_name": account.AccountName,
 		"region":       account.Region,
 	}
@@ -56,7 +56,7 @@ func (c *Client) EnableAwsGuardDuty(account *AwsGuardDutyAccount) error {
 func (c *Client) DisableAwsGuardDuty(account *AwsGuardDutyAccount) error {
 	data := map[string]string{
 		"action":       "disable_aws_guard_duty",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"account_name": account.AccountName,
 		"region":       account.Region,
 	}
@@ -66,7 +66,7 @@ func (c *Client) DisableAwsGuardDuty(account *AwsGuardDutyAccount) error {
 func (c *Client) UpdateAwsGuardDutyExcludedIPs(account *AwsGuardDutyAccount) error {
 	data := map[string]string{
 		"action":       "update_aws_guard_duty_excluded_ips",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"account_name": account.AccountName,
 		"region":       account.Region,
 		"excluded_ips": strings.Join(account.ExcludedIPs, ","),
@@ -97,7 +97,7 @@ type ListAwsGuardDutyIntervalInfo struct {
 func (c *Client) GetAwsGuardDuty() (*AwsGuardDuty, error) {
 	formData := map[string]string{
 		"action": "list_aws_guard_duty",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	var data ListAwsGuardDutyResp
 	err := c.GetAPI(&data, formData["action"], formData, BasicCheck)
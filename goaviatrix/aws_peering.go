@@ -32,7 +32,7 @@ type AwsPeerAPIResp struct {
 }
 
 func (c *Client) CreateAWSPeer(awsPeer *AWSPeer) (string, error) {
-	awsPeer.CID = c.CID
+	awsPeer.CID = c.cid()
 	awsPeer.Action = "create_aws_peering"
 	resp, err := c.Post(c.baseURL, awsPeer)
 	if err != nil {
@@ -60,7 +60,7 @@ func (c *Client) GetAWSPeer(awsPeer *AWSPeer) (*AWSPeer, error) {
 		return nil, errors.New(("url Parsing failed for list_aws_peerings ") + err.Error())
 	}
 	listAwsPeering := url.Values{}
-	listAwsPeering.Add("CID", c.CID)
+	listAwsPeering.Add("CID", c.cid())
 	listAwsPeering.Add("action", "list_aws_peerings")
 	Url.RawQuery = listAwsPeering.Encode()
 	resp, err := c.Get(Url.String(), nil)
@@ -111,7 +111,7 @@ func (c *Client) UpdateAWSPeer(awsPeer *AWSPeer) error {
 }
 
 func (c *Client) DeleteAWSPeer(awsPeer *AWSPeer) error {
-	awsPeer.CID = c.CID
+	awsPeer.CID = c.cid()
 	awsPeer.Action = "delete_aws_peering"
 	resp, err := c.Post(c.baseURL, awsPeer)
 	if err != nil {
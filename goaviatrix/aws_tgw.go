@@ -151,14 +151,14 @@ type AttachmentDetail struct {
 }
 
 func (c *Client) CreateAWSTgw(awsTgw *AWSTgw) error {
-	awsTgw.CID = c.CID
+	awsTgw.CID = c.cid()
 	awsTgw.Action = "add_aws_tgw"
 	return c.PostAPI(awsTgw.Action, awsTgw, BasicCheck)
 }
 
 func (c *Client) GetAWSTgw(awsTgw *AWSTgw) (*AWSTgw, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_route_domain_names",
 		"tgw_name": awsTgw.Name,
 	}
@@ -178,7 +178,7 @@ func (c *Client) GetAWSTgw(awsTgw *AWSTgw) (*AWSTgw, error) {
 		}
 
 		form = map[string]string{
-			"CID":               c.CID,
+			"CID":               c.cid(),
 			"action":            "view_route_domain_details",
 			"tgw_name":          awsTgw.Name,
 			"route_domain_name": dm,
@@ -214,7 +214,7 @@ func (c *Client) GetAWSTgw(awsTgw *AWSTgw) (*AWSTgw, error) {
 
 			if dm != "Aviatrix_Edge_Domain" {
 				form = map[string]string{
-					"CID":             c.CID,
+					"CID":             c.cid(),
 					"action":          "list_attachment_route_table_details",
 					"tgw_name":        awsTgw.Name,
 					"attachment_name": attachedVPCs[i].VPCId,
@@ -276,7 +276,7 @@ func (c *Client) GetAWSTgw(awsTgw *AWSTgw) (*AWSTgw, error) {
 
 func (c *Client) IsFirewallSecurityDomain(tgwName string, domainName string) (bool, error) {
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "view_route_domain_details",
 		"tgw_name":          tgwName,
 		"route_domain_name": domainName,
@@ -297,7 +297,7 @@ func (c *Client) IsFirewallSecurityDomain(tgwName string, domainName string) (bo
 }
 
 func (c *Client) DeleteAWSTgw(awsTgw *AWSTgw) error {
-	awsTgw.CID = c.CID
+	awsTgw.CID = c.cid()
 	awsTgw.Action = "delete_aws_tgw"
 	return c.PostAPI(awsTgw.Action, awsTgw, BasicCheck)
 }
@@ -419,7 +419,7 @@ func (c *Client) AttachAviatrixTransitGWToAWSTgw(awsTgw *AWSTgw, gateway *Gatewa
 		return fmt.Errorf("could not get transit gateway to attach to AWS TGW: %v", err)
 	}
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "attach_vpc_to_tgw",
 		"region":            awsTgw.Region,
 		"vpc_account_name":  transitGw.AccountName,
@@ -438,7 +438,7 @@ func (c *Client) DetachAviatrixTransitGWFromAWSTgw(awsTgw *AWSTgw, gateway *Gate
 		return fmt.Errorf("could not get transit gateway to detach from AWS TGW: %v", err)
 	}
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "detach_vpc_from_tgw",
 		"tgw_name": awsTgw.Name,
 		"vpc_name": transitGw.VpcID,
@@ -457,7 +457,7 @@ func (c *Client) DetachAviatrixTransitGWFromAWSTgw(awsTgw *AWSTgw, gateway *Gate
 
 func (c *Client) AttachVpcToAWSTgw(awsTgw *AWSTgw, vpcSolo VPCSolo, SecurityDomainName string) error {
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "attach_vpc_to_tgw",
 		"region":            awsTgw.Region,
 		"vpc_account_name":  vpcSolo.AccountName,
@@ -485,7 +485,7 @@ func (c *Client) AttachVpcToAWSTgw(awsTgw *AWSTgw, vpcSolo VPCSolo, SecurityDoma
 
 func (c *Client) DetachVpcFromAWSTgw(awsTgw *AWSTgw, vpcID string) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "detach_vpc_from_tgw",
 		"tgw_name": awsTgw.Name,
 		"vpc_name": vpcID,
@@ -496,7 +496,7 @@ func (c *Client) DetachVpcFromAWSTgw(awsTgw *AWSTgw, vpcID string) error {
 func (c *Client) GetTransitGwFromVpcID(awsTgw *AWSTgw, gateway *Gateway) (*Gateway, error) {
 	var data ListAwsTgwAttachmentAPIResp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_all_tgw_attachments",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -517,7 +517,7 @@ func (c *Client) GetTransitGwFromVpcID(awsTgw *AWSTgw, gateway *Gateway) (*Gatew
 func (c *Client) ListTgwDetails(awsTgw *AWSTgw) (*AWSTgw, error) {
 	var data TGWInfoResp
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_tgw_details",
 		"tgw_name": awsTgw.Name,
 	}
@@ -553,7 +553,7 @@ func (c *Client) ListTgwDetails(awsTgw *AWSTgw) (*AWSTgw, error) {
 func (c *Client) IsVpcAttachedToTgw(awsTgw *AWSTgw, vpcSolo *VPCSolo) (bool, error) {
 	var data listAttachedVpcNamesResp
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_attached_vpc_names_to_route_domain",
 		"tgw_name": awsTgw.Name,
 	}
@@ -574,7 +574,7 @@ func (c *Client) IsVpcAttachedToTgw(awsTgw *AWSTgw, vpcSolo *VPCSolo) (bool, err
 func (c *Client) GetAttachmentRouteTableDetails(tgwName string, attachmentName string) (*AttachmentRouteTableDetails, error) {
 	var data AttachmentRouteTableDetailsAPIResp
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "list_attachment_route_table_details",
 		"tgw_name":        tgwName,
 		"attachment_name": attachmentName,
@@ -589,7 +589,7 @@ func (c *Client) GetAttachmentRouteTableDetails(tgwName string, attachmentName s
 func (c *Client) UpdateTGWCidrs(tgwName string, cidrs []string) error {
 	data := map[string]string{
 		"action":    "update_tgw_cidrs",
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"tgw_name":  tgwName,
 		"cidr_list": strings.Join(cidrs, ","),
 	}
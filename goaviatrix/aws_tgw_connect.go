@@ -43,20 +43,20 @@ func (a *AwsTgwConnectPeer) ID() string {
 
 func (c *Client) AttachTGWConnectToTGW(ctx context.Context, connect *AwsTgwConnect) error {
 	connect.Action = "attach_tgw_connect_to_tgw"
-	connect.CID = c.CID
+	connect.CID = c.cid()
 	return c.PostAPIContext(ctx, connect.Action, connect, BasicCheck)
 }
 
 func (c *Client) DetachTGWConnectFromTGW(ctx context.Context, connect *AwsTgwConnect) error {
 	connect.Action = "detach_tgw_connect_from_tgw"
-	connect.CID = c.CID
+	connect.CID = c.cid()
 	return c.PostAPIContext(ctx, connect.Action, connect, BasicCheck)
 }
 
 func (c *Client) GetTGWConnect(ctx context.Context, connect *AwsTgwConnect) (*AwsTgwConnect, error) {
 	form := map[string]string{
 		"action":          "get_tgw_connect_by_connection_name",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"connection_name": connect.ConnectionName,
 		"tgw_name":        connect.TgwName,
 	}
@@ -84,21 +84,21 @@ func (c *Client) GetTGWConnect(ctx context.Context, connect *AwsTgwConnect) (*Aw
 
 func (c *Client) CreateTGWConnectPeer(ctx context.Context, peer *AwsTgwConnectPeer) error {
 	peer.Action = "create_tgw_connect_peer"
-	peer.CID = c.CID
+	peer.CID = c.cid()
 	peer.InsideIPCidrsString = strings.Join(peer.InsideIPCidrs, ",")
 	return c.PostAPIContext(ctx, peer.Action, peer, BasicCheck)
 }
 
 func (c *Client) DeleteTGWConnectPeer(ctx context.Context, peer *AwsTgwConnectPeer) error {
 	peer.Action = "delete_tgw_connect_peer"
-	peer.CID = c.CID
+	peer.CID = c.cid()
 	return c.PostAPIContext(ctx, peer.Action, peer, BasicCheck)
 }
 
 func (c *Client) GetTGWConnectPeer(ctx context.Context, peer *AwsTgwConnectPeer) (*AwsTgwConnectPeer, error) {
 	form := map[string]string{
 		"action":            "get_tgw_connect_peer_by_connect_peer_name",
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"connection_name":   peer.ConnectionName,
 		"tgw_name":          peer.TgwName,
 		"connect_peer_name": peer.ConnectPeerName,
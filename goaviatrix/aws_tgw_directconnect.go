@@ -36,7 +36,7 @@ type AwsTgwDirectConnResp struct {
 }
 
 func (c *Client) CreateAwsTgwDirectConnect(awsTgwDirectConnect *AwsTgwDirectConnect) error {
-	awsTgwDirectConnect.CID = c.CID
+	awsTgwDirectConnect.CID = c.cid()
 	awsTgwDirectConnect.Action = "attach_direct_connect_to_tgw"
 	return c.PostAPI(awsTgwDirectConnect.Action, awsTgwDirectConnect, BasicCheck)
 }
@@ -44,7 +44,7 @@ func (c *Client) CreateAwsTgwDirectConnect(awsTgwDirectConnect *AwsTgwDirectConn
 func (c *Client) GetAwsTgwDirectConnect(awsTgwDirectConnect *AwsTgwDirectConnect) (*AwsTgwDirectConnect, error) {
 	var data AwsTgwDirectConnResp
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_all_tgw_attachments",
 		"tgw_name": awsTgwDirectConnect.TgwName,
 	}
@@ -67,20 +67,20 @@ func (c *Client) GetAwsTgwDirectConnect(awsTgwDirectConnect *AwsTgwDirectConnect
 }
 
 func (c *Client) UpdateDirectConnAllowedPrefix(awsTgwDirectConnect *AwsTgwDirectConnect) error {
-	awsTgwDirectConnect.CID = c.CID
+	awsTgwDirectConnect.CID = c.cid()
 	awsTgwDirectConnect.Action = "update_tgw_directconnect_allowed_prefix"
 	return c.PostAPI(awsTgwDirectConnect.Action, awsTgwDirectConnect, BasicCheck)
 }
 
 func (c *Client) DeleteAwsTgwDirectConnect(awsTgwDirectConnect *AwsTgwDirectConnect) error {
-	awsTgwDirectConnect.CID = c.CID
+	awsTgwDirectConnect.CID = c.cid()
 	awsTgwDirectConnect.Action = "detach_directconnect_from_tgw"
 	return c.PostAPI(awsTgwDirectConnect.Action, awsTgwDirectConnect, BasicCheck)
 }
 
 func (c *Client) EnableDirectConnectLearnedCidrsApproval(awsTgwDirectConnect *AwsTgwDirectConnect) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "enable_learned_cidrs_approval",
 		"tgw_name":               awsTgwDirectConnect.TgwName,
 		"attachment_name":        awsTgwDirectConnect.DxGatewayName,
@@ -91,7 +91,7 @@ func (c *Client) EnableDirectConnectLearnedCidrsApproval(awsTgwDirectConnect *Aw
 
 func (c *Client) DisableDirectConnectLearnedCidrsApproval(awsTgwDirectConnect *AwsTgwDirectConnect) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "disable_learned_cidrs_approval",
 		"tgw_name":               awsTgwDirectConnect.TgwName,
 		"attachment_name":        awsTgwDirectConnect.DxGatewayName,
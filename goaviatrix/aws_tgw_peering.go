@@ -21,7 +21,7 @@ type AwsTgwPeeringAPIResp struct {
 }
 
 func (c *Client) CreateAwsTgwPeering(awsTgwPeering *AwsTgwPeering) error {
-	awsTgwPeering.CID = c.CID
+	awsTgwPeering.CID = c.cid()
 	awsTgwPeering.Action = "add_tgw_peering"
 	return c.PostAPI(awsTgwPeering.Action, awsTgwPeering, BasicCheck)
 }
@@ -29,7 +29,7 @@ func (c *Client) CreateAwsTgwPeering(awsTgwPeering *AwsTgwPeering) error {
 func (c *Client) GetAwsTgwPeering(awsTgwPeering *AwsTgwPeering) error {
 	var data AwsTgwPeeringAPIResp
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_peered_tgw_names",
 		"tgw_name": awsTgwPeering.TgwName1,
 	}
@@ -60,7 +60,7 @@ func (c *Client) GetAwsTgwPeering(awsTgwPeering *AwsTgwPeering) error {
 }
 
 func (c *Client) DeleteAwsTgwPeering(awsTgwPeering *AwsTgwPeering) error {
-	awsTgwPeering.CID = c.CID
+	awsTgwPeering.CID = c.cid()
 	awsTgwPeering.Action = "delete_tgw_peering"
 	return c.PostAPI(awsTgwPeering.Action, awsTgwPeering, BasicCheck)
 }
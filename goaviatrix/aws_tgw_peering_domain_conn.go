@@ -28,7 +28,7 @@ type ConnectedRouteDomainDetail struct {
 
 func (c *Client) CreateDomainConn(domainConn *DomainConn) error {
 	form := map[string]string{
-		"CID":                           c.CID,
+		"CID":                           c.cid(),
 		"action":                        "add_connection_between_route_domains",
 		"tgw_name":                      domainConn.TgwName1,
 		"source_route_domain_name":      domainConn.DomainName1,
@@ -40,7 +40,7 @@ func (c *Client) CreateDomainConn(domainConn *DomainConn) error {
 func (c *Client) GetDomainConn(domainConn *DomainConn) error {
 	var data ListConnectedRouteDomainsResp
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "list_connected_route_domains",
 		"tgw_name":          domainConn.TgwName1,
 		"route_domain_name": domainConn.DomainName1,
@@ -69,7 +69,7 @@ func (c *Client) GetDomainConn(domainConn *DomainConn) error {
 
 func (c *Client) DeleteDomainConn(domainConn *DomainConn) error {
 	form := map[string]string{
-		"CID":                           c.CID,
+		"CID":                           c.cid(),
 		"action":                        "delete_connection_between_route_domains",
 		"tgw_name":                      domainConn.TgwName1,
 		"source_route_domain_name":      domainConn.DomainName1,
@@ -41,7 +41,7 @@ type AttachmentInfo struct {
 
 func (c *Client) CreateAwsTgwTransitGwAttachment(awsTgwTransitGwAttachment *AwsTgwTransitGwAttachment) error {
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "attach_vpc_to_tgw",
 		"region":            awsTgwTransitGwAttachment.Region,
 		"vpc_account_name":  awsTgwTransitGwAttachment.VpcAccountName,
@@ -56,7 +56,7 @@ func (c *Client) CreateAwsTgwTransitGwAttachment(awsTgwTransitGwAttachment *AwsT
 func (c *Client) GetAwsTgwTransitGwAttachment(awsTgwTransitGwAttachment *AwsTgwTransitGwAttachment) (*AwsTgwTransitGwAttachment, error) {
 	var data TgwAttachmentResp
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_tgw_details",
 		"tgw_name": awsTgwTransitGwAttachment.TgwName,
 	}
@@ -87,7 +87,7 @@ func (c *Client) GetAwsTgwTransitGwAttachment(awsTgwTransitGwAttachment *AwsTgwT
 
 func (c *Client) DeleteAwsTgwTransitGwAttachment(awsTgwTransitGwAttachment *AwsTgwTransitGwAttachment) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "detach_vpc_from_tgw",
 		"tgw_name": awsTgwTransitGwAttachment.TgwName,
 		"vpc_name": awsTgwTransitGwAttachment.VpcID,
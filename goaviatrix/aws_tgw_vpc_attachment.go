@@ -30,7 +30,7 @@ type DomainListResp struct {
 
 func (c *Client) CreateAwsTgwVpcAttachment(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "attach_vpc_to_tgw",
 		"region":            awsTgwVpcAttachment.Region,
 		"vpc_account_name":  awsTgwVpcAttachment.VpcAccountName,
@@ -58,7 +58,7 @@ func (c *Client) CreateAwsTgwVpcAttachment(awsTgwVpcAttachment *AwsTgwVpcAttachm
 
 func (c *Client) CreateAwsTgwVpcAttachmentForFireNet(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "connect_firenet_with_tgw",
 		"vpc_id":      awsTgwVpcAttachment.VpcID,
 		"tgw_name":    awsTgwVpcAttachment.TgwName,
@@ -148,7 +148,7 @@ func (c *Client) GetAwsTgwVpcAttachment(awsTgwVpcAttachment *AwsTgwVpcAttachment
 
 func (c *Client) DeleteAwsTgwVpcAttachment(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "detach_vpc_from_tgw",
 		"tgw_name": awsTgwVpcAttachment.TgwName,
 		"vpc_name": awsTgwVpcAttachment.VpcID,
@@ -158,7 +158,7 @@ func (c *Client) DeleteAwsTgwVpcAttachment(awsTgwVpcAttachment *AwsTgwVpcAttachm
 
 func (c *Client) DeleteAwsTgwVpcAttachmentForFireNet(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "disconnect_firenet_with_tgw",
 		"vpc_id": awsTgwVpcAttachment.VpcID,
 	}
@@ -177,7 +177,7 @@ func (c *Client) GetAwsTgwDetail(awsTgw *AWSTgw) (*AWSTgw, error) {
 func (c *Client) GetAwsTgwDomain(awsTgw *AWSTgw, sDM string) error {
 	var data DomainListResp
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_route_domain_names",
 		"tgw_name": awsTgw.Name,
 	}
@@ -199,7 +199,7 @@ func (c *Client) GetAwsTgwDomain(awsTgw *AWSTgw, sDM string) error {
 func (c *Client) GetVPCAttachmentRouteTableDetails(awsTgwVpcAttachment *AwsTgwVpcAttachment) (*AwsTgwVpcAttachment, error) {
 	var data RouteDomainAPIResp
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "view_route_domain_details",
 		"tgw_name":          awsTgwVpcAttachment.TgwName,
 		"route_domain_name": awsTgwVpcAttachment.SecurityDomainName,
@@ -221,7 +221,7 @@ func (c *Client) GetVPCAttachmentRouteTableDetails(awsTgwVpcAttachment *AwsTgwVp
 
 func (c *Client) EditTgwSpokeVpcCustomizedRoutes(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "edit_tgw_spoke_vpc_customized_routes",
 		"tgw_name":   awsTgwVpcAttachment.TgwName,
 		"vpc_id":     awsTgwVpcAttachment.VpcID,
@@ -232,7 +232,7 @@ func (c *Client) EditTgwSpokeVpcCustomizedRoutes(awsTgwVpcAttachment *AwsTgwVpcA
 
 func (c *Client) EditTgwSpokeVpcCustomizedRouteAdvertisement(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "update_customized_route_advertisement",
 		"tgw_name":        awsTgwVpcAttachment.TgwName,
 		"attachment_name": awsTgwVpcAttachment.VpcID,
@@ -244,7 +244,7 @@ func (c *Client) EditTgwSpokeVpcCustomizedRouteAdvertisement(awsTgwVpcAttachment
 func (c *Client) UpdateFirewallAttachmentAccessFromOnprem(awsTgwVpcAttachment *AwsTgwVpcAttachment) error {
 	params := map[string]string{
 		"action":          "update_firewall_attachment_access_from_onprem",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"tgw_name":        awsTgwVpcAttachment.TgwName,
 		"attachment_name": awsTgwVpcAttachment.VpcID,
 		"edge_attachment": awsTgwVpcAttachment.EdgeAttachment,
@@ -260,7 +260,7 @@ func (c *Client) UpdateFirewallAttachmentAccessFromOnprem(awsTgwVpcAttachment *A
 func (c *Client) GetFirenetManagementDetails(awsTgwVpcAttachment *AwsTgwVpcAttachment) ([]string, error) {
 	params := map[string]string{
 		"action":          "get_tgw_attachment_details",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"tgw_name":        awsTgwVpcAttachment.TgwName,
 		"attachment_name": awsTgwVpcAttachment.VpcID,
 	}
@@ -83,7 +83,7 @@ type GetAwsTgwVpnConnVpnIdResp struct {
 func (c *Client) CreateAwsTgwVpnConn(awsTgwVpnConn *AwsTgwVpnConn) (string, error) {
 	var data AwsTgwVpnConnCreateResp
 	form := map[string]string{
-		"CID":                        c.CID,
+		"CID":                        c.cid(),
 		"action":                     "attach_edge_vpn_to_tgw",
 		"tgw_name":                   awsTgwVpnConn.TgwName,
 		"route_domain_name":          awsTgwVpnConn.RouteDomainName,
@@ -119,7 +119,7 @@ func (c *Client) CreateAwsTgwVpnConn(awsTgwVpnConn *AwsTgwVpnConn) (string, erro
 func (c *Client) GetAwsTgwVpnConn(awsTgwVpnConn *AwsTgwVpnConn) (*AwsTgwVpnConn, error) {
 	var data AwsTgwVpnConnResp
 	form := map[string]string{
-		"CID":           c.CID,
+		"CID":           c.cid(),
 		"action":        "list_all_tgw_attachments",
 		"tgw_name":      awsTgwVpnConn.TgwName,
 		"resource_type": "vpn",
@@ -181,14 +181,14 @@ func (c *Client) GetAwsTgwVpnConn(awsTgwVpnConn *AwsTgwVpnConn) (*AwsTgwVpnConn,
 }
 
 func (c *Client) DeleteAwsTgwVpnConn(awsTgwVpnConn *AwsTgwVpnConn) error {
-	awsTgwVpnConn.CID = c.CID
+	awsTgwVpnConn.CID = c.cid()
 	awsTgwVpnConn.Action = "detach_vpn_from_tgw"
 	return c.PostAPI(awsTgwVpnConn.Action, awsTgwVpnConn, BasicCheck)
 }
 
 func (c *Client) EnableVpnConnectionLearnedCidrsApproval(awsTgwVpnConn *AwsTgwVpnConn) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "enable_learned_cidrs_approval",
 		"tgw_name":               awsTgwVpnConn.TgwName,
 		"attachment_name":        awsTgwVpnConn.VpnID,
@@ -199,7 +199,7 @@ func (c *Client) EnableVpnConnectionLearnedCidrsApproval(awsTgwVpnConn *AwsTgwVp
 
 func (c *Client) DisableVpnConnectionLearnedCidrsApproval(awsTgwVpnConn *AwsTgwVpnConn) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "disable_learned_cidrs_approval",
 		"tgw_name":               awsTgwVpnConn.TgwName,
 		"attachment_name":        awsTgwVpnConn.VpnID,
@@ -211,7 +211,7 @@ func (c *Client) DisableVpnConnectionLearnedCidrsApproval(awsTgwVpnConn *AwsTgwV
 func (c *Client) GetAwsTgwVpnTunnelData(awsTgwVpnConn *AwsTgwVpnConn) (*AwsTgwVpnConnEdit, error) {
 	params := map[string]string{
 		"action":          "list_attachment_route_table_details",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"tgw_name":        awsTgwVpnConn.TgwName,
 		"attachment_name": awsTgwVpnConn.VpnID,
 	}
@@ -20,7 +20,7 @@ type AzurePeerAPIResp struct {
 }
 
 func (c *Client) CreateAzurePeer(azurePeer *AzurePeer) error {
-	azurePeer.CID = c.CID
+	azurePeer.CID = c.cid()
 	azurePeer.Action = "arm_peer_vnet_pair"
 	return c.PostAPI(azurePeer.Action, azurePeer, BasicCheck)
 }
@@ -28,7 +28,7 @@ func (c *Client) CreateAzurePeer(azurePeer *AzurePeer) error {
 func (c *Client) GetAzurePeer(azurePeer *AzurePeer) (*AzurePeer, error) {
 	var data map[string]interface{}
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_arm_peer_vnet_pairs",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -72,7 +72,7 @@ func (c *Client) GetAzurePeer(azurePeer *AzurePeer) (*AzurePeer, error) {
 
 func (c *Client) DeleteAzurePeer(azurePeer *AzurePeer) error {
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "arm_unpeer_vnet_pair",
 		"vpc_name1": azurePeer.VNet1,
 		"vpc_name2": azurePeer.VNet2,
@@ -27,7 +27,7 @@ type AzureSpokeNativePeeringEdit struct {
 }
 
 func (c *Client) CreateAzureSpokeNativePeering(azureSpokeNativePeering *AzureSpokeNativePeering) error {
-	azureSpokeNativePeering.CID = c.CID
+	azureSpokeNativePeering.CID = c.cid()
 	azureSpokeNativePeering.Action = "attach_arm_native_spoke_to_transit"
 	return c.PostAPI(azureSpokeNativePeering.Action, azureSpokeNativePeering, BasicCheck)
 }
@@ -35,7 +35,7 @@ func (c *Client) CreateAzureSpokeNativePeering(azureSpokeNativePeering *AzureSpo
 func (c *Client) GetAzureSpokeNativePeering(azureSpokeNativePeering *AzureSpokeNativePeering) (*AzureSpokeNativePeering, error) {
 	var data AzureSpokeNativePeeringAPIResp
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "list_arm_native_spokes",
 		"transit_gateway_name": azureSpokeNativePeering.TransitGatewayName,
 		"details":              "true",
@@ -66,7 +66,7 @@ func (c *Client) GetAzureSpokeNativePeering(azureSpokeNativePeering *AzureSpokeN
 
 func (c *Client) DeleteAzureSpokeNativePeering(azureSpokeNativePeering *AzureSpokeNativePeering) error {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "detach_arm_native_spoke_to_transit",
 		"transit_gateway_name": azureSpokeNativePeering.TransitGatewayName,
 		"spoke_name":           azureSpokeNativePeering.SpokeAccountName + ":" + strings.Replace(azureSpokeNativePeering.SpokeVpcID, ".", "-", -1),
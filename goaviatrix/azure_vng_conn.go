@@ -17,7 +17,7 @@ type AzureVngConnResp struct {
 func (c *Client) ConnectAzureVng(r *AzureVngConn) error {
 	params := map[string]string{
 		"action":               "attach_vng_to_transit_gateway",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"primary_gateway_name": r.PrimaryGatewayName,
 		"connection_name":      r.ConnectionName,
 	}
@@ -28,7 +28,7 @@ func (c *Client) ConnectAzureVng(r *AzureVngConn) error {
 func (c *Client) GetAzureVngConnStatus(connectionName string) (*AzureVngConnResp, error) {
 	params := map[string]string{
 		"action": "list_vnets_with_vng",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -56,7 +56,7 @@ func (c *Client) GetAzureVngConnStatus(connectionName string) (*AzureVngConnResp
 func (c *Client) DisconnectAzureVng(vpcId string, connectionName string) error {
 	params := map[string]string{
 		"action":          "disconnect_transit_gw",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"vpc_id":          vpcId,
 		"connection_name": connectionName,
 	}
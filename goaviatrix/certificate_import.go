@@ -9,7 +9,7 @@ type HTTPSCertConfig struct {
 func (c *Client) ImportNewHTTPSCerts(certConfig *HTTPSCertConfig) error {
 	data := map[string]string{
 		"action": "import_new_https_certs",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	files := []File{
 		{
@@ -31,7 +31,7 @@ func (c *Client) ImportNewHTTPSCerts(certConfig *HTTPSCertConfig) error {
 func (c *Client) DisableImportedHTTPSCerts() error {
 	data := map[string]string{
 		"action": "disable_imported_certificate",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
@@ -48,7 +48,7 @@ type GetHTTPSCertsStatus struct {
 func (c *Client) GetHTTPSCertsStatus() (bool, error) {
 	data := map[string]string{
 		"action": "get_https_certs_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	var respData GetHTTPSCertsStatusResp
 	err := c.GetAPI(&respData, data["action"], data, BasicCheck)
@@ -0,0 +1,68 @@
+package goaviatrix
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// CIDRefreshFunc returns a new CID after the controller rejected the current one as invalid or expired,
+// e.g. to get it from an external session broker instead of logging in with a username and password
+type CIDRefreshFunc func(ctx context.Context) (string, error)
+
+// isSessionExpired reports whether the controller rejected a request because its CID is invalid or expired
+func isSessionExpired(reason string) bool {
+	return strings.Contains(reason, "CID is invalid") || strings.Contains(reason, "Invalid session. Please login again.")
+}
+
+// refreshCID replaces staleCID, the CID the controller rejected, using c.CIDRefresh or logging in again
+// if it is not set. Requests rejected concurrently with the same CID share one refresh: once one of them
+// got a new CID, the others use it instead of logging in again, which would invalidate it.
+func (c *Client) refreshCID(ctx context.Context, staleCID string) error {
+	c.cidRefreshMu.Lock()
+	defer c.cidRefreshMu.Unlock()
+
+	if c.cid() != staleCID {
+		return nil
+	}
+	if c.CIDRefresh == nil {
+		return c.Login()
+	}
+	cid, err := c.CIDRefresh(ctx)
+	if err != nil {
+		return fmt.Errorf("could not refresh CID: %w", err)
+	}
+	c.setCID(cid)
+	return nil
+}
+
+// requestCID returns the CID sent in the form i of a request, or in the query of path if i is nil
+func requestCID(path string, i interface{}) string {
+	switch form := i.(type) {
+	case map[string]string:
+		return form["CID"]
+	case map[string]interface{}:
+		cid, _ := form["CID"].(string)
+		return cid
+	case nil:
+		u, err := url.Parse(path)
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("CID")
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("CID"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
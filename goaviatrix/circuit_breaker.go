@@ -0,0 +1,139 @@
+package goaviatrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrControllerUnavailable is returned without contacting the controller while the circuit breaker
+// of the client is open, after too many consecutive requests failed. Check for it with errors.Is.
+var ErrControllerUnavailable = errors.New("controller unavailable")
+
+const (
+	// DefaultCircuitBreakerWindow is the window of CircuitBreakerSettings when not set
+	DefaultCircuitBreakerWindow = time.Minute
+	// DefaultCircuitBreakerCooldown is the cooldown of CircuitBreakerSettings when not set
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// CircuitBreakerSettings configure when requests to a controller that keeps failing are short-circuited
+type CircuitBreakerSettings struct {
+	// Threshold is the number of consecutive failed requests within Window that opens the circuit
+	// breaker. 0 or less disables it.
+	Threshold int
+	// Window is the longest time between the first and the last of the consecutive failures
+	Window time.Duration
+	// Cooldown is how long requests fail with ErrControllerUnavailable once the breaker is open
+	Cooldown time.Duration
+}
+
+// circuitBreaker counts consecutive failed requests. A request fails if it gets no response, e.g. when
+// the connection is refused or times out, or an HTTP 5xx response. Any other response closes the breaker.
+type circuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu           sync.Mutex
+	failures     int
+	firstFailure time.Time
+	openUntil    time.Time
+}
+
+func newCircuitBreaker(settings CircuitBreakerSettings) *circuitBreaker {
+	if settings.Window <= 0 {
+		settings.Window = DefaultCircuitBreakerWindow
+	}
+	if settings.Cooldown <= 0 {
+		settings.Cooldown = DefaultCircuitBreakerCooldown
+	}
+	return &circuitBreaker{settings: settings}
+}
+
+// allow returns ErrControllerUnavailable while the breaker is open
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if time.Now().Before(b.openUntil) {
+		return fmt.Errorf("%w: the last %d requests to the controller failed, not sending requests until %s",
+			ErrControllerUnavailable, b.failures, b.openUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// record counts the outcome of a request. After the cooldown, the first request is sent to the
+// controller again: a success closes the breaker, a failure opens it again right away.
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+
+	now := time.Now()
+	if b.failures == 0 || (b.failures < b.settings.Threshold && now.Sub(b.firstFailure) > b.settings.Window) {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures >= b.settings.Threshold && !now.Before(b.openUntil) {
+		b.openUntil = now.Add(b.settings.Cooldown)
+		log.WithFields(log.Fields{
+			"failures": b.failures,
+			"cooldown": b.settings.Cooldown.String(),
+		}).Warn("Requests to the controller keep failing, failing further requests until the cooldown is over")
+	}
+}
+
+// circuitBreakerTransport short-circuits requests while the breaker is open
+type circuitBreakerTransport struct {
+	breaker *circuitBreaker
+	next    http.RoundTripper
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	// requests canceled by the caller say nothing about the controller, unlike requests timing out
+	if !errors.Is(err, context.Canceled) {
+		t.breaker.record(err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	}
+	return resp, err
+}
+
+// SetCircuitBreaker makes the requests of this client fail fast with ErrControllerUnavailable for
+// settings.Cooldown once settings.Threshold consecutive requests failed within settings.Window, so
+// an apply against a controller that is down fails quickly instead of retrying every request.
+// A threshold of 0 or less removes the circuit breaker.
+func (c *Client) SetCircuitBreaker(settings CircuitBreakerSettings) {
+	httpClient := &http.Client{}
+	if c.HTTPClient != nil {
+		// copy the client so the circuit breaker does not apply to other users of the same http.Client
+		*httpClient = *c.HTTPClient
+	}
+	next := httpClient.Transport
+	if t, ok := next.(*circuitBreakerTransport); ok {
+		next = t.next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if settings.Threshold <= 0 {
+		httpClient.Transport = next
+	} else {
+		httpClient.Transport = &circuitBreakerTransport{
+			breaker: newCircuitBreaker(settings),
+			next:    next,
+		}
+	}
+	c.HTTPClient = httpClient
+}
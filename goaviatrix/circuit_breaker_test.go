@@ -0,0 +1,104 @@
+package goaviatrix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetCircuitBreaker(t *testing.T) {
+	calls, down := 0, true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if down {
+			respondStatus(http.StatusServiceUnavailable)(w)
+			return
+		}
+		respondJSON(`{"return":true,"results":[]}`)(w)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.RetryBaseDelay = time.Millisecond
+	originalHTTPClient := client.HTTPClient
+	originalTransport := originalHTTPClient.Transport
+	client.SetCircuitBreaker(CircuitBreakerSettings{Threshold: 3, Window: time.Minute, Cooldown: 100 * time.Millisecond})
+	if originalHTTPClient.Transport != originalTransport {
+		t.Errorf("SetCircuitBreaker() modified the http.Client passed to the client")
+	}
+
+	// the retries of the request open the breaker after the third failure
+	_, err := client.ListDevices(context.Background())
+	if !errors.Is(err, ErrControllerUnavailable) {
+		t.Errorf("ListDevices() got error %v, want ErrControllerUnavailable", err)
+	}
+	if calls != 3 {
+		t.Errorf("server got %d calls, want 3", calls)
+	}
+
+	// the breaker is open, requests fail without reaching the controller and are not retried
+	start := time.Now()
+	err = client.PostAPIWithRetry("register_cloudwan_device", map[string]string{"action": "register_cloudwan_device"}, BasicCheck)
+	if !errors.Is(err, ErrControllerUnavailable) {
+		t.Errorf("PostAPIWithRetry() with open circuit breaker got error %v, want ErrControllerUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("request with open circuit breaker took %s, want it to fail fast", elapsed)
+	}
+	if calls != 3 {
+		t.Errorf("server got %d calls, want 3", calls)
+	}
+
+	// after the cooldown, a single failure opens the breaker again
+	time.Sleep(100 * time.Millisecond)
+	if _, err := client.ListDevices(context.Background()); !errors.Is(err, ErrControllerUnavailable) {
+		t.Errorf("ListDevices() after the cooldown got error %v, want ErrControllerUnavailable", err)
+	}
+	if calls != 4 {
+		t.Errorf("server got %d calls, want 4", calls)
+	}
+
+	// once the controller is back, a success closes the breaker
+	down = false
+	time.Sleep(100 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListDevices(context.Background()); err != nil {
+			t.Errorf("ListDevices() %d after the controller recovered unexpected error: %v", i, err)
+		}
+	}
+	if calls != 6 {
+		t.Errorf("server got %d calls, want 6", calls)
+	}
+
+	client.SetCircuitBreaker(CircuitBreakerSettings{})
+	if _, ok := client.HTTPClient.Transport.(*circuitBreakerTransport); ok {
+		t.Errorf("SetCircuitBreaker() with threshold 0 did not remove the circuit breaker")
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	breaker := newCircuitBreaker(CircuitBreakerSettings{Threshold: 2, Window: 20 * time.Millisecond})
+
+	// failures further apart than the window do not open the breaker
+	breaker.record(true)
+	time.Sleep(30 * time.Millisecond)
+	breaker.record(true)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after failures outside the window got error %v, want none", err)
+	}
+
+	// a success in between resets the count
+	breaker.record(false)
+	breaker.record(true)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after a success got error %v, want none", err)
+	}
+
+	breaker.record(true)
+	if err := breaker.allow(); !errors.Is(err, ErrControllerUnavailable) {
+		t.Errorf("allow() after consecutive failures got error %v, want ErrControllerUnavailable", err)
+	}
+}
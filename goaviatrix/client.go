@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
@@ -16,6 +17,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ajg/form"
@@ -42,14 +44,57 @@ type APIRequest struct {
 	Action string `form:"action,omitempty" json:"action" url:"action"`
 }
 
-// Client for accessing the Aviatrix Controller
+// Client for accessing the Aviatrix Controller. All session state and caches, e.g. the CID and the cached
+// devices and tags, are kept per Client, so clients of different controllers can be used side by side.
+// Package-level variables must not hold any state of a controller.
 type Client struct {
 	HTTPClient   *http.Client
 	Username     string
 	Password     string
-	CID          string
+	CID          string // use CurrentCID to read it while requests may refresh it
 	ControllerIP string
+	MaxRetries   int // maximum attempts for calls that retry transient failures, DefaultMaxRetries if not set
 	baseURL      string
+
+	// RetryBaseDelay is the wait before the first retry of a transient failure, 500ms if not set.
+	// Retries wait a random duration up to the base delay doubled after every failed try.
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay caps the wait between two tries, DefaultRetryMaxDelay if not set
+	RetryMaxDelay time.Duration
+
+	// DefaultDeviceHostOS is the host OS used for devices registered without one
+	DefaultDeviceHostOS string
+	// DisableTagCache makes every GetTagsMap call query the controller, e.g. for debugging
+	DisableTagCache bool
+	// IgnoreTags are the tags managed outside of the provider. They are left out of the tags read and
+	// are never added or deleted.
+	IgnoreTags IgnoreTagsConfig
+	// TagPrefix is prepended to the keys of the tags written, and only tags with the prefix are read,
+	// without it. It namespaces the tags managed by the provider, e.g. per team.
+	TagPrefix string
+	// DebugHTTP logs the bodies of the requests to the controller and of their responses, with
+	// passwords, CIDs and private keys masked
+	DebugHTTP bool
+	// CIDRefresh gets a new CID when the controller rejects the current one as invalid or expired.
+	// If not set, the client logs in again with its username and password.
+	CIDRefresh CIDRefreshFunc
+
+	deviceCacheMu sync.Mutex
+	deviceCache   []*Device
+
+	tagCacheMu sync.Mutex
+	tagCache   map[tagCacheKey]tagCacheEntry
+
+	controllerVersionMu sync.Mutex
+	controllerVersion   string
+
+	retryRandMu sync.Mutex
+	retryRand   *rand.Rand
+
+	// cidMu guards CID once the client is in use, see cid and setCID
+	cidMu sync.RWMutex
+	// cidRefreshMu serializes refreshing the CID
+	cidRefreshMu sync.Mutex
 }
 
 // Login to the Aviatrix controller with the username/password provided in
@@ -77,10 +122,28 @@ func (c *Client) Login() error {
 		return errors.New(data.Reason)
 	}
 	log.Tracef("CID is '%s'.", data.CID)
-	c.CID = data.CID
+	c.setCID(data.CID)
 	return nil
 }
 
+// CurrentCID returns the CID the client sends with its requests. Unlike reading the CID field it is
+// safe to call while other requests may refresh the CID.
+func (c *Client) CurrentCID() string {
+	return c.cid()
+}
+
+func (c *Client) cid() string {
+	c.cidMu.RLock()
+	defer c.cidMu.RUnlock()
+	return c.CID
+}
+
+func (c *Client) setCID(cid string) {
+	c.cidMu.Lock()
+	defer c.cidMu.Unlock()
+	c.CID = cid
+}
+
 // NewClient creates a Client object using the arguments provided.
 // Arguments:
 //   username - the controller username
@@ -113,11 +176,12 @@ func (c *Client) init(controllerIP string) (*Client, error) {
 
 	if c.HTTPClient == nil {
 		tr := &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
+			Proxy: ProxyFromEnvironment,
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: true,
 			},
 		}
+		ConfigureTransport(tr, TransportSettings{})
 		c.HTTPClient = &http.Client{Transport: tr}
 	}
 	if err := c.Login(); err != nil {
@@ -127,6 +191,21 @@ func (c *Client) init(controllerIP string) (*Client, error) {
 	return c, nil
 }
 
+// ProxyFromEnvironment returns the proxy set by the AVIATRIX_PROXY_URL environment variable to use
+// for the request. If it is not set, the standard HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment
+// variables are used, see http.ProxyFromEnvironment.
+func ProxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	proxyURL := os.Getenv("AVIATRIX_PROXY_URL")
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment(req)
+	}
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AVIATRIX_PROXY_URL %q: %v", proxyURL, err)
+	}
+	return proxy, nil
+}
+
 func (c *Client) Get(path string, i interface{}) (*http.Response, error) {
 	return c.Request("GET", path, i)
 }
@@ -150,9 +229,10 @@ func (c *Client) PostContext(ctx context.Context, path string, i interface{}) (*
 type CheckAPIResponseFunc func(action, method, reason string, ret bool) error
 
 // BasicCheck will only verify that the Return field was set the true
+// The error is an *APIError, which wraps ErrAuth, ErrValidation or ErrConflict for these categories of failures.
 var BasicCheck CheckAPIResponseFunc = func(action, method, reason string, ret bool) error {
 	if !ret {
-		return fmt.Errorf("rest API %s %s failed: %s", action, method, reason)
+		return newAPIError(action, method, reason)
 	}
 	return nil
 }
@@ -177,7 +257,7 @@ func (c *Client) PostFileAPI(params map[string]string, files []File, checkFunc C
 	if params["action"] == "" {
 		return fmt.Errorf("cannot PostFileAPI without an 'action' in params map")
 	}
-	resp, err := c.PostFile(c.baseURL, params, files)
+	resp, err := c.postFileRefreshingCID(context.Background(), params, files)
 	if err != nil {
 		return fmt.Errorf("HTTP POST %q failed: %v", params["action"], err)
 	}
@@ -190,13 +270,46 @@ func (c *Client) PostFileAPIContext(ctx context.Context, params map[string]strin
 	if params["action"] == "" {
 		return fmt.Errorf("cannot PostFileAPIContext without an 'action' in params map")
 	}
-	resp, err := c.PostFileContext(ctx, c.baseURL, params, files)
+	resp, err := c.postFileRefreshingCID(ctx, params, files)
 	if err != nil {
 		return fmt.Errorf("HTTP POST %q failed: %v", params["action"], err)
 	}
 	return decodeAndCheckAPIResp(resp, params["action"], checkFunc)
 }
 
+// postFileRefreshingCID posts the files and params like PostFileContext. If the controller rejects the
+// CID in params as invalid or expired, it refreshes the CID, updates params and posts them once more.
+// Sending the request again is safe for every action: the controller did not run the rejected one.
+func (c *Client) postFileRefreshingCID(ctx context.Context, params map[string]string, files []File) (*http.Response, error) {
+	resp, err := c.PostFileContext(ctx, c.baseURL, params, files)
+	if err != nil {
+		return resp, err
+	}
+
+	var b bytes.Buffer
+	_, err = b.ReadFrom(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return resp, err
+	}
+	// Replace resp.Body so that the caller can read the response again
+	resp.Body = io.NopCloser(&b)
+
+	var data APIResp
+	if json.Unmarshal(b.Bytes(), &data) != nil || !isSessionExpired(data.Reason) {
+		return resp, nil
+	}
+
+	log.WithFields(log.Fields{
+		"action": params["action"],
+	}).Warnf("HTTP request failed with expired CID")
+	if err := c.refreshCID(ctx, params["CID"]); err != nil {
+		return resp, err
+	}
+	params["CID"] = c.cid()
+	return c.PostFileContext(ctx, c.baseURL, params, files)
+}
+
 func decodeAndCheckAPIResp(resp *http.Response, action string, checkFunc CheckAPIResponseFunc) error {
 	var data APIResp
 	var b bytes.Buffer
@@ -209,7 +322,7 @@ func decodeAndCheckAPIResp(resp *http.Response, action string, checkFunc CheckAP
 		return fmt.Errorf("json Decode %q failed: %v\n Body: %s", action, err, b.String())
 	}
 
-	return checkFunc(action, "Post", data.Reason, data.Return)
+	return withRequestID(categorizeStatus(checkFunc(action, "Post", data.Reason, data.Return), resp), resp)
 }
 
 // GetAPI makes a GET request to the Aviatrix API
@@ -229,7 +342,7 @@ func (c *Client) GetAPIContext(ctx context.Context, v interface{}, action string
 		return fmt.Errorf("could not url encode values for action %q: %v", action, err)
 	}
 
-	try, maxTries, backoff := 0, 5, 500*time.Millisecond
+	try, maxTries := 0, 5
 	var resp *http.Response
 	for {
 		try++
@@ -244,12 +357,12 @@ func (c *Client) GetAPIContext(ctx context.Context, v interface{}, action string
 			"err":    err.Error(),
 		}).Warnf("HTTP GET request failed")
 
-		if try == maxTries {
-			return fmt.Errorf("HTTP Get %s failed: %v", action, err)
+		// retrying is pointless once the context is canceled or its deadline exceeded, or while the
+		// circuit breaker is open
+		if try == maxTries || ctx.Err() != nil || errors.Is(err, ErrControllerUnavailable) {
+			return fmt.Errorf("HTTP Get %s failed: %w", action, err)
 		}
-		time.Sleep(backoff)
-		// Double the backoff time after each failed try
-		backoff *= 2
+		time.Sleep(c.retryDelay(try))
 	}
 
 	buf := new(bytes.Buffer)
@@ -260,7 +373,7 @@ func (c *Client) GetAPIContext(ctx context.Context, v interface{}, action string
 		return fmt.Errorf("Json Decode into standard format failed: %v\n Body: %s", err, bodyString)
 	}
 	if err := checkFunc(action, "Get", data.Reason, data.Return); err != nil {
-		return err
+		return withRequestID(categorizeStatus(err, resp), resp)
 	}
 	if err := json.NewDecoder(strings.NewReader(bodyString)).Decode(&v); err != nil {
 		return fmt.Errorf("Json Decode failed: %v\n Body: %s", err, bodyString)
@@ -312,7 +425,7 @@ func (c *Client) PostFile(path string, params map[string]string, files []File) (
 	}
 	req.Header.Set("Content-Type", contentType)
 
-	return c.HTTPClient.Do(req)
+	return c.do(req, params["action"])
 }
 
 // PostFileContext will encode the files and parameters with multipart form encoding.
@@ -328,7 +441,7 @@ func (c *Client) PostFileContext(ctx context.Context, path string, params map[st
 	}
 	req.Header.Set("Content-Type", contentType)
 
-	return c.HTTPClient.Do(req)
+	return c.do(req, params["action"])
 }
 
 func encodeMultipartFormData(params map[string]string, files []File) (*bytes.Buffer, string, error) {
@@ -438,7 +551,7 @@ func (c *Client) RequestContext(ctx context.Context, verb string, path string, i
 			return nil, err
 		}
 
-		resp, err = c.HTTPClient.Do(req)
+		resp, err = c.do(req, requestAction(req, i))
 		if err != nil {
 			return resp, err
 		}
@@ -456,13 +569,13 @@ func (c *Client) RequestContext(ctx context.Context, verb string, path string, i
 			return resp, fmt.Errorf("Json Decode into standard format failed: %v\n Body: %s", err, bodyString)
 		}
 
-		// Any error not related to expired CID should return
-		if !(strings.Contains(data.Reason, "CID is invalid") || strings.Contains(data.Reason, "Invalid session. Please login again.")) {
+		// Any error not related to expired CID should return. Logging in can not be fixed by a new CID.
+		if !isSessionExpired(data.Reason) || requestAction(req, i) == "login" {
 			return resp, err
 		}
 
 		log.Tracef("CID invalid or expired. Trying to login again")
-		if err = c.Login(); err != nil {
+		if err = c.refreshCID(ctx, requestCID(path, i)); err != nil {
 			return resp, err
 		}
 
@@ -471,12 +584,12 @@ func (c *Client) RequestContext(ctx context.Context, verb string, path string, i
 			// Update CID in POST body
 			v := reflect.ValueOf(i)
 			if v.Kind() == reflect.Map {
-				v.SetMapIndex(reflect.ValueOf("CID"), reflect.ValueOf(c.CID))
+				v.SetMapIndex(reflect.ValueOf("CID"), reflect.ValueOf(c.cid()))
 			} else {
 				s := v.Elem()
 				f := s.FieldByName("CID")
 				if f.IsValid() && f.CanSet() {
-					f.SetString(c.CID)
+					f.SetString(c.cid())
 				}
 			}
 		} else {
@@ -486,7 +599,7 @@ func (c *Client) RequestContext(ctx context.Context, verb string, path string, i
 				return resp, fmt.Errorf("failed to parse url: %v", err)
 			}
 			query := Url.Query()
-			query["CID"] = []string{c.CID}
+			query["CID"] = []string{c.cid()}
 			Url.RawQuery = query.Encode()
 			path = Url.String()
 		}
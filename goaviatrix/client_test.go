@@ -0,0 +1,271 @@
+package goaviatrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// sessionTestServer fakes a controller that issues a CID per login and only accepts the latest one
+type sessionTestServer struct {
+	mu       sync.Mutex
+	username string
+	password string
+	logins   int
+	cid      string
+}
+
+func (s *sessionTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.FormValue("action") == "login" {
+		if r.FormValue("username") != s.username || r.FormValue("password") != s.password {
+			respondJSON(`{"return":false,"reason":"invalid username or password"}`)(w)
+			return
+		}
+		s.logins++
+		s.cid = fmt.Sprintf("%s-cid-%d", s.username, s.logins)
+		respondJSON(fmt.Sprintf(`{"return":true,"CID":%q}`, s.cid))(w)
+		return
+	}
+	if r.FormValue("CID") != s.cid {
+		respondJSON(`{"return":false,"reason":"CID is invalid or expired."}`)(w)
+		return
+	}
+	respondJSON(fmt.Sprintf(`{"return":true,"results":{"usr_tags":{"controller":%q}}}`, s.username))(w)
+}
+
+// expireSession invalidates the current CID, e.g. after a controller restart
+func (s *sessionTestServer) expireSession() {
+	s.mu.Lock()
+	s.cid = ""
+	s.mu.Unlock()
+}
+
+func TestClientsDoNotShareSessions(t *testing.T) {
+	newSessionClient := func(fake *sessionTestServer) *Client {
+		srv := httptest.NewServer(fake)
+		t.Cleanup(srv.Close)
+		client := &Client{
+			HTTPClient: srv.Client(),
+			Username:   fake.username,
+			Password:   fake.password,
+			baseURL:    srv.URL,
+		}
+		if err := client.Login(); err != nil {
+			t.Fatalf("Login() unexpected error: %v", err)
+		}
+		return client
+	}
+	fakeA := &sessionTestServer{username: "admin-a", password: "password-a"}
+	fakeB := &sessionTestServer{username: "admin-b", password: "password-b"}
+	clientA, clientB := newSessionClient(fakeA), newSessionClient(fakeB)
+	clientB.SetRateLimit(10)
+
+	getTags := func(client *Client) map[string]string {
+		tags, err := client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "gw-1"})
+		if err != nil {
+			t.Fatalf("GetTagsMap() unexpected error: %v", err)
+		}
+		return tags
+	}
+	// the same resource on both controllers must not share cached tags
+	if got, want := getTags(clientA), map[string]string{"controller": "admin-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTagsMap() of client A = %v, want %v", got, want)
+	}
+	if got, want := getTags(clientB), map[string]string{"controller": "admin-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTagsMap() of client B = %v, want %v", got, want)
+	}
+
+	// logging in again after the session of one controller expired must not affect the other client
+	cidB := clientB.CID
+	fakeA.expireSession()
+	clientA.DisableTagCache = true
+	getTags(clientA)
+	if clientA.CID != "admin-a-cid-2" {
+		t.Errorf("client A CID after login = %q, want %q", clientA.CID, "admin-a-cid-2")
+	}
+	if clientB.CID != cidB || fakeB.logins != 1 {
+		t.Errorf("client B CID = %q after %d logins, want %q after 1 login", clientB.CID, fakeB.logins, cidB)
+	}
+	if clientA.HTTPClient == clientB.HTTPClient {
+		t.Errorf("clients share an http.Client")
+	}
+}
+
+func TestRefreshExpiredCID(t *testing.T) {
+	fake := &sessionTestServer{username: "admin", password: "password"}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Username:   fake.username,
+		Password:   fake.password,
+		baseURL:    srv.URL,
+	}
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login() unexpected error: %v", err)
+	}
+
+	// requests rejected with an expired CID share a single login and are retried with the new CID
+	fake.expireSession()
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			form := map[string]string{"action": "list_resource_tags", "CID": client.CurrentCID()}
+			if i%2 == 0 {
+				errs[i] = client.PostAPIContext(context.Background(), form["action"], form, BasicCheck)
+				return
+			}
+			var data map[string]interface{}
+			errs[i] = client.GetAPIContext(context.Background(), &data, form["action"], form, BasicCheck)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("request %d with expired CID unexpected error: %v", i, err)
+		}
+	}
+	if fake.logins != 2 || client.CID != "admin-cid-2" {
+		t.Errorf("client CID = %q after %d logins, want %q after 2 logins", client.CID, fake.logins, "admin-cid-2")
+	}
+
+	// a custom refresh gets the new CID instead of logging in
+	client.CIDRefresh = func(ctx context.Context) (string, error) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.cid = "external-cid"
+		return fake.cid, nil
+	}
+	fake.expireSession()
+	form := map[string]string{"action": "list_resource_tags", "CID": client.CID}
+	if err := client.PostAPIContext(context.Background(), form["action"], form, BasicCheck); err != nil {
+		t.Errorf("PostAPIContext() with custom CID refresh unexpected error: %v", err)
+	}
+	if fake.logins != 2 || client.CID != "external-cid" {
+		t.Errorf("client CID = %q after %d logins, want %q after 2 logins", client.CID, fake.logins, "external-cid")
+	}
+
+	refreshErr := errors.New("session broker unavailable")
+	client.CIDRefresh = func(ctx context.Context) (string, error) {
+		return "", refreshErr
+	}
+	fake.expireSession()
+	form["CID"] = client.CID
+	if err := client.PostAPIContext(context.Background(), form["action"], form, BasicCheck); err == nil || !strings.Contains(err.Error(), refreshErr.Error()) {
+		t.Errorf("PostAPIContext() with failing CID refresh error = %v, want %v", err, refreshErr)
+	}
+}
+
+func TestRefreshExpiredCIDDuringRequests(t *testing.T) {
+	fake := &sessionTestServer{username: "admin", password: "password"}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Username:   fake.username,
+		Password:   fake.password,
+		baseURL:    srv.URL,
+	}
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login() unexpected error: %v", err)
+	}
+
+	// requests keep reading the CID while others refresh it; run with -race to check the access is guarded
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				client.CurrentCID()
+			}
+		}
+	}()
+	defer close(done)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 0 {
+				fake.expireSession()
+				return
+			}
+			for j := 0; j < 5 && errs[i] == nil; j++ {
+				form := map[string]string{"action": "list_resource_tags", "CID": client.CurrentCID()}
+				if i%2 == 0 {
+					errs[i] = client.PostAPIContext(context.Background(), form["action"], form, BasicCheck)
+					continue
+				}
+				errs[i] = client.PostFileAPIContext(context.Background(), form, nil, BasicCheck)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("request %d during CID refresh unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestPostFileRefreshesExpiredCID(t *testing.T) {
+	fake := &sessionTestServer{username: "admin", password: "password"}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := &Client{
+		HTTPClient: srv.Client(),
+		Username:   fake.username,
+		Password:   fake.password,
+		baseURL:    srv.URL,
+	}
+	if err := client.Login(); err != nil {
+		t.Fatalf("Login() unexpected error: %v", err)
+	}
+
+	post := map[string]func(map[string]string) error{
+		"PostFileAPI": func(form map[string]string) error {
+			return client.PostFileAPI(form, nil, BasicCheck)
+		},
+		"PostFileAPIContext": func(form map[string]string) error {
+			return client.PostFileAPIContext(context.Background(), form, nil, BasicCheck)
+		},
+		"PostFileAPIWithRetryContext": func(form map[string]string) error {
+			return client.PostFileAPIWithRetryContext(context.Background(), form, nil, BasicCheck)
+		},
+	}
+	for name, post := range post {
+		t.Run(name, func(t *testing.T) {
+			fake.expireSession()
+			form := map[string]string{"action": "register_cloudwan_device", "CID": client.CurrentCID()}
+			if err := post(form); err != nil {
+				t.Fatalf("%s() with expired CID unexpected error: %v", name, err)
+			}
+			if form["CID"] != client.CurrentCID() {
+				t.Errorf("%s() sent CID %q, want the refreshed CID %q", name, form["CID"], client.CurrentCID())
+			}
+		})
+	}
+	if fake.logins != 4 {
+		t.Errorf("logins = %d, want 4", fake.logins)
+	}
+}
@@ -16,7 +16,7 @@ type CloudnRegistration struct {
 
 // CreateCloudnRegistration should only be called with a CloudN Client, not the default controller Client
 func (c *Client) CreateCloudnRegistration(ctx context.Context, cloudnRegistration *CloudnRegistration) error {
-	cloudnRegistration.CID = c.CID
+	cloudnRegistration.CID = c.cid()
 	cloudnRegistration.Action = "register_caag_with_controller"
 
 	return c.PostAPIContext(ctx, cloudnRegistration.Action, cloudnRegistration, BasicCheck)
@@ -25,7 +25,7 @@ func (c *Client) CreateCloudnRegistration(ctx context.Context, cloudnRegistratio
 func (c *Client) GetCloudnRegistration(ctx context.Context, cloudnRegistration *CloudnRegistration) (*CloudnRegistration, error) {
 	data := map[string]string{
 		"action": "list_cloudwan_devices_summary",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type CloudnRegistrationAPIResult struct {
@@ -58,7 +58,7 @@ func (c *Client) GetCloudnRegistration(ctx context.Context, cloudnRegistration *
 func (c *Client) DeleteCloudnRegistration(ctx context.Context, cloudnRegistration *CloudnRegistration) error {
 	data := map[string]string{
 		"action":      "deregister_cloudwan_device",
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"device_name": cloudnRegistration.Name,
 	}
 
@@ -29,7 +29,7 @@ type CloudnTransitGatewayAttachment struct {
 
 func (c *Client) CreateCloudnTransitGatewayAttachment(ctx context.Context, attachment *CloudnTransitGatewayAttachment) error {
 	attachment.Action = "attach_cloudwan_device_to_transit_gateway"
-	attachment.CID = c.CID
+	attachment.CID = c.cid()
 	attachment.RoutingProtocol = "bgp"
 	return c.PostAPIContext(ctx, attachment.Action, attachment, BasicCheck)
 }
@@ -62,7 +62,7 @@ func (c *Client) GetCloudnTransitGatewayAttachment(ctx context.Context, connName
 
 	form := map[string]string{
 		"action":    "get_site2cloud_conn_detail",
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"conn_name": connName,
 		"vpc_id":    vpcID,
 	}
@@ -92,7 +92,7 @@ func (c *Client) GetCloudnTransitGatewayAttachment(ctx context.Context, connName
 func (c *Client) EnableJumboFrameOnConnectionToCloudn(ctx context.Context, connName, vpcID string) error {
 	form := map[string]string{
 		"action":          "enable_jumbo_frame_on_connection_to_cloudn",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"connection_name": connName,
 		"vpc_id":          vpcID,
 	}
@@ -102,7 +102,7 @@ func (c *Client) EnableJumboFrameOnConnectionToCloudn(ctx context.Context, connN
 func (c *Client) DisableJumboFrameOnConnectionToCloudn(ctx context.Context, connName, vpcID string) error {
 	form := map[string]string{
 		"action":          "disable_jumbo_frame_on_connection_to_cloudn",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"connection_name": connName,
 		"vpc_id":          vpcID,
 	}
@@ -118,7 +118,7 @@ func (c *Client) EditCloudnTransitGatewayAttachmentASPathPrepend(ctx context.Con
 		ConnectionName string `form:"connection_name"`
 		PrependASPath  string `form:"connection_as_path_prepend"`
 	}{
-		CID:            c.CID,
+		CID:            c.cid(),
 		Action:         action,
 		GatewayName:    attachment.TransitGatewayName,
 		ConnectionName: attachment.ConnectionName,
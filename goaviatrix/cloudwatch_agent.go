@@ -19,7 +19,7 @@ type CloudwatchAgentResp struct {
 func (c *Client) EnableCloudwatchAgent(r *CloudwatchAgent) error {
 	params := map[string]string{
 		"action":               "enable_cloudwatch_agent",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"cloudwatch_role_arn":  r.RoleArn,
 		"region":               r.Region,
 		"log_group_name":       r.LogGroupName,
@@ -32,7 +32,7 @@ func (c *Client) EnableCloudwatchAgent(r *CloudwatchAgent) error {
 func (c *Client) GetCloudwatchAgentStatus() (*CloudwatchAgentResp, error) {
 	params := map[string]string{
 		"action": "get_cloudwatch_agent_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -58,7 +58,7 @@ func (c *Client) GetCloudwatchAgentStatus() (*CloudwatchAgentResp, error) {
 func (c *Client) DisableCloudwatchAgent() error {
 	params := map[string]string{
 		"action": "disable_cloudwatch_agent",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	return c.PostAPI(params["action"], params, BasicCheck)
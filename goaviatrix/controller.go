@@ -59,7 +59,7 @@ type ResourceCounts struct {
 
 func (c *Client) EnableHttpAccess() error {
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "config_http_access",
 		"operation": "enable",
 	}
@@ -68,7 +68,7 @@ func (c *Client) EnableHttpAccess() error {
 
 func (c *Client) DisableHttpAccess() error {
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "config_http_access",
 		"operation": "disable",
 	}
@@ -78,7 +78,7 @@ func (c *Client) DisableHttpAccess() error {
 func (c *Client) GetHttpAccessEnabled() (string, error) {
 	var data ControllerHttpAccessResp
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "config_http_access",
 		"operation": "get",
 	}
@@ -91,7 +91,7 @@ func (c *Client) GetHttpAccessEnabled() (string, error) {
 
 func (c *Client) EnableExceptionRule() error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "enable_fqdn_exception_rule",
 	}
 	return c.PostAPI(form["action"], form, BasicCheck)
@@ -99,7 +99,7 @@ func (c *Client) EnableExceptionRule() error {
 
 func (c *Client) DisableExceptionRule() error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "disable_fqdn_exception_rule",
 	}
 	return c.PostAPI(form["action"], form, BasicCheck)
@@ -108,7 +108,7 @@ func (c *Client) DisableExceptionRule() error {
 func (c *Client) GetExceptionRuleStatus() (bool, error) {
 	var data GetFqdnExceptionRuleResp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "get_fqdn_exception_rule_status",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -123,7 +123,7 @@ func (c *Client) GetExceptionRuleStatus() (bool, error) {
 
 func (c *Client) EnableSecurityGroupManagement(account string) error {
 	form := map[string]string{
-		"CID":                 c.CID,
+		"CID":                 c.cid(),
 		"action":              "enable_controller_security_group_management",
 		"access_account_name": account,
 	}
@@ -132,7 +132,7 @@ func (c *Client) EnableSecurityGroupManagement(account string) error {
 
 func (c *Client) DisableSecurityGroupManagement() error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "disable_controller_security_group_management",
 	}
 	return c.PostAPI(form["action"], form, BasicCheck)
@@ -141,7 +141,7 @@ func (c *Client) DisableSecurityGroupManagement() error {
 func (c *Client) GetSecurityGroupManagementStatus() (*SecurityGroupInfo, error) {
 	var data GetSecurityGroupManagementResp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "get_controller_security_group_management_status",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -153,7 +153,7 @@ func (c *Client) GetSecurityGroupManagementStatus() (*SecurityGroupInfo, error)
 
 func (c *Client) EnableCloudnBackupConfig(cloudnBackupConfiguration *CloudnBackupConfiguration) error {
 	form := map[string]string{
-		"CID":            c.CID,
+		"CID":            c.cid(),
 		"action":         "enable_cloudn_backup_config",
 		"cloud_type":     strconv.Itoa(cloudnBackupConfiguration.BackupCloudType),
 		"account_name":   cloudnBackupConfiguration.BackupAccountName,
@@ -170,7 +170,7 @@ func (c *Client) EnableCloudnBackupConfig(cloudnBackupConfiguration *CloudnBacku
 
 func (c *Client) DisableCloudnBackupConfig() error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "disable_cloudn_backup_config",
 	}
 	return c.PostAPI(form["action"], form, BasicCheck)
@@ -179,7 +179,7 @@ func (c *Client) DisableCloudnBackupConfig() error {
 func (c *Client) GetCloudnBackupConfig() (*CloudnBackupConfiguration, error) {
 	var data GetCloudnBackupConfigResp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "get_cloudn_backup_config",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -197,7 +197,7 @@ func (c *Client) GetControllerVpcDnsServerStatus() (bool, error) {
 	}
 	var data Resp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "get_controller_vpc_dns_server_status",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -213,7 +213,7 @@ func (c *Client) SetControllerVpcDnsServer(enabled bool) error {
 		action = "disable_controller_vpc_dns_server"
 	}
 	return c.PostAPI(action, &APIRequest{
-		CID:    c.CID,
+		CID:    c.cid(),
 		Action: action,
 	}, BasicCheck)
 }
@@ -224,7 +224,7 @@ func (c *Client) SetEmailExceptionNotification(ctx context.Context, enabled bool
 		action = "disable_exception_email_notification"
 	}
 	return c.PostAPIContext(ctx, action, &APIRequest{
-		CID:    c.CID,
+		CID:    c.cid(),
 		Action: action,
 	}, BasicCheck)
 }
@@ -232,7 +232,7 @@ func (c *Client) SetEmailExceptionNotification(ctx context.Context, enabled bool
 func (c *Client) GetEmailExceptionNotificationStatus(ctx context.Context) (bool, error) {
 	params := map[string]string{
 		"action": "get_exception_email_notification_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -258,7 +258,7 @@ func (c *Client) GetEmailExceptionNotificationStatus(ctx context.Context) (bool,
 func (c *Client) SetCertDomain(ctx context.Context, certDomain string) error {
 	params := map[string]string{
 		"action":      "set_cert_domain",
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"cert_domain": certDomain,
 	}
 	return c.PostAPIContext(ctx, params["action"], params, BasicCheck)
@@ -267,7 +267,7 @@ func (c *Client) SetCertDomain(ctx context.Context, certDomain string) error {
 func (c *Client) GetCertDomain(ctx context.Context) (*CertDomainConfig, error) {
 	params := map[string]string{
 		"action": "list_cert_domain",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -289,7 +289,7 @@ func (c *Client) GetCertDomain(ctx context.Context) (*CertDomainConfig, error) {
 func (c *Client) GetGatewayCount(ctx context.Context) (int, error) {
 	params := map[string]string{
 		"action": "list_resource_counts",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
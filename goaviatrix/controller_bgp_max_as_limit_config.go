@@ -10,7 +10,7 @@ import (
 func (c *Client) SetControllerBgpMaxAsLimit(ctx context.Context, maxAsLimit int) error {
 	data := map[string]string{
 		"action":       "set_bgp_max_as_limit",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"max_as_limit": fmt.Sprint(maxAsLimit),
 	}
 
@@ -26,7 +26,7 @@ func (c *Client) SetControllerBgpMaxAsLimit(ctx context.Context, maxAsLimit int)
 func (c *Client) DisableControllerBgpMaxAsLimit(ctx context.Context) error {
 	data := map[string]string{
 		"action":       "set_bgp_max_as_limit",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"max_as_limit": "",
 	}
 
@@ -42,7 +42,7 @@ func (c *Client) DisableControllerBgpMaxAsLimit(ctx context.Context) error {
 func (c *Client) GetControllerBgpMaxAsLimit(ctx context.Context) (int, error) {
 	data := map[string]string{
 		"action": "show_bgp_max_as_limit",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type BgpMaxAsLimitResults struct {
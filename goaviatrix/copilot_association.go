@@ -5,7 +5,7 @@ import "context"
 func (c *Client) EnableCopilotAssociation(ctx context.Context, addr string) error {
 	form := map[string]string{
 		"action":     "enable_copilot_association",
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"copilot_ip": addr,
 	}
 	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
@@ -14,7 +14,7 @@ func (c *Client) EnableCopilotAssociation(ctx context.Context, addr string) erro
 func (c *Client) DisableCopilotAssociation(ctx context.Context) error {
 	form := map[string]string{
 		"action": "disable_copilot_association",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
 }
@@ -27,7 +27,7 @@ type CopilotAssociationStatus struct {
 func (c *Client) GetCopilotAssociationStatus(ctx context.Context) (*CopilotAssociationStatus, error) {
 	form := map[string]string{
 		"action": "get_copilot_association_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	var resp struct {
 		APIResp
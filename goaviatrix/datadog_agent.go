@@ -21,7 +21,7 @@ type DatadogAgentResp struct {
 func (c *Client) EnableDatadogAgent(r *DatadogAgent) error {
 	params := map[string]string{
 		"action":               "enable_datadog_agent_logging",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"api_key":              r.ApiKey,
 		"site":                 r.Site,
 		"exclude_gateway_list": r.ExcludedGatewaysInput,
@@ -34,7 +34,7 @@ func (c *Client) EnableDatadogAgent(r *DatadogAgent) error {
 func (c *Client) GetDatadogAgentStatus() (*DatadogAgentResp, error) {
 	params := map[string]string{
 		"action": "get_datadog_agent_logging_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -60,7 +60,7 @@ func (c *Client) GetDatadogAgentStatus() (*DatadogAgentResp, error) {
 func (c *Client) DisableDatadogAgent() error {
 	params := map[string]string{
 		"action": "disable_datadog_agent_logging",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	return c.PostAPI(params["action"], params, BasicCheck)
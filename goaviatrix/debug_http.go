@@ -0,0 +1,188 @@
+package goaviatrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// redactedValue replaces the values of sensitive parameters in the logged requests and responses
+const redactedValue = "REDACTED"
+
+// sensitiveParamParts are lowercased parts of the names of parameters whose values are never logged
+var sensitiveParamParts = []string{"password", "passphrase", "secret", "token", "private_key", "key_file", "api_key", "access_key"}
+
+// privateKeyRegexp matches PEM encoded private keys, e.g. the content of an uploaded key file
+var privateKeyRegexp = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+// isSensitiveParam reports whether the value of the request or response parameter name must not be logged
+func isSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	if name == "cid" {
+		return true
+	}
+	for _, part := range sensitiveParamParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactText masks the private keys in text
+func redactText(text string) string {
+	return privateKeyRegexp.ReplaceAllString(text, redactedValue)
+}
+
+// redactValues returns the form or query values encoded, with the sensitive values masked
+func redactValues(values url.Values) string {
+	redacted := url.Values{}
+	for key, vals := range values {
+		for _, val := range vals {
+			if isSensitiveParam(key) {
+				val = redactedValue
+			}
+			redacted.Add(key, redactText(val))
+		}
+	}
+	return redacted.Encode()
+}
+
+// redactJSON returns the JSON body with the values of sensitive keys masked, at any depth
+func redactJSON(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return redactText(string(body))
+	}
+	redacted, err := json.Marshal(redactJSONValue(v))
+	if err != nil {
+		return redactText(string(body))
+	}
+	return string(redacted)
+}
+
+func redactJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if isSensitiveParam(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSONValue(val)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactJSONValue(val)
+		}
+		return v
+	case string:
+		return redactText(v)
+	}
+	return v
+}
+
+// redactRequestBody returns the body of req with the sensitive values masked. Files uploaded in a
+// multipart body are replaced by their names.
+func redactRequestBody(req *http.Request) (string, error) {
+	if req.GetBody == nil {
+		return "", nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+		values, err := url.ParseQuery(string(b))
+		if err != nil {
+			return "", err
+		}
+		return redactValues(values), nil
+	case "multipart/form-data":
+		values := url.Values{}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return redactValues(values), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if part.FileName() != "" {
+				values.Add(part.FormName(), fmt.Sprintf("<file %s>", part.FileName()))
+				continue
+			}
+			b, err := ioutil.ReadAll(part)
+			if err != nil {
+				return "", err
+			}
+			values.Add(part.FormName(), string(b))
+		}
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return redactJSON(b), nil
+}
+
+// logDebugRequest logs the query and the body of req, with the sensitive values masked
+func logDebugRequest(req *http.Request, requestID, action string) {
+	fields := log.Fields{
+		"request_id": requestID,
+		"action":     action,
+		"method":     req.Method,
+	}
+	if query := req.URL.Query(); len(query) > 0 {
+		fields["query"] = redactValues(query)
+	}
+	body, err := redactRequestBody(req)
+	if err != nil {
+		body = fmt.Sprintf("<could not read body: %v>", err)
+	}
+	if body != "" {
+		fields["body"] = body
+	}
+	log.WithFields(fields).Info("HTTP request")
+}
+
+// logDebugResponse logs the body of resp, with the sensitive values masked. The body is read and
+// replaced, so that it can still be decoded by the caller.
+func logDebugResponse(resp *http.Response, requestID, action string) {
+	fields := log.Fields{
+		"request_id": requestID,
+		"action":     action,
+		"status":     resp.StatusCode,
+	}
+	if resp.Body != nil {
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		if err != nil {
+			fields["body"] = fmt.Sprintf("<could not read body: %v>", err)
+		} else {
+			fields["body"] = redactJSON(b)
+		}
+	}
+	log.WithFields(fields).Info("HTTP response")
+}
@@ -1,24 +1,37 @@
 package goaviatrix
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
-// Device represents a device used in CloudWAN
+// Device represents a device used in CloudWAN.
+// Not all controller versions return PublicIPHa, the Bastion fields, CloudType, CreatedAt, RegisteredBy,
+// HostKeyFingerprint, Model, SerialNumber, ManagedBy, Region, Zone and Mtu. They are left empty when missing.
 type Device struct {
 	Action             string               `form:"action,omitempty" json:"-"`
 	CID                string               `form:"CID,omitempty" json:"-"`
 	Name               string               `form:"device_name,omitempty" json:"rgw_name"`
 	PublicIP           string               `form:"public_ip,omitempty" json:"hostname"`
+	PublicIPHa         string               `form:"public_ip_ha,omitempty" json:"public_ip_ha"`
 	Username           string               `form:"username,omitempty" json:"username"`
 	KeyFile            string               `form:"-" json:"-"`
+	KeyFileContent     string               `form:"-" json:"-"`
+	KeyPassphrase      string               `form:"private_key_passphrase,omitempty" json:"-"`
 	Password           string               `form:"password,omitempty" json:"-"`
 	HostOS             string               `form:"host_os,omitempty" json:"host_os"`
 	SshPort            int                  `form:"-" json:"ssh_port"`
 	SshPortStr         string               `form:"port,omitempty" json:"-"`
+	BastionIP          string               `form:"-" json:"bastion_ip"`
+	BastionUsername    string               `form:"-" json:"bastion_username"`
+	BastionPort        int                  `form:"-" json:"bastion_port"`
 	Address1           string               `form:"addr_1,omitempty" json:"-"`
 	Address2           string               `form:"addr_2,omitempty" json:"-"`
 	City               string               `form:"city,omitempty" json:"-"`
@@ -34,6 +47,17 @@ type Device struct {
 	ConnectionName     string               `form:"-" json:"conn_name"`
 	SoftwareVersion    string               `form:"-" json:"software_version"`
 	IsCaag             bool                 `form:"-" json:"is_caag"`
+	CloudType          int                  `form:"-" json:"cloud_type"`
+	CreatedAt          string               `form:"-" json:"created_at"`
+	RegisteredBy       string               `form:"-" json:"registered_by"`
+	HostKeyFingerprint string               `form:"-" json:"host_key_fingerprint"`
+	Model              string               `form:"-" json:"model"`
+	SerialNumber       string               `form:"-" json:"serial_number"`
+	ManagedBy          string               `form:"-" json:"managed_by"`
+	Region             string               `form:"region,omitempty" json:"region"`
+	Zone               string               `form:"zone,omitempty" json:"zone"`
+	Mtu                int                  `form:"-" json:"mtu"`
+	AccountName        string               `form:"account_name,omitempty" json:"account_name"`
 }
 
 type DeviceInterfaceConfig struct {
@@ -51,10 +75,32 @@ type GetDeviceRespAddress struct {
 	ZipCode  string `json:"zipcode"`
 }
 
-func (c *Client) RegisterDevice(d *Device) error {
+// keyFiles returns the private key to upload, either read from KeyFile or taken from KeyFileContent
+func (d *Device) keyFiles() []File {
+	if d.KeyFileContent != "" {
+		return []File{
+			{
+				ParamName:      "private_key_file",
+				UseFileContent: true,
+				FileName:       "private_key.pem", // fake name for key
+				FileContent:    d.KeyFileContent,
+			},
+		}
+	}
+	return []File{
+		{
+			Path:      d.KeyFile,
+			ParamName: "private_key_file",
+		},
+	}
+}
+
+func (c *Client) RegisterDevice(ctx context.Context, d *Device) error {
+	defer c.InvalidateDeviceCache()
+
 	form := map[string]string{
 		"action":      "register_cloudwan_device",
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"device_name": d.Name,
 		"public_ip":   d.PublicIP,
 		"username":    d.Username,
@@ -69,50 +115,422 @@ func (c *Client) RegisterDevice(d *Device) error {
 		"zipcode":     d.ZipCode,
 		"description": d.Description,
 	}
-	files := []File{
-		{
-			Path:      d.KeyFile,
-			ParamName: "private_key_file",
-		},
+	if d.KeyPassphrase != "" {
+		form["private_key_passphrase"] = d.KeyPassphrase
+	}
+	if d.AccountName != "" {
+		form["account_name"] = d.AccountName
+	}
+	if d.PublicIPHa != "" {
+		// the second appliance of an HA pair registered as one device
+		form["public_ip_ha"] = d.PublicIPHa
 	}
-	return c.PostFileAPI(form, files, BasicCheck)
+	if d.Mtu != 0 {
+		form["mtu"] = strconv.Itoa(d.Mtu)
+	}
+	if d.Region != "" {
+		form["region"] = d.Region
+		if d.Zone != "" {
+			form["zone"] = d.Zone
+		}
+	}
+	if d.BastionIP != "" {
+		// the controller connects to the device through the bastion host
+		form["bastion_ip"] = d.BastionIP
+		form["bastion_username"] = d.BastionUsername
+		form["bastion_port"] = strconv.Itoa(d.BastionPort)
+	}
+	err := c.PostFileAPIWithRetryContext(ctx, form, d.keyFiles(), BasicCheck)
+	if err != nil {
+		if algorithm := d.keyAlgorithm(); algorithm != "" && algorithm != PrivateKeyAlgorithmRSA {
+			return fmt.Errorf("%w (the private key is an %s key, check that the controller supports "+
+				"%s keys for device registration or use an RSA key)", err, algorithm, algorithm)
+		}
+		return err
+	}
+	return nil
 }
 
-func (c *Client) GetDevice(d *Device) (*Device, error) {
+// ListDevices returns all devices registered with the controller. Controllers that paginate the
+// device list are asked for every page.
+func (c *Client) ListDevices(ctx context.Context) ([]*Device, error) {
 	type Resp struct {
-		Return  bool     `json:"return"`
-		Results []Device `json:"results"`
-		Reason  string   `json:"reason"`
+		Return     bool        `json:"return"`
+		Results    []Device    `json:"results"`
+		Reason     string      `json:"reason"`
+		Pagination *Pagination `json:"pagination"`
 	}
-	var data Resp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_cloudwan_devices_summary",
 	}
-	err := c.GetAPI(&data, form["action"], form, BasicCheck)
+
+	var devices []*Device
+	err := listAllPages(form, func(pageForm map[string]string) (*Pagination, error) {
+		var data Resp
+		if err := c.GetAPIContext(ctx, &data, pageForm["action"], pageForm, BasicCheck); err != nil {
+			return nil, err
+		}
+		for i := range data.Results {
+			device := &data.Results[i]
+			device.Address1 = device.Address.Address1
+			device.Address2 = device.Address.Address2
+			device.City = device.Address.City
+			device.State = device.Address.State
+			device.Country = device.Address.Country
+			device.ZipCode = device.Address.ZipCode
+			devices = append(devices, device)
+		}
+		return data.Pagination, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	var foundDevice *Device
-	for _, device := range data.Results {
-		if device.Name == d.Name {
-			foundDevice = &device
+	if devices == nil {
+		devices = []*Device{}
+	}
+	return devices, nil
+}
+
+// ListDevicesCached behaves like ListDevices, but only calls the controller when there is no
+// result cached on the client yet. The cache is invalidated whenever a device is registered,
+// updated or deregistered through this client, or by calling InvalidateDeviceCache. It does not see
+// changes made outside of this client, so it is only meant for reading many devices at once, e.g. by
+// the bulk registration. Reading a single device should use GetDevice.
+func (c *Client) ListDevicesCached(ctx context.Context) ([]*Device, error) {
+	c.deviceCacheMu.Lock()
+	defer c.deviceCacheMu.Unlock()
+
+	if c.deviceCache != nil {
+		return c.deviceCache, nil
+	}
+	devices, err := c.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.deviceCache = devices
+	return devices, nil
+}
+
+// InvalidateDeviceCache clears the devices cached by ListDevicesCached
+func (c *Client) InvalidateDeviceCache() {
+	c.deviceCacheMu.Lock()
+	c.deviceCache = nil
+	c.deviceCacheMu.Unlock()
+}
+
+func (c *Client) GetDevice(ctx context.Context, d *Device) (*Device, error) {
+	devices, err := c.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findDevice(devices, d.Name, d.AccountName)
+}
+
+// deviceVisibleBackoff is the initial time between two checks of WaitForDevice
+var deviceVisibleBackoff = 500 * time.Millisecond
+
+// WaitForDevice returns the device once the controller lists it. Right after registration the controller
+// may not list the device yet, so ErrNotFound is retried with exponential backoff until timeout elapses.
+func (c *Client) WaitForDevice(ctx context.Context, d *Device, timeout time.Duration) (*Device, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := deviceVisibleBackoff
+	for {
+		device, err := c.GetDevice(ctx, d)
+		if err != ErrNotFound {
+			return device, err
+		}
+
+		log.Infof("device %s is not listed by the controller yet, checking again in %s", d.Name, backoff)
+		select {
+		case <-ctx.Done():
+			return nil, ErrNotFound
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// GetDeviceCached behaves like GetDevice, but looks the device up from ListDevicesCached
+func (c *Client) GetDeviceCached(ctx context.Context, d *Device) (*Device, error) {
+	devices, err := c.ListDevicesCached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findDevice(devices, d.Name, d.AccountName)
+}
+
+// GetDeviceByIP returns the device registered with the given public IP.
+// Unlike the name, the public IP does not change when a device is renamed outside of Terraform.
+func (c *Client) GetDeviceByIP(ctx context.Context, publicIP string) (*Device, error) {
+	devices, err := c.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Device
+	for _, device := range devices {
+		if device.PublicIP != publicIP {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("multiple devices are registered with public IP %s: %s and %s", publicIP, found.Name, device.Name)
+		}
+		found = device
+	}
+	if found == nil {
+		log.Errorf("Could not find Aviatrix device with public IP %s", publicIP)
+		return nil, ErrNotFound
+	}
+	// return a copy so callers can not modify the cached device
+	foundDevice := *found
+	return &foundDevice, nil
+}
+
+// findDevice returns the device with the given name. If accountName is set, a device that the
+// controller reports under a different account does not match.
+func findDevice(devices []*Device, name, accountName string) (*Device, error) {
+	// The controller treats device names case-insensitively. Prefer a device with the exact name,
+	// but also find a device whose name only differs in case.
+	var foldedMatch *Device
+	for _, device := range devices {
+		if !strings.EqualFold(device.Name, name) ||
+			(accountName != "" && device.AccountName != "" && device.AccountName != accountName) {
+			continue
+		}
+		if device.Name == name {
+			foldedMatch = device
 			break
 		}
+		if foldedMatch == nil {
+			foldedMatch = device
+		}
 	}
-	if foundDevice == nil {
-		log.Errorf("Could not find Aviatrix device %s", d.Name)
+	if foldedMatch == nil {
+		log.Errorf("Could not find Aviatrix device %s", name)
 		return nil, ErrNotFound
 	}
+	// return a copy so callers can not modify the cached device
+	foundDevice := *foldedMatch
+	return &foundDevice, nil
+}
+
+// GetDeviceConnectionStatus returns the status of the controller's connection to the device,
+// e.g. 'connected' or 'disconnected'
+func (c *Client) GetDeviceConnectionStatus(ctx context.Context, d *Device) (string, error) {
+	type Result struct {
+		Status string `json:"status"`
+	}
+	type Resp struct {
+		Return  bool   `json:"return"`
+		Results Result `json:"results"`
+		Reason  string `json:"reason"`
+	}
+	var data Resp
+	form := map[string]string{
+		"CID":         c.cid(),
+		"action":      "get_cloudwan_device_connection_status",
+		"device_name": d.Name,
+	}
+	err := c.GetAPIContext(ctx, &data, form["action"], form, BasicCheck)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(data.Results.Status), nil
+}
+
+// ErrDeviceRenameNotSupported is returned by RenameDevice if the controller can not rename devices,
+// e.g. because it runs an older version
+var ErrDeviceRenameNotSupported = errors.New("the controller does not support renaming devices")
+
+// RenameDevice renames the device oldName to newName, keeping its connections and attachments
+func (c *Client) RenameDevice(ctx context.Context, oldName, newName string) error {
+	defer c.InvalidateDeviceCache()
+	defer c.invalidateTagCacheByName(oldName)
+
+	form := map[string]string{
+		"CID":             c.cid(),
+		"action":          "rename_cloudwan_device",
+		"device_name":     oldName,
+		"new_device_name": newName,
+	}
+	err := c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+	if err != nil && isUnsupportedActionError(err) {
+		return ErrDeviceRenameNotSupported
+	}
+	return err
+}
+
+// DeviceStatusUnknown is the connection status of a device whose status could not be fetched
+const DeviceStatusUnknown = "unknown"
+
+// DeviceConnectionStatus is the status of the controller's connection to a device
+type DeviceConnectionStatus struct {
+	Name     string `json:"device_name"`
+	Status   string `json:"status"`
+	LastSeen string `json:"last_seen"` // not returned by all controller versions
+}
+
+// ListDeviceConnectionStatuses returns the connection status of all devices in a single call, keyed
+// by the lower case device name since the controller treats device names case-insensitively
+func (c *Client) ListDeviceConnectionStatuses(ctx context.Context) (map[string]*DeviceConnectionStatus, error) {
+	type Resp struct {
+		Return  bool                     `json:"return"`
+		Results []DeviceConnectionStatus `json:"results"`
+		Reason  string                   `json:"reason"`
+	}
+	var data Resp
+	form := map[string]string{
+		"CID":    c.cid(),
+		"action": "list_cloudwan_device_connection_status",
+	}
+	err := c.GetAPIContext(ctx, &data, form["action"], form, BasicCheck)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]*DeviceConnectionStatus, len(data.Results))
+	for i := range data.Results {
+		status := &data.Results[i]
+		status.Status = strings.ToLower(status.Status)
+		statuses[strings.ToLower(status.Name)] = status
+	}
+	return statuses, nil
+}
 
-	foundDevice.Address1 = foundDevice.Address.Address1
-	foundDevice.Address2 = foundDevice.Address.Address2
-	foundDevice.City = foundDevice.Address.City
-	foundDevice.State = foundDevice.Address.State
-	foundDevice.Country = foundDevice.Address.Country
-	foundDevice.ZipCode = foundDevice.Address.ZipCode
+// GetDevicesHealth returns the connection status of each of devices, in the same order. The statuses
+// are fetched in a single call. Devices missing from its result, or all devices if it fails, are
+// queried one by one. Devices whose status can not be fetched have status DeviceStatusUnknown, so
+// a single device does not fail the whole result.
+func (c *Client) GetDevicesHealth(ctx context.Context, devices []*Device) []*DeviceConnectionStatus {
+	statuses, err := c.ListDeviceConnectionStatuses(ctx)
+	if err != nil {
+		log.WithField("error", err).Warn("could not list connection status of devices, querying devices one by one")
+	}
 
-	return foundDevice, nil
+	health := make([]*DeviceConnectionStatus, 0, len(devices))
+	for _, device := range devices {
+		if status, ok := statuses[strings.ToLower(device.Name)]; ok {
+			health = append(health, &DeviceConnectionStatus{Name: device.Name, Status: status.Status, LastSeen: status.LastSeen})
+			continue
+		}
+		status, err := c.GetDeviceConnectionStatus(ctx, device)
+		if err != nil || status == "" {
+			log.WithFields(log.Fields{
+				"device": device.Name,
+				"error":  err,
+			}).Warn("could not get connection status of device")
+			status = DeviceStatusUnknown
+		}
+		health = append(health, &DeviceConnectionStatus{Name: device.Name, Status: status})
+	}
+	return health
+}
+
+// RebootDevice has the controller reboot the named device. The device is disconnected from the
+// controller until it has restarted, see WaitForDeviceConnected.
+func (c *Client) RebootDevice(ctx context.Context, name string) error {
+	defer c.InvalidateDeviceCache()
+
+	form := map[string]string{
+		"CID":         c.cid(),
+		"action":      "reboot_cloudwan_device",
+		"device_name": name,
+	}
+	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+}
+
+// deviceConnectedPollInterval is the time between two checks of WaitForDeviceConnected
+var deviceConnectedPollInterval = 15 * time.Second
+
+// WaitForDeviceConnected polls the connection status of the named device until it is 'connected', or
+// returns an error once timeout has elapsed or ctx is done. The first check is made after one poll
+// interval, so a device that was just rebooted is not reported as connected before it went down.
+func (c *Client) WaitForDeviceConnected(ctx context.Context, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var status string
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("device %s was not connected within %s, last reported status %q: %v",
+				name, timeout, status, ctx.Err())
+		case <-time.After(deviceConnectedPollInterval):
+		}
+
+		currentStatus, err := c.GetDeviceConnectionStatus(ctx, &Device{Name: name})
+		if err != nil {
+			// the controller can report errors while the device restarts
+			log.WithFields(log.Fields{
+				"device": name,
+				"error":  err,
+			}).Warn("could not get connection status of device, will retry")
+			continue
+		}
+		status = currentStatus
+		if status == "connected" {
+			log.Infof("device %s is connected", name)
+			return nil
+		}
+		log.Infof("waiting for device %s to be connected: current status %q", name, status)
+	}
+}
+
+// RefreshDeviceSoftwareVersion has the controller query the named device for the software version it is
+// running, instead of reporting the version last recorded by the controller, and returns it. Use it to
+// detect upgrades made outside of the controller.
+func (c *Client) RefreshDeviceSoftwareVersion(ctx context.Context, name string) (string, error) {
+	defer c.InvalidateDeviceCache()
+
+	type Result struct {
+		SoftwareVersion string `json:"software_version"`
+	}
+	type Resp struct {
+		Return  bool   `json:"return"`
+		Results Result `json:"results"`
+		Reason  string `json:"reason"`
+	}
+	var data Resp
+	form := map[string]string{
+		"CID":         c.cid(),
+		"action":      "refresh_cloudwan_device_software_version",
+		"device_name": name,
+	}
+	err := c.GetAPIContext(ctx, &data, form["action"], form, BasicCheck)
+	if err != nil {
+		return "", err
+	}
+	return data.Results.SoftwareVersion, nil
+}
+
+// CheckDeviceReachable has the controller probe SSH connectivity to publicIP on sshPort. It returns
+// an error if the device can not be reached, so registration can fail fast instead of timing out.
+func (c *Client) CheckDeviceReachable(ctx context.Context, publicIP string, sshPort int) error {
+	type Result struct {
+		Reachable bool `json:"reachable"`
+	}
+	type Resp struct {
+		Return  bool   `json:"return"`
+		Results Result `json:"results"`
+		Reason  string `json:"reason"`
+	}
+	var data Resp
+	form := map[string]string{
+		"CID":       c.cid(),
+		"action":    "check_cloudwan_device_reachability",
+		"public_ip": publicIP,
+		"ssh_port":  strconv.Itoa(sshPort),
+	}
+	err := c.GetAPIContext(ctx, &data, form["action"], form, BasicCheck)
+	if err != nil {
+		return fmt.Errorf("device %s:%d not reachable: %w", publicIP, sshPort, err)
+	}
+	if !data.Results.Reachable {
+		return fmt.Errorf("device %s:%d not reachable", publicIP, sshPort)
+	}
+	return nil
 }
 
 func (c *Client) GetDeviceName(connName string) (string, error) {
@@ -123,7 +541,7 @@ func (c *Client) GetDeviceName(connName string) (string, error) {
 	}
 	var data Resp
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_cloudwan_devices_summary",
 	}
 	err := c.GetAPI(&data, form["action"], form, BasicCheck)
@@ -132,9 +550,7 @@ func (c *Client) GetDeviceName(connName string) (string, error) {
 	}
 
 	for _, device := range data.Results {
-		// ConnectionName is actually a CSV list of connection names
-		conns := strings.Split(device.ConnectionName, ",")
-		for _, c := range conns {
+		for _, c := range device.connectionNames() {
 			if c == connName {
 				return device.Name, nil
 			}
@@ -144,10 +560,90 @@ func (c *Client) GetDeviceName(connName string) (string, error) {
 	return "", ErrNotFound
 }
 
-func (c *Client) UpdateDevice(d *Device) error {
+// connectionNames returns the names of the connections attached to the device
+func (d *Device) connectionNames() []string {
+	var names []string
+	// ConnectionName is actually a CSV list of connection names
+	for _, name := range strings.Split(d.ConnectionName, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetDeviceAttachments returns the names of the connections attached to the device, e.g. transit
+// gateway, AWS TGW or Azure Virtual WAN attachments, which prevent the device from being deregistered
+func (c *Client) GetDeviceAttachments(ctx context.Context, name string) ([]string, error) {
+	devices, err := c.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	device, err := findDevice(devices, name, "")
+	if err != nil {
+		return nil, err
+	}
+	return device.connectionNames(), nil
+}
+
+// DetachDeviceConnections detaches all connections attached to the device, so that it can be
+// deregistered. It does nothing if the device does not exist.
+func (c *Client) DetachDeviceConnections(ctx context.Context, name string) error {
+	defer c.InvalidateDeviceCache()
+
+	attachments, err := c.GetDeviceAttachments(ctx, name)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get attachments of device %s: %w", name, err)
+	}
+	for _, connectionName := range attachments {
+		log.Infof("detaching connection %s from device %s", connectionName, name)
+		if err := c.DeleteDeviceAttachment(connectionName); err != nil {
+			return fmt.Errorf("could not detach connection %s from device %s: %w", connectionName, name, err)
+		}
+	}
+	return nil
+}
+
+// ErrDevicePublicIPUpdateNotSupported is returned by UpdateDevice, wrapped with the reason of the
+// controller, if the controller can not change the public IPs of a registered device. Check for it
+// with errors.Is.
+var ErrDevicePublicIPUpdateNotSupported = errors.New("the controller does not support changing the public IP of a registered device")
+
+// publicIPUpdateNotSupportedReasons are the lowercased reasons the controller gives for refusing to
+// change the public IPs of a registered device
+var publicIPUpdateNotSupportedReasons = []string{
+	"cannot change public ip of a registered device",
+	"public ip of a registered device cannot be changed",
+}
+
+// isPublicIPUpdateNotSupportedError reports whether the controller rejected an update of a device
+// because it can not update devices, or can not change their public IPs
+func isPublicIPUpdateNotSupportedError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if isUnsupportedActionError(apiErr) {
+		return true
+	}
+	reason := strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(apiErr.Reason), ".")))
+	for _, notSupported := range publicIPUpdateNotSupportedReasons {
+		if reason == notSupported {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) UpdateDevice(ctx context.Context, d *Device) error {
+	defer c.InvalidateDeviceCache()
+
 	form := map[string]string{
 		"action":      "update_cloudwan_device_info",
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"device_name": d.Name,
 		"public_ip":   d.PublicIP,
 		"username":    d.Username,
@@ -162,22 +658,159 @@ func (c *Client) UpdateDevice(d *Device) error {
 		"zipcode":     d.ZipCode,
 		"description": d.Description,
 	}
-	files := []File{
-		{
-			Path:      d.KeyFile,
-			ParamName: "private_key_file",
-		},
+	if d.KeyPassphrase != "" {
+		form["private_key_passphrase"] = d.KeyPassphrase
+	}
+	if d.PublicIPHa != "" {
+		form["public_ip_ha"] = d.PublicIPHa
+	}
+	if d.Mtu != 0 {
+		form["mtu"] = strconv.Itoa(d.Mtu)
+	}
+	err := c.PostFileAPIContext(ctx, form, d.keyFiles(), BasicCheck)
+	if isPublicIPUpdateNotSupportedError(err) {
+		return fmt.Errorf("%w: %v", ErrDevicePublicIPUpdateNotSupported, err)
+	}
+	return err
+}
+
+// ErrDeviceMetadataUpdateNotSupported is returned by UpdateDeviceMetadata if the controller can only update
+// devices with UpdateDevice, e.g. because it runs an older version
+var ErrDeviceMetadataUpdateNotSupported = errors.New("the controller does not support updating device metadata only")
+
+// UpdateDeviceMetadata updates the address and description of a device. Unlike UpdateDevice, it does not
+// send the public IP, credentials or SSH port, so the controller does not connect to the device again.
+func (c *Client) UpdateDeviceMetadata(ctx context.Context, d *Device) error {
+	defer c.InvalidateDeviceCache()
+
+	form := map[string]string{
+		"CID":         c.cid(),
+		"action":      "update_cloudwan_device_metadata",
+		"device_name": d.Name,
+		"addr_1":      d.Address1,
+		"addr_2":      d.Address2,
+		"city":        d.City,
+		"state":       d.State,
+		"country":     d.Country,
+		"zipcode":     d.ZipCode,
+		"description": d.Description,
 	}
-	return c.PostFileAPI(form, files, BasicCheck)
+	err := c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+	if err != nil && isUnsupportedActionError(err) {
+		return ErrDeviceMetadataUpdateNotSupported
+	}
+	return err
 }
 
-func (c *Client) DeregisterDevice(d *Device) error {
+func (c *Client) DeregisterDevice(ctx context.Context, d *Device) error {
+	defer c.InvalidateDeviceCache()
+	defer c.invalidateTagCacheByName(d.Name)
+
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "deregister_cloudwan_device",
 		"device_name": d.Name,
 	}
-	return c.PostAPI(form["action"], form, BasicCheck)
+	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+}
+
+// BulkDeviceError is returned by DeregisterDevicesBulk when some of the devices could not be deregistered
+type BulkDeviceError struct {
+	Errors map[string]error // device name to the error deregistering it
+}
+
+// FailedDeviceNames returns the sorted names of the devices that could not be deregistered
+func (e *BulkDeviceError) FailedDeviceNames() []string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *BulkDeviceError) Error() string {
+	var failures []string
+	for _, name := range e.FailedDeviceNames() {
+		failures = append(failures, fmt.Sprintf("%s: %v", name, e.Errors[name]))
+	}
+	return fmt.Sprintf("could not deregister %d device(s): %s", len(failures), strings.Join(failures, "; "))
+}
+
+// DeregisterDevicesBulk deregisters all the named devices in a single call. Controllers that do not
+// support deregistering multiple devices at once are sent one DeregisterDevice call per device instead.
+// If any device could not be deregistered, a *BulkDeviceError naming the failed devices is returned, so
+// that only those need to be retried. If the single call fails, the devices are listed to find out
+// which of them are still registered.
+func (c *Client) DeregisterDevicesBulk(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	defer c.InvalidateDeviceCache()
+	defer func() {
+		for _, name := range names {
+			c.invalidateTagCacheByName(name)
+		}
+	}()
+
+	form := map[string]string{
+		"CID":              c.cid(),
+		"action":           "deregister_cloudwan_devices_bulk",
+		"device_name_list": strings.Join(names, ","),
+	}
+	err := c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+	if err == nil {
+		return nil
+	}
+
+	bulkErr := &BulkDeviceError{Errors: make(map[string]error)}
+	if isUnsupportedActionError(err) {
+		for _, name := range names {
+			if err := c.DeregisterDevice(ctx, &Device{Name: name}); err != nil {
+				bulkErr.Errors[name] = err
+			}
+		}
+		if len(bulkErr.Errors) > 0 {
+			return bulkErr
+		}
+		return nil
+	}
+
+	devices, listErr := c.ListDevices(ctx)
+	if listErr != nil {
+		log.Warnf("could not list devices to find out which were deregistered: %v", listErr)
+		for _, name := range names {
+			bulkErr.Errors[name] = err
+		}
+		return bulkErr
+	}
+	registered := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		registered[strings.ToLower(device.Name)] = true
+	}
+	for _, name := range names {
+		if registered[strings.ToLower(name)] {
+			bulkErr.Errors[name] = err
+		}
+	}
+	if len(bulkErr.Errors) > 0 {
+		return bulkErr
+	}
+	return nil
+}
+
+// NewDeviceTags returns the Tags of the named device. Devices do not run in a cloud, so no cloud type is
+// set. Managed CloudN (CaaG) devices are tagged as gateways, other devices as devices.
+func NewDeviceTags(name string, isCaag bool, tags map[string]string) *Tags {
+	resourceType := "device"
+	if isCaag {
+		resourceType = "gw"
+	}
+	return &Tags{
+		ResourceType: resourceType,
+		ResourceName: name,
+		Tags:         tags,
+	}
 }
 
 func (c *Client) ConfigureDeviceInterfaces(config *DeviceInterfaceConfig) error {
@@ -192,7 +825,7 @@ func (c *Client) ConfigureDeviceInterfaces(config *DeviceInterfaceConfig) error
 	}
 
 	form := map[string]string{
-		"CID":            c.CID,
+		"CID":            c.cid(),
 		"action":         "config_cloudwan_device_wan_interfaces",
 		"device_name":    config.DeviceName,
 		"wan_primary_if": config.PrimaryInterface,
@@ -213,7 +846,7 @@ func (c *Client) GetDeviceInterfaces(device *Device) ([]string, error) {
 	}
 	var data Resp
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "get_cloudwan_device_wan_interfaces",
 		"device_name": device.Name,
 	}
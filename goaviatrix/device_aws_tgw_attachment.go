@@ -23,14 +23,14 @@ func (b *DeviceAwsTgwAttachment) ID() string {
 
 func (c *Client) CreateDeviceAwsTgwAttachment(attachment *DeviceAwsTgwAttachment) error {
 	attachment.Action = "attach_cloudwan_device_to_aws_tgw"
-	attachment.CID = c.CID
+	attachment.CID = c.cid()
 	return c.PostAPI(attachment.Action, attachment, BasicCheck)
 }
 
 func (c *Client) GetDeviceAwsTgwAttachment(tgwAttachment *DeviceAwsTgwAttachment) (*DeviceAwsTgwAttachment, error) {
 	form := map[string]string{
 		"action":                    "list_tgw_details",
-		"CID":                       c.CID,
+		"CID":                       c.cid(),
 		"connection_name":           tgwAttachment.ConnectionName,
 		"device_name":               tgwAttachment.DeviceName,
 		"tgw_name":                  tgwAttachment.AwsTgwName,
@@ -0,0 +1,96 @@
+package goaviatrix
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// DeviceConnectionProfile holds the username, credentials and SSH port shared by devices in CloudWAN
+type DeviceConnectionProfile struct {
+	Name           string `json:"profile_name"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	KeyFileContent string `json:"private_key"`
+	KeyPassphrase  string `json:"private_key_passphrase"`
+	SshPort        int    `json:"ssh_port"`
+}
+
+func (p *DeviceConnectionProfile) form(action, cid string) map[string]string {
+	return map[string]string{
+		"action":                 action,
+		"CID":                    cid,
+		"profile_name":           p.Name,
+		"username":               p.Username,
+		"password":               p.Password,
+		"private_key":            p.KeyFileContent,
+		"private_key_passphrase": p.KeyPassphrase,
+		"port":                   strconv.Itoa(p.SshPort),
+	}
+}
+
+// ApplyTo replaces the username, credentials and SSH port of device with the ones of the profile
+func (p *DeviceConnectionProfile) ApplyTo(device *Device) {
+	device.Username = p.Username
+	device.Password = p.Password
+	device.KeyFile = ""
+	device.KeyFileContent = p.KeyFileContent
+	// the passphrase only applies to key based authentication
+	device.KeyPassphrase = ""
+	if p.KeyFileContent != "" {
+		device.KeyPassphrase = p.KeyPassphrase
+	}
+	if p.SshPort != 0 {
+		device.SshPort = p.SshPort
+		device.SshPortStr = strconv.Itoa(p.SshPort)
+	}
+}
+
+func (c *Client) CreateDeviceConnectionProfile(ctx context.Context, profile *DeviceConnectionProfile) error {
+	form := profile.form("add_cloudwan_device_connection_profile", c.cid())
+	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+}
+
+// GetDeviceConnectionProfile returns the connection profile called name, or ErrNotFound if it does not exist
+func (c *Client) GetDeviceConnectionProfile(ctx context.Context, name string) (*DeviceConnectionProfile, error) {
+	type Resp struct {
+		Return  bool                    `json:"return"`
+		Results DeviceConnectionProfile `json:"results"`
+		Reason  string                  `json:"reason"`
+	}
+	var data Resp
+	form := map[string]string{
+		"action":       "get_cloudwan_device_connection_profile",
+		"CID":          c.cid(),
+		"profile_name": name,
+	}
+	check := func(action, method, reason string, ret bool) error {
+		if !ret {
+			if strings.Contains(reason, "does not exist") {
+				return ErrNotFound
+			}
+			return newAPIError(action, method, reason)
+		}
+		return nil
+	}
+	err := c.GetAPIContext(ctx, &data, form["action"], form, check)
+	if err != nil {
+		return nil, err
+	}
+	data.Results.Name = name
+	return &data.Results, nil
+}
+
+func (c *Client) UpdateDeviceConnectionProfile(ctx context.Context, profile *DeviceConnectionProfile) error {
+	form := profile.form("edit_cloudwan_device_connection_profile", c.cid())
+	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+}
+
+func (c *Client) DeleteDeviceConnectionProfile(ctx context.Context, name string) error {
+	form := map[string]string{
+		"action":       "delete_cloudwan_device_connection_profile",
+		"CID":          c.cid(),
+		"profile_name": name,
+	}
+	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+}
@@ -0,0 +1,52 @@
+package goaviatrix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDeviceConnectionProfile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("profile_name") {
+		case "branches":
+			respondJSON(`{"return":true,"results":{"username":"ec2-user","private_key":"key","ssh_port":2222}}`)(w)
+		default:
+			respondJSON(`{"return":false,"reason":"Connection profile does not exist"}`)(w)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	profile, err := client.GetDeviceConnectionProfile(context.Background(), "branches")
+	if err != nil {
+		t.Fatalf("GetDeviceConnectionProfile() unexpected error: %v", err)
+	}
+	want := DeviceConnectionProfile{Name: "branches", Username: "ec2-user", KeyFileContent: "key", SshPort: 2222}
+	if *profile != want {
+		t.Errorf("GetDeviceConnectionProfile() got = %+v, want %+v", *profile, want)
+	}
+
+	if _, err := client.GetDeviceConnectionProfile(context.Background(), "missing"); err != ErrNotFound {
+		t.Errorf("GetDeviceConnectionProfile() of a missing profile error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestDeviceConnectionProfileApplyTo(t *testing.T) {
+	device := &Device{
+		Name:          "device-1",
+		Username:      "admin",
+		KeyFile:       "/path/to/key",
+		KeyPassphrase: "passphrase",
+		SshPort:       22,
+		SshPortStr:    "22",
+	}
+	profile := &DeviceConnectionProfile{Username: "ec2-user", Password: "secret", KeyPassphrase: "unused", SshPort: 2222}
+	profile.ApplyTo(device)
+
+	want := Device{Name: "device-1", Username: "ec2-user", Password: "secret", SshPort: 2222, SshPortStr: "2222"}
+	if *device != want {
+		t.Errorf("ApplyTo() got = %+v, want %+v", *device, want)
+	}
+}
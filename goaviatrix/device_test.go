@@ -0,0 +1,857 @@
+package goaviatrix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetDeviceCached(t *testing.T) {
+	listCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.FormValue("action") {
+		case "list_cloudwan_devices_summary":
+			listCalls++
+			respondJSON(`{"return":true,"results":[` +
+				`{"rgw_name":"device-1","hostname":"1.1.1.1","address":{"city":"Santa Clara"}},` +
+				`{"rgw_name":"device-2","hostname":"2.2.2.2"}]}`)(w)
+		default:
+			respondJSON(`{"return":true}`)(w)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	for _, name := range []string{"device-1", "device-2", "device-1"} {
+		if _, err := client.GetDeviceCached(context.Background(), &Device{Name: name}); err != nil {
+			t.Fatalf("GetDeviceCached(%q) unexpected error: %v", name, err)
+		}
+	}
+	if listCalls != 1 {
+		t.Errorf("GetDeviceCached() listed devices %d times, want 1", listCalls)
+	}
+
+	device, err := client.GetDeviceCached(context.Background(), &Device{Name: "device-1"})
+	if err != nil {
+		t.Fatalf("GetDeviceCached() unexpected error: %v", err)
+	}
+	if device.PublicIP != "1.1.1.1" || device.City != "Santa Clara" {
+		t.Errorf("GetDeviceCached() got = %+v, want public IP 1.1.1.1 and city Santa Clara", device)
+	}
+
+	if _, err := client.GetDeviceCached(context.Background(), &Device{Name: "device-3"}); err != ErrNotFound {
+		t.Errorf("GetDeviceCached() for unknown device got error %v, want ErrNotFound", err)
+	}
+
+	if err := client.DeregisterDevice(context.Background(), &Device{Name: "device-2"}); err != nil {
+		t.Fatalf("DeregisterDevice() unexpected error: %v", err)
+	}
+	if _, err := client.GetDeviceCached(context.Background(), &Device{Name: "device-1"}); err != nil {
+		t.Fatalf("GetDeviceCached() unexpected error: %v", err)
+	}
+	if listCalls != 2 {
+		t.Errorf("GetDeviceCached() after DeregisterDevice listed devices %d times in total, want 2", listCalls)
+	}
+}
+
+func TestRegisterDeviceErrorReason(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name       string
+		response   func(w http.ResponseWriter)
+		wantReason string
+	}{
+		{
+			"controller reason",
+			respondJSON(`{"return":false,"reason":"device name already exists"}`),
+			"device name already exists",
+		},
+		{
+			"reason in server error",
+			func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"return":false,"reason":"internal error while connecting to device"}`)
+			},
+			"internal error while connecting to device",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.response(w)
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).RegisterDevice(context.Background(), &Device{Name: "test-device"})
+			if err == nil {
+				t.Fatalf("RegisterDevice() expected an error, got none")
+			}
+			if !strings.Contains(err.Error(), tt.wantReason) {
+				t.Errorf("RegisterDevice() error = %q, want it to contain %q", err.Error(), tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRegisterDeviceContextCanceled(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := newTestClient(srv).RegisterDevice(ctx, &Device{Name: "test-device"}); err == nil {
+		t.Fatalf("RegisterDevice() with canceled context expected an error, got none")
+	}
+	if calls != 0 {
+		t.Errorf("RegisterDevice() with canceled context made %d calls, want 0", calls)
+	}
+}
+
+func TestRegisterDeviceBastion(t *testing.T) {
+	tests := []struct {
+		name   string
+		device *Device
+		want   map[string]string
+	}{
+		{
+			"bastion",
+			&Device{Name: "test-device", BastionIP: "10.0.0.1", BastionUsername: "ec2-user", BastionPort: 2222},
+			map[string]string{"bastion_ip": "10.0.0.1", "bastion_username": "ec2-user", "bastion_port": "2222"},
+		},
+		{
+			"no bastion",
+			&Device{Name: "test-device"},
+			map[string]string{"bastion_ip": "", "bastion_username": "", "bastion_port": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key := range tt.want {
+					got[key] = r.FormValue(key)
+				}
+				respondJSON(`{"return":true}`)(w)
+			}))
+			defer srv.Close()
+
+			if err := newTestClient(srv).RegisterDevice(context.Background(), tt.device); err != nil {
+				t.Fatalf("RegisterDevice() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RegisterDevice() sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeviceMtu(t *testing.T) {
+	tests := []struct {
+		name   string
+		device *Device
+		want   string
+	}{
+		{"mtu", &Device{Name: "test-device", Mtu: 1400}, "1400"},
+		{"controller default", &Device{Name: "test-device"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.FormValue("mtu"))
+				respondJSON(`{"return":true}`)(w)
+			}))
+			defer srv.Close()
+
+			client := newTestClient(srv)
+			if err := client.RegisterDevice(context.Background(), tt.device); err != nil {
+				t.Fatalf("RegisterDevice() unexpected error: %v", err)
+			}
+			if err := client.UpdateDevice(context.Background(), tt.device); err != nil {
+				t.Fatalf("UpdateDevice() unexpected error: %v", err)
+			}
+			if want := []string{tt.want, tt.want}; !reflect.DeepEqual(got, want) {
+				t.Errorf("RegisterDevice() and UpdateDevice() sent mtu %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDevicePublicIPHa(t *testing.T) {
+	tests := []struct {
+		name   string
+		device *Device
+		want   string
+	}{
+		{"ha pair", &Device{Name: "test-device", PublicIP: "1.2.3.4", PublicIPHa: "1.2.3.5"}, "1.2.3.5"},
+		{"single device", &Device{Name: "test-device", PublicIP: "1.2.3.4"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.FormValue("public_ip_ha"))
+				respondJSON(`{"return":true}`)(w)
+			}))
+			defer srv.Close()
+
+			client := newTestClient(srv)
+			if err := client.RegisterDevice(context.Background(), tt.device); err != nil {
+				t.Fatalf("RegisterDevice() unexpected error: %v", err)
+			}
+			if err := client.UpdateDevice(context.Background(), tt.device); err != nil {
+				t.Fatalf("UpdateDevice() unexpected error: %v", err)
+			}
+			if want := []string{tt.want, tt.want}; !reflect.DeepEqual(got, want) {
+				t.Errorf("RegisterDevice() and UpdateDevice() sent public_ip_ha %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGetDeviceRegistrationMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(`{"return":true,"results":[` +
+			`{"rgw_name":"device-1","created_at":"2021-06-01 10:00:00","registered_by":"admin",` +
+			`"host_key_fingerprint":"SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8",` +
+			`"model":"CloudN-1000","serial_number":"CN1000-0042","managed_by":"ui",` +
+			`"region":"us-east-1","zone":"us-east-1a","mtu":1400},` +
+			`{"rgw_name":"device-2"}]}`)(w)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name             string
+		wantCreatedAt    string
+		wantRegisteredBy string
+		wantFingerprint  string
+		wantModel        string
+		wantSerialNumber string
+		wantManagedBy    string
+		wantRegion       string
+		wantZone         string
+		wantMtu          int
+	}{
+		{"device-1", "2021-06-01 10:00:00", "admin", "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8", "CloudN-1000", "CN1000-0042", "ui", "us-east-1", "us-east-1a", 1400},
+		{"device-2", "", "", "", "", "", "", "", "", 0},
+	}
+	client := newTestClient(srv)
+	for _, tt := range tests {
+		device, err := client.GetDevice(context.Background(), &Device{Name: tt.name})
+		if err != nil {
+			t.Fatalf("GetDevice(%q) unexpected error: %v", tt.name, err)
+		}
+		if device.CreatedAt != tt.wantCreatedAt || device.RegisteredBy != tt.wantRegisteredBy {
+			t.Errorf("GetDevice(%q) got created_at %q and registered_by %q, want %q and %q",
+				tt.name, device.CreatedAt, device.RegisteredBy, tt.wantCreatedAt, tt.wantRegisteredBy)
+		}
+		if device.HostKeyFingerprint != tt.wantFingerprint {
+			t.Errorf("GetDevice(%q) got host_key_fingerprint %q, want %q", tt.name, device.HostKeyFingerprint, tt.wantFingerprint)
+		}
+		if device.Model != tt.wantModel || device.SerialNumber != tt.wantSerialNumber {
+			t.Errorf("GetDevice(%q) got model %q and serial_number %q, want %q and %q",
+				tt.name, device.Model, device.SerialNumber, tt.wantModel, tt.wantSerialNumber)
+		}
+		if device.ManagedBy != tt.wantManagedBy {
+			t.Errorf("GetDevice(%q) got managed_by %q, want %q", tt.name, device.ManagedBy, tt.wantManagedBy)
+		}
+		if device.Region != tt.wantRegion || device.Zone != tt.wantZone {
+			t.Errorf("GetDevice(%q) got region %q and zone %q, want %q and %q",
+				tt.name, device.Region, device.Zone, tt.wantRegion, tt.wantZone)
+		}
+		if device.Mtu != tt.wantMtu {
+			t.Errorf("GetDevice(%q) got mtu %d, want %d", tt.name, device.Mtu, tt.wantMtu)
+		}
+	}
+}
+
+func TestGetDeviceAttachments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(`{"return":true,"results":[` +
+			`{"rgw_name":"device-1","conn_name":"conn-1,conn-2"},` +
+			`{"rgw_name":"device-2","conn_name":""}]}`)(w)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr error
+	}{
+		{"device-1", []string{"conn-1", "conn-2"}, nil},
+		{"device-2", nil, nil},
+		{"device-3", nil, ErrNotFound},
+	}
+	client := newTestClient(srv)
+	for _, tt := range tests {
+		got, err := client.GetDeviceAttachments(context.Background(), tt.name)
+		if err != tt.wantErr {
+			t.Fatalf("GetDeviceAttachments(%q) error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetDeviceAttachments(%q) got = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetachDeviceConnections(t *testing.T) {
+	var detached []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("action") {
+		case "list_cloudwan_devices_summary":
+			respondJSON(`{"return":true,"results":[{"rgw_name":"device-1","conn_name":"conn-1, conn-2"}]}`)(w)
+		case "list_cloudwan_attachments":
+			respondJSON(`{"return":true,"results":[{"name":"conn-1","vpc_id":"vpc-1"},{"name":"conn-2","vpc_id":"vpc-2"}]}`)(w)
+		case "detach_cloudwan_device":
+			detached = append(detached, r.FormValue("connection_name"))
+			respondJSON(`{"return":true}`)(w)
+		default:
+			t.Errorf("unexpected action %q", r.FormValue("action"))
+			respondJSON(`{"return":false,"reason":"unexpected action"}`)(w)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	if err := client.DetachDeviceConnections(context.Background(), "device-1"); err != nil {
+		t.Fatalf("DetachDeviceConnections() unexpected error: %v", err)
+	}
+	if want := []string{"conn-1", "conn-2"}; !reflect.DeepEqual(detached, want) {
+		t.Errorf("DetachDeviceConnections() detached %v, want %v", detached, want)
+	}
+
+	detached = nil
+	if err := client.DetachDeviceConnections(context.Background(), "device-2"); err != nil {
+		t.Fatalf("DetachDeviceConnections() of a missing device unexpected error: %v", err)
+	}
+	if len(detached) != 0 {
+		t.Errorf("DetachDeviceConnections() of a missing device detached %v", detached)
+	}
+}
+
+func TestFindDeviceAccountName(t *testing.T) {
+	devices := []*Device{
+		{Name: "device-1", AccountName: "tenant-a"},
+		{Name: "device-2"},
+	}
+	tests := []struct {
+		name        string
+		deviceName  string
+		accountName string
+		wantErr     error
+	}{
+		{"any account", "device-1", "", nil},
+		{"matching account", "device-1", "tenant-a", nil},
+		{"other account", "device-1", "tenant-b", ErrNotFound},
+		{"account not reported", "device-2", "tenant-b", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, err := findDevice(devices, tt.deviceName, tt.accountName)
+			if err != tt.wantErr {
+				t.Fatalf("findDevice() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && device.Name != tt.deviceName {
+				t.Errorf("findDevice() got device %s, want %s", device.Name, tt.deviceName)
+			}
+		})
+	}
+}
+
+func TestFindDeviceCaseInsensitive(t *testing.T) {
+	devices := []*Device{
+		{Name: "mydevice", PublicIP: "1.1.1.1"},
+		{Name: "other", PublicIP: "2.2.2.2"},
+		{Name: "Other", PublicIP: "3.3.3.3"},
+	}
+	tests := []struct {
+		name       string
+		deviceName string
+		wantIP     string
+		wantErr    error
+	}{
+		{"exact name", "mydevice", "1.1.1.1", nil},
+		{"name in other case", "MyDevice", "1.1.1.1", nil},
+		{"exact name preferred", "Other", "3.3.3.3", nil},
+		{"missing", "device", "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, err := findDevice(devices, tt.deviceName, "")
+			if err != tt.wantErr {
+				t.Fatalf("findDevice() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && device.PublicIP != tt.wantIP {
+				t.Errorf("findDevice() got device with public IP %s, want %s", device.PublicIP, tt.wantIP)
+			}
+		})
+	}
+}
+
+func TestWaitForDevice(t *testing.T) {
+	deviceVisibleBackoff = time.Millisecond
+	defer func() { deviceVisibleBackoff = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name          string
+		notFoundCalls int
+		timeout       time.Duration
+		wantErr       bool
+		wantCalls     int
+	}{
+		{"found immediately", 0, time.Second, false, 1},
+		{"found after not found", 2, time.Second, false, 3},
+		{"never found", 1000, 20 * time.Millisecond, true, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= tt.notFoundCalls {
+					respondJSON(`{"return":true,"results":[]}`)(w)
+					return
+				}
+				respondJSON(`{"return":true,"results":[{"rgw_name":"device-1"}]}`)(w)
+			}))
+			defer srv.Close()
+
+			device, err := newTestClient(srv).WaitForDevice(context.Background(), &Device{Name: "device-1"}, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitForDevice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && device.Name != "device-1" {
+				t.Errorf("WaitForDevice() got device %+v, want device-1", device)
+			}
+			if tt.wantCalls >= 0 && calls != tt.wantCalls {
+				t.Errorf("WaitForDevice() listed devices %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWaitForDeviceConnected(t *testing.T) {
+	deviceConnectedPollInterval = time.Millisecond
+	defer func() { deviceConnectedPollInterval = 15 * time.Second }()
+
+	tests := []struct {
+		name      string
+		responses []string
+		timeout   time.Duration
+		wantErr   bool
+	}{
+		{
+			"connected after reboot",
+			[]string{
+				`{"return":true,"results":{"status":"disconnected"}}`,
+				`{"return":false,"reason":"device is not responding"}`,
+				`{"return":true,"results":{"status":"Connected"}}`,
+			},
+			time.Second,
+			false,
+		},
+		{
+			"never connected",
+			[]string{`{"return":true,"results":{"status":"disconnected"}}`},
+			20 * time.Millisecond,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := tt.responses[len(tt.responses)-1]
+				if calls < len(tt.responses) {
+					response = tt.responses[calls]
+				}
+				calls++
+				respondJSON(response)(w)
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).WaitForDeviceConnected(context.Background(), "device-1", tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitForDeviceConnected() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && calls != len(tt.responses) {
+				t.Errorf("WaitForDeviceConnected() checked the status %d times, want %d", calls, len(tt.responses))
+			}
+		})
+	}
+}
+
+func TestGetDevicesHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		listStatus string
+		want       []DeviceConnectionStatus
+	}{
+		{
+			"batched",
+			`{"return":true,"results":[` +
+				`{"device_name":"Device-1","status":"Connected","last_seen":"2026-10-16 09:00:00"},` +
+				`{"device_name":"device-2","status":"disconnected","last_seen":"2026-10-15 18:00:00"}]}`,
+			[]DeviceConnectionStatus{
+				{"device-1", "connected", "2026-10-16 09:00:00"},
+				{"device-2", "disconnected", "2026-10-15 18:00:00"},
+				{"device-3", "unknown", ""},
+			},
+		},
+		{
+			"batch not supported",
+			`{"return":false,"reason":"unknown action"}`,
+			[]DeviceConnectionStatus{
+				{"device-1", "connected", ""},
+				{"device-2", "connected", ""},
+				{"device-3", "unknown", ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				switch {
+				case r.FormValue("action") == "list_cloudwan_device_connection_status":
+					respondJSON(tt.listStatus)(w)
+				case r.FormValue("device_name") == "device-3":
+					respondJSON(`{"return":false,"reason":"device is not responding"}`)(w)
+				default:
+					respondJSON(`{"return":true,"results":{"status":"connected"}}`)(w)
+				}
+			}))
+			defer srv.Close()
+
+			devices := []*Device{{Name: "device-1"}, {Name: "device-2"}, {Name: "device-3"}}
+			var got []DeviceConnectionStatus
+			for _, status := range newTestClient(srv).GetDevicesHealth(context.Background(), devices) {
+				got = append(got, *status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDevicesHealth() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  error
+	}{
+		{"renamed", `{"return":true,"results":"device renamed"}`, nil},
+		{"not supported", `{"return":false,"reason":"Valid action required: rename_cloudwan_device"}`, ErrDeviceRenameNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldName, newName string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				oldName, newName = r.FormValue("device_name"), r.FormValue("new_device_name")
+				respondJSON(tt.response)(w)
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).RenameDevice(context.Background(), "device-1", "device-2")
+			if err != tt.wantErr {
+				t.Fatalf("RenameDevice() error = %v, want %v", err, tt.wantErr)
+			}
+			if oldName != "device-1" || newName != "device-2" {
+				t.Errorf("RenameDevice() sent %q to %q, want device-1 to device-2", oldName, newName)
+			}
+		})
+	}
+}
+
+func TestUpdateDeviceMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  error
+	}{
+		{"updated", `{"return":true,"results":"device updated"}`, nil},
+		{"not supported", `{"return":false,"reason":"Valid action required: update_cloudwan_device_metadata"}`, ErrDeviceMetadataUpdateNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				form = r.Form
+				respondJSON(tt.response)(w)
+			}))
+			defer srv.Close()
+
+			device := &Device{
+				Name:        "device-1",
+				PublicIP:    "1.2.3.4",
+				Username:    "ec2-user",
+				Password:    "secret",
+				SshPortStr:  "2222",
+				City:        "Santa Clara",
+				Description: "branch office",
+			}
+			err := newTestClient(srv).UpdateDeviceMetadata(context.Background(), device)
+			if err != tt.wantErr {
+				t.Fatalf("UpdateDeviceMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+			if form.Get("device_name") != "device-1" || form.Get("city") != "Santa Clara" || form.Get("description") != "branch office" {
+				t.Errorf("UpdateDeviceMetadata() sent %v, want the name, city and description of the device", form)
+			}
+			for _, key := range []string{"public_ip", "username", "password", "port"} {
+				if _, ok := form[key]; ok {
+					t.Errorf("UpdateDeviceMetadata() sent %s, want only metadata", key)
+				}
+			}
+		})
+	}
+}
+
+func TestRebootDevice(t *testing.T) {
+	var action, deviceName string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		action, deviceName = r.FormValue("action"), r.FormValue("device_name")
+		respondJSON(`{"return":true,"results":"rebooting device-1"}`)(w)
+	}))
+	defer srv.Close()
+
+	if err := newTestClient(srv).RebootDevice(context.Background(), "device-1"); err != nil {
+		t.Fatalf("RebootDevice() unexpected error: %v", err)
+	}
+	if action != "reboot_cloudwan_device" || deviceName != "device-1" {
+		t.Errorf("RebootDevice() sent action %q for device %q, want reboot_cloudwan_device for device-1", action, deviceName)
+	}
+}
+
+func TestCheckDeviceReachable(t *testing.T) {
+	tests := []struct {
+		name     string
+		response func(w http.ResponseWriter)
+		wantErr  bool
+	}{
+		{
+			"reachable",
+			respondJSON(`{"return":true,"results":{"reachable":true}}`),
+			false,
+		},
+		{
+			"not reachable",
+			respondJSON(`{"return":true,"results":{"reachable":false}}`),
+			true,
+		},
+		{
+			"check failed",
+			respondJSON(`{"return":false,"reason":"connection timed out"}`),
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				tt.response(w)
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).CheckDeviceReachable(context.Background(), "10.0.0.1", 2222)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckDeviceReachable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "device 10.0.0.1:2222 not reachable") {
+				t.Errorf("CheckDeviceReachable() error = %q, want it to name the device address", err.Error())
+			}
+			if !strings.Contains(query, "ssh_port=2222") {
+				t.Errorf("CheckDeviceReachable() query = %q, want ssh_port=2222", query)
+			}
+		})
+	}
+}
+
+func TestGetDeviceByIP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(`{"return":true,"results":[` +
+			`{"rgw_name":"device-1","hostname":"1.1.1.1"},` +
+			`{"rgw_name":"device-2","hostname":"2.2.2.2"},` +
+			`{"rgw_name":"device-3","hostname":"2.2.2.2"}]}`)(w)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		publicIP string
+		want     string
+		wantErr  bool
+	}{
+		{"found", "1.1.1.1", "device-1", false},
+		{"not found", "3.3.3.3", "", true},
+		{"multiple devices", "2.2.2.2", "", true},
+	}
+	client := newTestClient(srv)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, err := client.GetDeviceByIP(context.Background(), tt.publicIP)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDeviceByIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && device.Name != tt.want {
+				t.Errorf("GetDeviceByIP() got device %q, want %q", device.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshDeviceSoftwareVersion(t *testing.T) {
+	listCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "list_cloudwan_devices_summary":
+			listCalls++
+			respondJSON(`{"return":true,"results":[{"rgw_name":"device-1","software_version":"6.5.100"}]}`)(w)
+		case "refresh_cloudwan_device_software_version":
+			if name := r.URL.Query().Get("device_name"); name != "device-1" {
+				t.Errorf("RefreshDeviceSoftwareVersion() sent device_name %q, want %q", name, "device-1")
+			}
+			respondJSON(`{"return":true,"results":{"software_version":"6.5.200"}}`)(w)
+		default:
+			respondJSON(`{"return":true}`)(w)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	if _, err := client.GetDeviceCached(context.Background(), &Device{Name: "device-1"}); err != nil {
+		t.Fatalf("GetDeviceCached() unexpected error: %v", err)
+	}
+	version, err := client.RefreshDeviceSoftwareVersion(context.Background(), "device-1")
+	if err != nil {
+		t.Fatalf("RefreshDeviceSoftwareVersion() unexpected error: %v", err)
+	}
+	if version != "6.5.200" {
+		t.Errorf("RefreshDeviceSoftwareVersion() got = %q, want %q", version, "6.5.200")
+	}
+
+	// the cached device list recorded the old version and must be listed again
+	if _, err := client.GetDeviceCached(context.Background(), &Device{Name: "device-1"}); err != nil {
+		t.Fatalf("GetDeviceCached() unexpected error: %v", err)
+	}
+	if listCalls != 2 {
+		t.Errorf("GetDeviceCached() after RefreshDeviceSoftwareVersion listed devices %d times in total, want 2", listCalls)
+	}
+}
+
+func TestDeregisterDevicesBulk(t *testing.T) {
+	tests := []struct {
+		name         string
+		bulkResponse string
+		failDevice   string
+		wantActions  []string
+		wantFailed   []string
+	}{
+		{
+			"bulk supported",
+			`{"return":true}`,
+			"",
+			[]string{"deregister_cloudwan_devices_bulk"},
+			nil,
+		},
+		{
+			"bulk failed",
+			`{"return":false,"reason":"device-2 has attachments"}`,
+			"",
+			[]string{"deregister_cloudwan_devices_bulk", "list_cloudwan_devices_summary"},
+			[]string{"Device-2"},
+		},
+		{
+			"fallback to single calls",
+			`{"return":false,"reason":"Valid action required: deregister_cloudwan_devices_bulk"}`,
+			"",
+			[]string{"deregister_cloudwan_devices_bulk", "deregister_cloudwan_device", "deregister_cloudwan_device"},
+			nil,
+		},
+		{
+			"fallback with partial failure",
+			`{"return":false,"reason":"Valid action required: deregister_cloudwan_devices_bulk"}`,
+			"Device-2",
+			[]string{"deregister_cloudwan_devices_bulk", "deregister_cloudwan_device", "deregister_cloudwan_device"},
+			[]string{"Device-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actions []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				action := r.FormValue("action")
+				actions = append(actions, action)
+				switch {
+				case action == "deregister_cloudwan_devices_bulk":
+					if got := r.FormValue("device_name_list"); got != "device-1,Device-2" {
+						t.Errorf("device_name_list = %q, want %q", got, "device-1,Device-2")
+					}
+					respondJSON(tt.bulkResponse)(w)
+				case action == "list_cloudwan_devices_summary":
+					respondJSON(`{"return":true,"results":[{"rgw_name":"device-2"},{"rgw_name":"device-3"}]}`)(w)
+				case r.FormValue("device_name") == tt.failDevice:
+					respondJSON(`{"return":false,"reason":"device has attachments"}`)(w)
+				default:
+					respondJSON(`{"return":true}`)(w)
+				}
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).DeregisterDevicesBulk(context.Background(), []string{"device-1", "Device-2"})
+			if !reflect.DeepEqual(actions, tt.wantActions) {
+				t.Errorf("DeregisterDevicesBulk() actions = %v, want %v", actions, tt.wantActions)
+			}
+			if tt.wantFailed == nil {
+				if err != nil {
+					t.Errorf("DeregisterDevicesBulk() unexpected error: %v", err)
+				}
+				return
+			}
+			bulkErr, ok := err.(*BulkDeviceError)
+			if !ok {
+				t.Fatalf("DeregisterDevicesBulk() error = %v, want a *BulkDeviceError", err)
+			}
+			if got := bulkErr.FailedDeviceNames(); !reflect.DeepEqual(got, tt.wantFailed) {
+				t.Errorf("DeregisterDevicesBulk() failed devices = %v, want %v", got, tt.wantFailed)
+			}
+		})
+	}
+}
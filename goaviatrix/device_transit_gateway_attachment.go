@@ -32,7 +32,7 @@ type DeviceTransitGatewayAttachment struct {
 
 func (c *Client) CreateDeviceTransitGatewayAttachment(attachment *DeviceTransitGatewayAttachment) error {
 	attachment.Action = "attach_cloudwan_device_to_transit_gateway"
-	attachment.CID = c.CID
+	attachment.CID = c.cid()
 	return c.PostAPI(attachment.Action, attachment, BasicCheck)
 }
 
@@ -54,7 +54,7 @@ func (c *Client) GetDeviceTransitGatewayAttachment(attachment *DeviceTransitGate
 	}
 
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "get_site2cloud_conn_detail",
 		"vpc_id":    vpcID,
 		"conn_name": attachment.ConnectionName,
@@ -100,7 +100,7 @@ func (c *Client) GetDeviceTransitGatewayAttachment(attachment *DeviceTransitGate
 
 func (c *Client) GetDeviceAttachmentVpcID(connectionName string) (string, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_cloudwan_attachments",
 	}
 
@@ -138,7 +138,7 @@ func (c *Client) DeleteDeviceAttachment(connectionName string) error {
 	}
 
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "detach_cloudwan_device",
 		"vpc_id":          vpcID,
 		"connection_name": connectionName,
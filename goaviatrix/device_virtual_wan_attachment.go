@@ -18,7 +18,7 @@ type DeviceVirtualWanAttachment struct {
 
 func (c *Client) CreateDeviceVirtualWanAttachment(attachment *DeviceVirtualWanAttachment) error {
 	attachment.Action = "attach_cloudwan_device_to_virtual_wan"
-	attachment.CID = c.CID
+	attachment.CID = c.cid()
 	return c.PostAPI(attachment.Action, attachment, BasicCheck)
 }
 
@@ -40,7 +40,7 @@ func (c *Client) GetDeviceVirtualWanAttachment(attachment *DeviceVirtualWanAttac
 	}
 
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "get_site2cloud_conn_detail",
 		"vpc_id":    vpcID,
 		"conn_name": attachment.ConnectionName,
@@ -0,0 +1,116 @@
+package goaviatrix
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrAuth is the category of errors caused by the controller rejecting the credentials or
+// permissions of the request. Check for it with errors.Is.
+var ErrAuth = errors.New("ErrAuth")
+
+// ErrValidation is the category of errors caused by the controller rejecting the parameters of the
+// request. Retrying such a request unchanged fails again. Check for it with errors.Is.
+var ErrValidation = errors.New("ErrValidation")
+
+// ErrConflict is the category of errors caused by the controller rejecting an update because the
+// updated object was changed concurrently. Reading the object again and retrying may succeed.
+var ErrConflict = errors.New("ErrConflict")
+
+// authReasons are lowercased parts of the reasons the controller gives for rejecting credentials or permissions
+var authReasons = []string{
+	"cid is invalid",
+	"invalid session",
+	"not authorized",
+	"unauthorized",
+	"permission denied",
+	"access denied",
+	"authentication failed",
+	"invalid username or password",
+}
+
+// conflictReasons are lowercased parts of the reasons the controller gives for rejecting concurrent updates
+var conflictReasons = []string{
+	"version mismatch",
+	"conflict",
+	"modified by another",
+}
+
+// validationReasons are lowercased parts of the reasons the controller gives for rejecting parameters
+var validationReasons = []string{
+	"invalid",
+	"is required",
+	"must be",
+	"missing",
+	"not a valid",
+	"not valid",
+}
+
+// APIError is returned when the controller rejects an API call. It wraps ErrAuth, ErrValidation or
+// ErrConflict if the rejection falls into one of these categories.
+type APIError struct {
+	Action   string
+	Method   string
+	Reason   string
+	Category error // ErrAuth, ErrValidation, ErrConflict or nil
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("rest API %s %s failed: %s", e.Action, e.Method, e.Reason)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Category
+}
+
+// newAPIError returns the APIError for a call the controller rejected with reason
+func newAPIError(action, method, reason string) *APIError {
+	return &APIError{
+		Action:   action,
+		Method:   method,
+		Reason:   reason,
+		Category: categorizeReason(reason),
+	}
+}
+
+// categorizeReason returns the error category of a reason given by the controller, or nil if it
+// does not fall into a known category
+func categorizeReason(reason string) error {
+	reason = strings.ToLower(reason)
+	// check authentication first, e.g. "CID is invalid" is not a validation error
+	for _, part := range authReasons {
+		if strings.Contains(reason, part) {
+			return ErrAuth
+		}
+	}
+	for _, part := range conflictReasons {
+		if strings.Contains(reason, part) {
+			return ErrConflict
+		}
+	}
+	for _, part := range validationReasons {
+		if strings.Contains(reason, part) {
+			return ErrValidation
+		}
+	}
+	return nil
+}
+
+// categorizeStatus sets the category of an APIError without one from the HTTP status of the response
+func categorizeStatus(err error, resp *http.Response) error {
+	var apiErr *APIError
+	if resp == nil || !errors.As(err, &apiErr) || apiErr.Category != nil {
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		apiErr.Category = ErrAuth
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		apiErr.Category = ErrValidation
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		apiErr.Category = ErrConflict
+	}
+	return err
+}
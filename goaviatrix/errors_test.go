@@ -0,0 +1,78 @@
+package goaviatrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCategorizeReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   error
+	}{
+		{"CID is invalid or expired.", ErrAuth},
+		{"Permission denied for this account", ErrAuth},
+		{"Invalid public IP address 1.2.3", ErrValidation},
+		{"Parameter ssh_port must be between 1 and 65535", ErrValidation},
+		{"Tag version mismatch, tags were modified by another request", ErrConflict},
+		{"Device device-1 is busy", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			if got := categorizeReason(tt.reason); got != tt.want {
+				t.Errorf("categorizeReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorCategory(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		reason string
+		want   error
+	}{
+		{"auth reason", http.StatusOK, "Permission denied for this account", ErrAuth},
+		{"validation reason", http.StatusOK, "Invalid public IP address", ErrValidation},
+		{"auth status", http.StatusForbidden, "Request rejected", ErrAuth},
+		{"validation status", http.StatusBadRequest, "Request rejected", ErrValidation},
+		{"conflict status", http.StatusConflict, "Request rejected", ErrConflict},
+		{"reason over status", http.StatusBadRequest, "not authorized", ErrAuth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				fmt.Fprintf(w, `{"return":false,"reason":%q}`, tt.reason)
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).UpdateDevice(context.Background(), &Device{Name: "device-1"})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("UpdateDevice() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Reason != tt.reason {
+				t.Errorf("UpdateDevice() error = %v, want APIError with reason %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestAPIErrorWrappedByTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(`{"return":false,"reason":"Permission denied for this account"}`)(w)
+	}))
+	defer srv.Close()
+
+	err := newTestClient(srv).UpdateTags(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "gw-1", Tags: map[string]string{"key": "value"}})
+	if !errors.Is(err, ErrAuth) {
+		t.Errorf("UpdateTags() error = %v, want %v", err, ErrAuth)
+	}
+}
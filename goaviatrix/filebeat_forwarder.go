@@ -23,7 +23,7 @@ type FilebeatForwarderResp struct {
 func (c *Client) EnableFilebeatForwarder(r *FilebeatForwarder) error {
 	params := map[string]string{
 		"action":               "enable_logstash_logging",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"server_ip":            r.Server,
 		"port":                 strconv.Itoa(r.Port),
 		"exclude_gateway_list": r.ExcludedGatewaysInput,
@@ -59,7 +59,7 @@ func (c *Client) EnableFilebeatForwarder(r *FilebeatForwarder) error {
 func (c *Client) GetFilebeatForwarderStatus() (*FilebeatForwarderResp, error) {
 	params := map[string]string{
 		"action": "get_logstash_logging_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -85,7 +85,7 @@ func (c *Client) GetFilebeatForwarderStatus() (*FilebeatForwarderResp, error) {
 func (c *Client) DisableFilebeatForwarder() error {
 	params := map[string]string{
 		"action": "disable_logstash_logging",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	return c.PostAPI(params["action"], params, BasicCheck)
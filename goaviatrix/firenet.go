@@ -77,7 +77,7 @@ func (c *Client) CreateFireNet(fireNet *FireNet) error {
 
 func (c *Client) GetFireNet(fireNet *FireNet) (*FireNetDetail, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "show_firenet_detail",
 		"vpc_id": fireNet.VpcID,
 	}
@@ -108,7 +108,7 @@ func (c *Client) GetFireNet(fireNet *FireNet) (*FireNetDetail, error) {
 
 func (c *Client) AssociateFirewallWithFireNet(firewallInstance *FirewallInstance) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "associate_firewall_with_firenet",
 		"vpc_id":       firewallInstance.VpcID,
 		"gateway_name": firewallInstance.GwName,
@@ -139,7 +139,7 @@ func (c *Client) AssociateFirewallWithFireNet(firewallInstance *FirewallInstance
 
 func (c *Client) DisassociateFirewallFromFireNet(firewallInstance *FirewallInstance) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "disassociate_firewall_with_firenet",
 		"vpc_id":      firewallInstance.VpcID,
 		"firewall_id": firewallInstance.InstanceID,
@@ -160,7 +160,7 @@ func (c *Client) DisassociateFirewallFromFireNet(firewallInstance *FirewallInsta
 
 func (c *Client) AttachFirewallToFireNet(firewallInstance *FirewallInstance) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "attach_firewall_to_firenet",
 		"vpc_id":      firewallInstance.VpcID,
 		"firewall_id": firewallInstance.InstanceID,
@@ -171,7 +171,7 @@ func (c *Client) AttachFirewallToFireNet(firewallInstance *FirewallInstance) err
 
 func (c *Client) DetachFirewallFromFireNet(firewallInstance *FirewallInstance) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "detach_firewall_from_firenet",
 		"vpc_id":      firewallInstance.VpcID,
 		"firewall_id": firewallInstance.InstanceID,
@@ -182,7 +182,7 @@ func (c *Client) DetachFirewallFromFireNet(firewallInstance *FirewallInstance) e
 
 func (c *Client) ConnectFireNetWithTgw(awsTgw *AWSTgw, vpcSolo VPCSolo, SecurityDomainName string) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "connect_firenet_with_tgw",
 		"vpc_id":      vpcSolo.VpcID,
 		"tgw_name":    awsTgw.Name,
@@ -194,7 +194,7 @@ func (c *Client) ConnectFireNetWithTgw(awsTgw *AWSTgw, vpcSolo VPCSolo, Security
 
 func (c *Client) DisconnectFireNetFromTgw(awsTgw *AWSTgw, vpcID string) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "disconnect_firenet_with_tgw",
 		"vpc_id": vpcID,
 	}
@@ -204,7 +204,7 @@ func (c *Client) DisconnectFireNetFromTgw(awsTgw *AWSTgw, vpcID string) error {
 
 func (c *Client) EditFireNetInspection(fireNet *FireNet) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "edit_firenet",
 		"vpc_id":     fireNet.VpcID,
 		"inspection": strconv.FormatBool(fireNet.Inspection),
@@ -225,7 +225,7 @@ func (c *Client) EditFireNetInspection(fireNet *FireNet) error {
 
 func (c *Client) EditFireNetEgress(fireNet *FireNet) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "edit_firenet",
 		"vpc_id":          fireNet.VpcID,
 		"firewall_egress": strconv.FormatBool(fireNet.FirewallEgress),
@@ -247,7 +247,7 @@ func (c *Client) EditFireNetEgress(fireNet *FireNet) error {
 func (c *Client) EditFireNetHashingAlgorithm(fireNet *FireNet) error {
 	data := map[string]string{
 		"action":           "edit_firenet",
-		"CID":              c.CID,
+		"CID":              c.cid(),
 		"vpc_id":           fireNet.VpcID,
 		"firewall_hashing": fireNet.HashingAlgorithm,
 	}
@@ -268,7 +268,7 @@ func (c *Client) EditFireNetHashingAlgorithm(fireNet *FireNet) error {
 func (c *Client) EnableFireNetLanKeepAlive(net *FireNet) error {
 	data := map[string]string{
 		"action":   "edit_firenet",
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"vpc_id":   net.VpcID,
 		"lan_ping": "true",
 	}
@@ -290,7 +290,7 @@ func (c *Client) EnableFireNetLanKeepAlive(net *FireNet) error {
 func (c *Client) DisableFireNetLanKeepAlive(net *FireNet) error {
 	data := map[string]string{
 		"action":   "edit_firenet",
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"vpc_id":   net.VpcID,
 		"lan_ping": "false",
 	}
@@ -312,7 +312,7 @@ func (c *Client) DisableFireNetLanKeepAlive(net *FireNet) error {
 func (c *Client) EnableTgwSegmentationForEgress(net *FireNet) error {
 	data := map[string]string{
 		"action": "enable_firenet_tgw_segmentation_for_egress",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"vpc_id": net.VpcID,
 	}
 
@@ -322,7 +322,7 @@ func (c *Client) EnableTgwSegmentationForEgress(net *FireNet) error {
 func (c *Client) DisableTgwSegmentationForEgress(net *FireNet) error {
 	data := map[string]string{
 		"action": "disable_firenet_tgw_segmentation_for_egress",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"vpc_id": net.VpcID,
 	}
 
@@ -332,7 +332,7 @@ func (c *Client) DisableTgwSegmentationForEgress(net *FireNet) error {
 func (c *Client) EditFirenetEgressStaticCidr(net *FireNet) error {
 	data := map[string]string{
 		"action":             "edit_firenet_egress_static_cidr",
-		"CID":                c.CID,
+		"CID":                c.cid(),
 		"vpc_id":             net.VpcID,
 		"egress_static_cidr": net.EgressStaticCidrs,
 	}
@@ -343,7 +343,7 @@ func (c *Client) EditFirenetEgressStaticCidr(net *FireNet) error {
 func (c *Client) EditFirenetExcludedCidr(net *FireNet) error {
 	form := map[string]string{
 		"action":       "edit_firenet_excluded_cidr",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"vpc_id":       net.VpcID,
 		"exclude_cidr": net.ExcludedCidrs,
 	}
@@ -362,7 +362,7 @@ func (c *Client) EditFirenetExcludedCidr(net *FireNet) error {
 func (c *Client) EnableFirenetFailClose(net *FireNet) error {
 	form := map[string]string{
 		"action": "enable_firenet_fail_close",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"vpc_id": net.VpcID,
 	}
 	check := func(act, method, reason string, ret bool) error {
@@ -380,7 +380,7 @@ func (c *Client) EnableFirenetFailClose(net *FireNet) error {
 func (c *Client) DisableFirenetFailClose(net *FireNet) error {
 	form := map[string]string{
 		"action": "disable_firenet_fail_close",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"vpc_id": net.VpcID,
 	}
 	check := func(act, method, reason string, ret bool) error {
@@ -37,7 +37,7 @@ type FirewallResp struct {
 
 func (c *Client) SetBasePolicy(firewall *Firewall) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "set_vpc_base_policy",
 		"vpc_name":               firewall.GwName,
 		"base_policy":            firewall.BasePolicy,
@@ -48,7 +48,7 @@ func (c *Client) SetBasePolicy(firewall *Firewall) error {
 }
 
 func (c *Client) UpdatePolicy(firewall *Firewall) error {
-	firewall.CID = c.CID
+	firewall.CID = c.cid()
 	firewall.Action = "update_access_policy"
 	// If the PolicyList is nil it will be encoded as 'null'.
 	// Instead, we want to set PolicyList to an empty slice so that it is encoded as '[]'.
@@ -66,7 +66,7 @@ func (c *Client) UpdatePolicy(firewall *Firewall) error {
 
 func (c *Client) GetPolicy(firewall *Firewall) (*Firewall, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "vpc_access_policy",
 		"vpc_name": firewall.GwName,
 	}
@@ -137,7 +137,7 @@ func (c *Client) AddFirewallPolicy(fw *Firewall) error {
 	}
 
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "append_stateful_firewall_rules",
 		"gateway_name": fw.GwName,
 		"rules":        string(rules),
@@ -153,7 +153,7 @@ func (c *Client) DeleteFirewallPolicy(fw *Firewall) error {
 	}
 
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "delete_stateful_firewall_rules",
 		"gateway_name": fw.GwName,
 		"rules":        string(rules),
@@ -83,7 +83,7 @@ func (c *Client) CreateFirewallInstance(firewallInstance *FirewallInstance) (str
 		return "", errors.New(("url Parsing failed for add_firewall_instance: ") + err.Error())
 	}
 	addFirewallInstance := url.Values{}
-	addFirewallInstance.Add("CID", c.CID)
+	addFirewallInstance.Add("CID", c.cid())
 	addFirewallInstance.Add("action", "add_firewall_instance")
 	if firewallInstance.GwName != "" {
 		addFirewallInstance.Add("gw_name", firewallInstance.GwName)
@@ -176,7 +176,7 @@ func (c *Client) CreateFirewallInstance(firewallInstance *FirewallInstance) (str
 
 func (c *Client) GetFirewallInstance(firewallInstance *FirewallInstance) (*FirewallInstance, error) {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "get_instance_by_id",
 		"instance_id": firewallInstance.InstanceID,
 	}
@@ -214,7 +214,7 @@ func (c *Client) GetFirewallInstance(firewallInstance *FirewallInstance) (*Firew
 
 func (c *Client) DeleteFirewallInstance(firewallInstance *FirewallInstance) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "delete_firenet_firewall_instance",
 		"vpc_id":      firewallInstance.VpcID,
 		"firewall_id": firewallInstance.InstanceID,
@@ -15,7 +15,7 @@ type FirewallManagementAccessAPIResp struct {
 
 func (c *Client) CreateFirewallManagementAccess(firewallManagementAccess *FirewallManagementAccess) error {
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "edit_transit_firenet_management_access",
 		"gateway_name":      firewallManagementAccess.TransitFireNetGatewayName,
 		"management_access": firewallManagementAccess.ManagementAccessResourceName,
@@ -26,7 +26,7 @@ func (c *Client) CreateFirewallManagementAccess(firewallManagementAccess *Firewa
 
 func (c *Client) GetFirewallManagementAccess(firewallManagementAccess *FirewallManagementAccess) (*FirewallManagementAccess, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_transit_firenet_spoke_policies",
 	}
 
@@ -56,7 +56,7 @@ func (c *Client) GetFirewallManagementAccess(firewallManagementAccess *FirewallM
 
 func (c *Client) DestroyFirewallManagementAccess(firewallManagementAccess *FirewallManagementAccess) error {
 	form := map[string]string{
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"action":            "edit_transit_firenet_management_access",
 		"gateway_name":      firewallManagementAccess.TransitFireNetGatewayName,
 		"management_access": firewallManagementAccess.ManagementAccessResourceName,
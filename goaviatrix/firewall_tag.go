@@ -31,7 +31,7 @@ type FirewallTagResp struct {
 }
 
 func (c *Client) CreateFirewallTag(firewall_tag *FirewallTag) error {
-	firewall_tag.CID = c.CID
+	firewall_tag.CID = c.cid()
 	firewall_tag.Action = "add_policy_tag"
 
 	return c.PostAPI(firewall_tag.Action, firewall_tag, BasicCheck)
@@ -39,10 +39,10 @@ func (c *Client) CreateFirewallTag(firewall_tag *FirewallTag) error {
 
 func (c *Client) UpdateFirewallTag(firewall_tag *FirewallTag) error {
 	// TODO: use PostAPI - tags need special processing
-	firewall_tag.CID = c.CID
+	firewall_tag.CID = c.cid()
 	firewall_tag.Action = "update_policy_members"
 	verb := "POST"
-	body := fmt.Sprintf("CID=%s&action=%s&tag_name=%s", c.CID, firewall_tag.Action, firewall_tag.Name)
+	body := fmt.Sprintf("CID=%s&action=%s&tag_name=%s", c.cid(), firewall_tag.Action, firewall_tag.Name)
 	for i, cidr := range firewall_tag.CIDRList {
 		body = body + fmt.Sprintf("&new_policies[%d][name]=%s&new_policies[%d][cidr]=%s", i, cidr.CIDRTag, i, cidr.CIDR)
 	}
@@ -74,7 +74,7 @@ func (c *Client) UpdateFirewallTag(firewall_tag *FirewallTag) error {
 
 func (c *Client) GetFirewallTag(firewall_tag *FirewallTag) (*FirewallTag, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_policy_members",
 		"tag_name": firewall_tag.Name,
 	}
@@ -97,7 +97,7 @@ func (c *Client) GetFirewallTag(firewall_tag *FirewallTag) (*FirewallTag, error)
 }
 
 func (c *Client) DeleteFirewallTag(firewall_tag *FirewallTag) error {
-	firewall_tag.CID = c.CID
+	firewall_tag.CID = c.cid()
 	firewall_tag.Action = "del_policy_tag"
 
 	return c.PostAPI(firewall_tag.Action, firewall_tag, BasicCheck)
@@ -70,7 +70,7 @@ type FQDNPassThrough struct {
 
 func (c *Client) CreateFQDN(fqdn *FQDN) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "add_fqdn_filter_tag",
 		"tag_name": fqdn.FQDNTag,
 	}
@@ -80,7 +80,7 @@ func (c *Client) CreateFQDN(fqdn *FQDN) error {
 
 func (c *Client) DeleteFQDN(fqdn *FQDN) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "del_fqdn_filter_tag",
 		"tag_name": fqdn.FQDNTag,
 	}
@@ -91,7 +91,7 @@ func (c *Client) DeleteFQDN(fqdn *FQDN) error {
 //change state to 'enabled' or 'disabled'
 func (c *Client) UpdateFQDNStatus(fqdn *FQDN) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "set_fqdn_filter_tag_state",
 		"tag_name": fqdn.FQDNTag,
 		"status":   fqdn.FQDNStatus,
@@ -103,7 +103,7 @@ func (c *Client) UpdateFQDNStatus(fqdn *FQDN) error {
 //Change default mode to 'white' or 'black'
 func (c *Client) UpdateFQDNMode(fqdn *FQDN) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "set_fqdn_filter_tag_color",
 		"tag_name": fqdn.FQDNTag,
 		"color":    fqdn.FQDNMode,
@@ -114,12 +114,12 @@ func (c *Client) UpdateFQDNMode(fqdn *FQDN) error {
 
 func (c *Client) UpdateDomains(fqdn *FQDN) error {
 	// TODO: use PostAPI - domain names need special processing
-	fqdn.CID = c.CID
+	fqdn.CID = c.cid()
 	fqdn.Action = "set_fqdn_filter_tag_domain_names"
 	log.Infof("Update domains: %#v", fqdn)
 
 	verb := "POST"
-	body := fmt.Sprintf("CID=%s&action=%s&tag_name=%s", c.CID, fqdn.Action, fqdn.FQDNTag)
+	body := fmt.Sprintf("CID=%s&action=%s&tag_name=%s", c.cid(), fqdn.Action, fqdn.FQDNTag)
 	for i, dn := range fqdn.DomainList {
 		body = body + fmt.Sprintf("&domain_names[%d][fqdn]=%s&domain_names[%d]"+
 			"[proto]=%s&domain_names[%d][port]=%s&domain_names[%d][verdict]=%s", i, dn.FQDN, i, dn.Protocol, i, dn.Port, i, dn.Verdict)
@@ -155,7 +155,7 @@ func (c *Client) AttachGws(fqdn *FQDN) error {
 
 func (c *Client) DetachGws(fqdn *FQDN, gwList []string) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "detach_fqdn_filter_tag_from_gw",
 		"tag_name": fqdn.FQDNTag,
 	}
@@ -173,7 +173,7 @@ func (c *Client) DetachGws(fqdn *FQDN, gwList []string) error {
 
 func (c *Client) ListFQDNTags() ([]*FQDN, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_fqdn_filter_tags",
 	}
 
@@ -218,7 +218,7 @@ func (c *Client) GetFQDNTag(fqdn *FQDN) (*FQDN, error) {
 
 func (c *Client) ListDomains(fqdn *FQDN) (*FQDN, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_fqdn_filter_tag_domain_names",
 		"tag_name": fqdn.FQDNTag,
 	}
@@ -247,7 +247,7 @@ func (c *Client) ListDomains(fqdn *FQDN) (*FQDN, error) {
 
 func (c *Client) ListGws(fqdn *FQDN) ([]string, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_fqdn_filter_tag_attached_gws",
 		"tag_name": fqdn.FQDNTag,
 	}
@@ -264,7 +264,7 @@ func (c *Client) ListGws(fqdn *FQDN) ([]string, error) {
 
 func (c *Client) AttachTagToGw(fqdn *FQDN, gateway *Gateway) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "attach_fqdn_filter_tag_to_gw",
 		"tag_name": fqdn.FQDNTag,
 		"gw_name":  gateway.GwName,
@@ -275,7 +275,7 @@ func (c *Client) AttachTagToGw(fqdn *FQDN, gateway *Gateway) error {
 
 func (c *Client) UpdateSourceIPFilters(fqdn *FQDN, gateway *Gateway, sourceIPs []string) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "update_fqdn_filter_tag_source_ip_filters",
 		"tag_name":     fqdn.FQDNTag,
 		"gateway_name": gateway.GwName,
@@ -299,7 +299,7 @@ func (c *Client) GetGwFilterTagList(fqdn *FQDN) (*FQDN, error) {
 	var gwFilterTagList []GwFilterTag
 	for i := range listGws {
 		form := map[string]string{
-			"CID":          c.CID,
+			"CID":          c.cid(),
 			"action":       "list_fqdn_filter_tag_source_ip_filters",
 			"tag_name":     fqdn.FQDNTag,
 			"gateway_name": listGws[i],
@@ -328,7 +328,7 @@ func (c *Client) GetGwFilterTagList(fqdn *FQDN) (*FQDN, error) {
 
 func (c *Client) GetFQDNPassThroughCIDRs(gw *Gateway) ([]string, error) {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "list_fqdn_pass_through_cidrs",
 		"gateway_name": gw.GwName,
 	}
@@ -349,7 +349,7 @@ func (c *Client) GetFQDNPassThroughCIDRs(gw *Gateway) ([]string, error) {
 
 func (c *Client) ConfigureFQDNPassThroughCIDRs(gw *Gateway, IPs []string) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "update_fqdn_pass_through_cidrs",
 		"gateway_name": gw.GwName,
 	}
@@ -363,7 +363,7 @@ func (c *Client) ConfigureFQDNPassThroughCIDRs(gw *Gateway, IPs []string) error
 
 func (c *Client) DisableFQDNPassThrough(gw *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "update_fqdn_pass_through_cidrs",
 		"gateway_name": gw.GwName,
 	}
@@ -378,7 +378,7 @@ func (c *Client) AddFQDNTagRule(fqdn *FQDN) error {
 	}
 
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "add_fqdn_policies_to_tag",
 		"tag_name": fqdn.FQDNTag,
 		"policies": string(policies),
@@ -413,7 +413,7 @@ func (c *Client) DeleteFQDNTagRule(fqdn *FQDN) error {
 	}
 
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "delete_fqdn_policies_to_tag",
 		"tag_name": fqdn.FQDNTag,
 		"policies": string(policies),
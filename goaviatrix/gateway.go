@@ -337,7 +337,7 @@ type FQDNGatwayInfo struct {
 }
 
 func (c *Client) CreateGateway(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "connect_container"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
@@ -346,7 +346,7 @@ func (c *Client) CreateGateway(gateway *Gateway) error {
 func (c *Client) CreatePublicSubnetFilteringGateway(gateway *Gateway) error {
 	data := map[string]string{
 		"action":         "add_public_subnet_filtering_gateway",
-		"CID":            c.CID,
+		"CID":            c.cid(),
 		"cloud_type":     strconv.Itoa(gateway.CloudType),
 		"account_name":   gateway.AccountName,
 		"region":         gateway.VpcRegion,
@@ -363,7 +363,7 @@ func (c *Client) CreatePublicSubnetFilteringGateway(gateway *Gateway) error {
 func (c *Client) DeletePublicSubnetFilteringGateway(gateway *Gateway) error {
 	data := map[string]string{
 		"action":       "delete_public_subnet_filtering_gateway",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
@@ -372,7 +372,7 @@ func (c *Client) DeletePublicSubnetFilteringGateway(gateway *Gateway) error {
 func (c *Client) EnablePublicSubnetFilteringHAGateway(gateway *Gateway) error {
 	data := map[string]string{
 		"action":         "enable_ha_for_public_subnet_filtering_gateway",
-		"CID":            c.CID,
+		"CID":            c.cid(),
 		"gateway_name":   gateway.GwName,
 		"gateway_subnet": gateway.PeeringHASubnet,
 		"route_tables":   gateway.RouteTable,
@@ -399,7 +399,7 @@ type PublicSubnetFilteringGatewayDetailsResp struct {
 func (c *Client) GetPublicSubnetFilteringGatewayDetails(gateway *Gateway) (*PublicSubnetFilteringGatewayDetails, error) {
 	data := map[string]string{
 		"action":       "get_public_subnet_filtering_gateway_details",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 	}
 	var resp PublicSubnetFilteringGatewayDetailsResp
@@ -413,7 +413,7 @@ func (c *Client) GetPublicSubnetFilteringGatewayDetails(gateway *Gateway) (*Publ
 func (c *Client) EditPublicSubnetFilteringRouteTableList(gateway *Gateway, routeTables []string) error {
 	data := map[string]string{
 		"action":       "edit_public_subnet_filtering_enforced_route_table_list",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 		"route_table":  strings.Join(routeTables, ", "),
 	}
@@ -423,7 +423,7 @@ func (c *Client) EditPublicSubnetFilteringRouteTableList(gateway *Gateway, route
 func (c *Client) EnableGuardDutyEnforcement(gateway *Gateway) error {
 	data := map[string]string{
 		"action":       "enable_public_subnet_filtering_guard_duty_enforced_mode",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
@@ -432,35 +432,35 @@ func (c *Client) EnableGuardDutyEnforcement(gateway *Gateway) error {
 func (c *Client) DisableGuardDutyEnforcement(gateway *Gateway) error {
 	data := map[string]string{
 		"action":       "disable_public_subnet_filtering_guard_duty_enforced_mode",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
 
 func (c *Client) EnableNatGateway(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "enable_nat"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) EnableSingleAZGateway(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "enable_single_az_ha"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) EnablePeeringHaGateway(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "create_peering_ha_gateway"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) DisableSingleAZGateway(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "disable_single_az_ha"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
@@ -469,7 +469,7 @@ func (c *Client) DisableSingleAZGateway(gateway *Gateway) error {
 func (c *Client) GetGateway(gateway *Gateway) (*Gateway, error) {
 	action := "list_vpcs_summary"
 	params := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": gateway.GwName,
 	}
@@ -495,7 +495,7 @@ func (c *Client) GetGateway(gateway *Gateway) (*Gateway, error) {
 
 func (c *Client) GetGatewayDetail(gateway *Gateway) (*GatewayDetail, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_vpc_by_name",
 		"vpc_name": gateway.GwName,
 	}
@@ -516,15 +516,17 @@ func (c *Client) GetGatewayDetail(gateway *Gateway) (*GatewayDetail, error) {
 }
 
 func (c *Client) UpdateGateway(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "edit_gw_config"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) DeleteGateway(gateway *Gateway) error {
+	defer c.invalidateTagCacheByName(gateway.GwName)
+
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "delete_container",
 		"cloud_type": strconv.Itoa(gateway.CloudType),
 		"gw_name":    gateway.GwName,
@@ -534,7 +536,7 @@ func (c *Client) DeleteGateway(gateway *Gateway) error {
 }
 
 func (c *Client) EnableSNat(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "enable_snat"
 	args, err := json.Marshal(gateway.SnatPolicy)
 	if err != nil {
@@ -546,21 +548,21 @@ func (c *Client) EnableSNat(gateway *Gateway) error {
 }
 
 func (c *Client) DisableSNat(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "disable_snat"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) DisableCustomSNat(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "enable_snat"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) UpdateDNat(gateway *Gateway) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "update_dnat_config"
 	args, err := json.Marshal(gateway.DnatPolicy)
 	if err != nil {
@@ -573,7 +575,7 @@ func (c *Client) UpdateDNat(gateway *Gateway) error {
 
 func (c *Client) UpdateVpnCidr(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "edit_vpn_gateway_virtual_address_range",
 		"vpn_cidr":     gateway.VpnCidr,
 		"gateway_name": gateway.GwName,
@@ -584,7 +586,7 @@ func (c *Client) UpdateVpnCidr(gateway *Gateway) error {
 
 func (c *Client) UpdateMaxVpnConn(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":                c.CID,
+		"CID":                c.cid(),
 		"action":             "set_vpn_max_connection",
 		"max_connections":    gateway.MaxConn,
 		"vpc_id":             gateway.VpcID,
@@ -599,7 +601,7 @@ func (c *Client) UpdateMaxVpnConn(gateway *Gateway) error {
 }
 
 func (c *Client) SetVpnGatewayAuthentication(gateway *VpnGatewayAuth) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "set_vpn_gateway_authentication"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
@@ -607,7 +609,7 @@ func (c *Client) SetVpnGatewayAuthentication(gateway *VpnGatewayAuth) error {
 
 func (c *Client) EnableVpcDnsServer(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_vpc_dns_server",
 		"gateway_name": gateway.GwName,
 	}
@@ -617,7 +619,7 @@ func (c *Client) EnableVpcDnsServer(gateway *Gateway) error {
 
 func (c *Client) DisableVpcDnsServer(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_vpc_dns_server",
 		"gateway_name": gateway.GwName,
 	}
@@ -627,7 +629,7 @@ func (c *Client) DisableVpcDnsServer(gateway *Gateway) error {
 
 func (c *Client) EnableVpnNat(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "enable_nat_on_vpn_gateway",
 		"vpc_id": gateway.VpcID,
 	}
@@ -646,7 +648,7 @@ func (c *Client) EnableVpnNat(gateway *Gateway) error {
 
 func (c *Client) DisableVpnNat(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "disable_nat_on_vpn_gateway",
 		"vpc_id": gateway.VpcID,
 	}
@@ -665,7 +667,7 @@ func (c *Client) DisableVpnNat(gateway *Gateway) error {
 
 func (c *Client) EditDesignatedGateway(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "set_designated_gateway_additional_cidr_list",
 		"gateway_name":         gateway.GwName,
 		"additional_cidr_list": gateway.AdditionalCidrsDesignatedGw,
@@ -676,7 +678,7 @@ func (c *Client) EditDesignatedGateway(gateway *Gateway) error {
 
 func (c *Client) EnableEncryptVolume(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "encrypt_gateway_volume",
 		"gateway_name": gateway.GwName,
 	}
@@ -700,7 +702,7 @@ func (c *Client) EnableEncryptVolume(gateway *Gateway) error {
 
 func (c *Client) EditGatewayCustomRoutes(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "edit_gateway_custom_routes",
 		"gateway_name": gateway.GwName,
 		"cidr":         strings.Join(gateway.CustomizedSpokeVpcRoutes, ","),
@@ -711,7 +713,7 @@ func (c *Client) EditGatewayCustomRoutes(gateway *Gateway) error {
 
 func (c *Client) EditGatewayFilterRoutes(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "edit_gateway_filter_routes",
 		"gateway_name": gateway.GwName,
 		"cidr":         strings.Join(gateway.FilteredSpokeVpcRoutes, ","),
@@ -722,7 +724,7 @@ func (c *Client) EditGatewayFilterRoutes(gateway *Gateway) error {
 
 func (c *Client) EditGatewayAdvertisedCidr(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "edit_gateway_advertised_cidr",
 		"gateway_name": gateway.GwName,
 		"cidr":         strings.Join(gateway.AdvertisedSpokeRoutes, ","),
@@ -733,7 +735,7 @@ func (c *Client) EditGatewayAdvertisedCidr(gateway *Gateway) error {
 
 func (c *Client) EnableTransitFireNet(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_gateway_for_transit_firenet",
 		"gateway_name": gateway.GwName,
 	}
@@ -743,7 +745,7 @@ func (c *Client) EnableTransitFireNet(gateway *Gateway) error {
 
 func (c *Client) EnableTransitFireNetWithGWLB(gateway *Gateway) error {
 	data := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_gateway_for_transit_firenet",
 		"gateway_name": gateway.GwName,
 		"mode":         "gwlb",
@@ -758,7 +760,7 @@ func (c *Client) DisableTransitFireNet(gateway *Gateway) error {
 	}
 
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_gateway_for_transit_firenet",
 		"gateway_name": gateway.GwName,
 	}
@@ -768,7 +770,7 @@ func (c *Client) DisableTransitFireNet(gateway *Gateway) error {
 
 func (c *Client) IsTransitFireNetReadyToBeDisabled(gateway *Gateway) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_transit_firenet_spoke_policies",
 	}
 
@@ -801,7 +803,7 @@ func (c *Client) IsTransitFireNetReadyToBeDisabled(gateway *Gateway) error {
 func (c *Client) EnableSegmentation(transitGateway *TransitVpc) error {
 	action := "enable_transit_gateway_for_multi_cloud_security_domain"
 	form := map[string]interface{}{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               action,
 		"transit_gateway_name": transitGateway.GwName,
 	}
@@ -811,7 +813,7 @@ func (c *Client) EnableSegmentation(transitGateway *TransitVpc) error {
 func (c *Client) DisableSegmentation(transitGateway *TransitVpc) error {
 	action := "disable_transit_gateway_for_multi_cloud_security_domain"
 	form := map[string]interface{}{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               action,
 		"transit_gateway_name": transitGateway.GwName,
 	}
@@ -820,7 +822,7 @@ func (c *Client) DisableSegmentation(transitGateway *TransitVpc) error {
 
 func (c *Client) IsSegmentationEnabled(transitGateway *TransitVpc) (bool, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_transit_gateways_for_multi_cloud_domains",
 	}
 
@@ -848,7 +850,7 @@ func (c *Client) IsSegmentationEnabled(transitGateway *TransitVpc) (bool, error)
 func (c *Client) EnableEgressTransitFirenet(transitGateway *TransitVpc) error {
 	action := "enable_transit_firenet_on_egress_transit_gateway"
 	data := map[string]interface{}{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": transitGateway.GwName,
 	}
@@ -858,7 +860,7 @@ func (c *Client) EnableEgressTransitFirenet(transitGateway *TransitVpc) error {
 func (c *Client) DisableEgressTransitFirenet(transitGateway *TransitVpc) error {
 	action := "disable_transit_firenet_on_egress_transit_gateway"
 	data := map[string]interface{}{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": transitGateway.GwName,
 	}
@@ -868,7 +870,7 @@ func (c *Client) DisableEgressTransitFirenet(transitGateway *TransitVpc) error {
 func (c *Client) EnableMonitorGatewaySubnets(gwName string, excludedInstances []string) error {
 	action := "enable_monitor_gateway_subnets"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": gwName,
 	}
@@ -881,7 +883,7 @@ func (c *Client) EnableMonitorGatewaySubnets(gwName string, excludedInstances []
 func (c *Client) DisableMonitorGatewaySubnets(gwName string) error {
 	action := "disable_monitor_gateway_subnets"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": gwName,
 	}
@@ -900,7 +902,7 @@ func (c *Client) DisableMonitorGatewaySubnets(gwName string) error {
 func (c *Client) EnableVPNConfig(gateway *Gateway, vpnConfig *VPNConfig) error {
 	action := "edit_vpn_config"
 	form := map[string]interface{}{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  action,
 		"command": "enable",
 		"vpc_id":  gateway.VpcID,
@@ -914,7 +916,7 @@ func (c *Client) EnableVPNConfig(gateway *Gateway, vpnConfig *VPNConfig) error {
 func (c *Client) DisableVPNConfig(gateway *Gateway, vpnConfig *VPNConfig) error {
 	action := "edit_vpn_config"
 	form := map[string]interface{}{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  action,
 		"command": "disable",
 		"vpc_id":  gateway.VpcID,
@@ -927,7 +929,7 @@ func (c *Client) DisableVPNConfig(gateway *Gateway, vpnConfig *VPNConfig) error
 
 func (c *Client) GetVPNConfigList(gateway *Gateway) ([]VPNConfig, error) {
 	form := map[string]string{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  "edit_vpn_config",
 		"command": "show",
 		"vpc_id":  gateway.VpcID,
@@ -947,7 +949,7 @@ func (c *Client) GetVPNConfigList(gateway *Gateway) ([]VPNConfig, error) {
 func (c *Client) EnableActiveStandby(transitGateway *TransitVpc) error {
 	action := "enable_active_standby"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": transitGateway.GwName,
 	}
@@ -957,7 +959,7 @@ func (c *Client) EnableActiveStandby(transitGateway *TransitVpc) error {
 func (c *Client) DisableActiveStandby(transitGateway *TransitVpc) error {
 	action := "disable_active_standby"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": transitGateway.GwName,
 	}
@@ -967,7 +969,7 @@ func (c *Client) DisableActiveStandby(transitGateway *TransitVpc) error {
 func (c *Client) SwitchActiveTransitGateway(gwName, connName string) error {
 	action := "active_standby_connection_switchover"
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          action,
 		"gateway_name":    gwName,
 		"connection_name": connName,
@@ -977,7 +979,7 @@ func (c *Client) SwitchActiveTransitGateway(gwName, connName string) error {
 
 func (c *Client) GetTransitGatewayLanCidr(gatewayName string) (string, error) {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "get_firewall_lan_cidr",
 		"gateway_name": gatewayName,
 	}
@@ -1007,7 +1009,7 @@ func (c *Client) GetFqdnGatewayInfo(gateway *Gateway) (*FQDNGatwayInfo, error) {
 		"action":    "list_firenet",
 		"subaction": "instance",
 		"vpc_id":    gateway.VpcID,
-		"CID":       c.CID,
+		"CID":       c.cid(),
 	}
 	var data FQDNGatewayInfoResp
 	err := c.GetAPI(&data, params["action"], params, BasicCheck)
@@ -1020,7 +1022,7 @@ func (c *Client) GetFqdnGatewayInfo(gateway *Gateway) (*FQDNGatwayInfo, error) {
 func (c *Client) UpdateTransitGatewayCustomizedVpcRoute(gateway string, customizedTransitVpcRoutes []string) error {
 	params := map[string]string{
 		"action":            "edit_transit_gateway_customized_vpc_route",
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"gateway_name":      gateway,
 		"customized_routes": strings.Join(customizedTransitVpcRoutes, ","),
 	}
@@ -1031,7 +1033,7 @@ func (c *Client) UpdateTransitGatewayCustomizedVpcRoute(gateway string, customiz
 func (c *Client) EnableJumboFrame(gateway *Gateway) error {
 	action := "enable_jumbo_frame"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": gateway.GwName,
 	}
@@ -1041,7 +1043,7 @@ func (c *Client) EnableJumboFrame(gateway *Gateway) error {
 func (c *Client) DisableJumboFrame(gateway *Gateway) error {
 	action := "disable_jumbo_frame"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": gateway.GwName,
 	}
@@ -1051,7 +1053,7 @@ func (c *Client) DisableJumboFrame(gateway *Gateway) error {
 func (c *Client) GetJumboFrameStatus(gateway *Gateway) (bool, error) {
 	action := "get_jumbo_frame_status"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": gateway.GwName,
 	}
@@ -1072,7 +1074,7 @@ func (c *Client) GetJumboFrameStatus(gateway *Gateway) (bool, error) {
 
 func (c *Client) EnablePrivateVpcDefaultRoute(gw *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_private_vpc_default_route",
 		"gateway_name": gw.GwName,
 	}
@@ -1081,7 +1083,7 @@ func (c *Client) EnablePrivateVpcDefaultRoute(gw *Gateway) error {
 
 func (c *Client) DisablePrivateVpcDefaultRoute(gw *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_private_vpc_default_route",
 		"gateway_name": gw.GwName,
 	}
@@ -1090,7 +1092,7 @@ func (c *Client) DisablePrivateVpcDefaultRoute(gw *Gateway) error {
 
 func (c *Client) EnableSkipPublicRouteUpdate(gw *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_skip_public_route_table_update",
 		"gateway_name": gw.GwName,
 	}
@@ -1099,7 +1101,7 @@ func (c *Client) EnableSkipPublicRouteUpdate(gw *Gateway) error {
 
 func (c *Client) DisableSkipPublicRouteUpdate(gw *Gateway) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_skip_public_route_table_update",
 		"gateway_name": gw.GwName,
 	}
@@ -1109,7 +1111,7 @@ func (c *Client) DisableSkipPublicRouteUpdate(gw *Gateway) error {
 // Entity should be gateway name or "Controller"
 func (c *Client) GetTunnelDetectionTime(entity string) (int, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "show_tunnel_status_change_detection_time",
 		"entity": entity,
 	}
@@ -1134,7 +1136,7 @@ func (c *Client) GetTunnelDetectionTime(entity string) (int, error) {
 
 func (c *Client) ModifyTunnelDetectionTime(entity string, detectionTime int) error {
 	form := map[string]string{
-		"CID":            c.CID,
+		"CID":            c.cid(),
 		"action":         "modify_detection_time",
 		"detection_time": strconv.Itoa(detectionTime),
 		"entity":         entity,
@@ -9,7 +9,7 @@ import (
 func (c *Client) SetGatewayKeepaliveConfig(ctx context.Context, speed string) error {
 	data := map[string]string{
 		"action": "set_keep_alive_speed",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"speed":  speed,
 	}
 
@@ -26,7 +26,7 @@ func (c *Client) SetGatewayKeepaliveConfig(ctx context.Context, speed string) er
 func (c *Client) GetGatewayKeepaliveConfig(ctx context.Context) (string, error) {
 	data := map[string]string{
 		"action": "get_keep_alive_speed",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type GatewayKeepaliveResults struct {
@@ -40,7 +40,7 @@ type GetGeoVPNInfoResp struct {
 }
 
 func (c *Client) EnableGeoVPN(geoVPN *GeoVPN) error {
-	geoVPN.CID = c.CID
+	geoVPN.CID = c.cid()
 	geoVPN.Action = "enable_geo_vpn"
 
 	return c.PostAPI(geoVPN.Action, geoVPN, BasicCheck)
@@ -48,7 +48,7 @@ func (c *Client) EnableGeoVPN(geoVPN *GeoVPN) error {
 
 func (c *Client) GetGeoVPNInfo(geoVPN *GeoVPN) (*GeoVPN, error) {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "get_geo_vpn_info",
 		"cloud_type": strconv.Itoa(geoVPN.CloudType),
 	}
@@ -88,21 +88,21 @@ func (c *Client) GetGeoVPNInfo(geoVPN *GeoVPN) (*GeoVPN, error) {
 }
 
 func (c *Client) AddElbToGeoVPN(geoVPN *GeoVPN) error {
-	geoVPN.CID = c.CID
+	geoVPN.CID = c.cid()
 	geoVPN.Action = "add_elb_to_geo_vpn"
 
 	return c.PostAPI(geoVPN.Action, geoVPN, BasicCheck)
 }
 
 func (c *Client) DeleteElbFromGeoVPN(geoVPN *GeoVPN) error {
-	geoVPN.CID = c.CID
+	geoVPN.CID = c.cid()
 	geoVPN.Action = "delete_elb_from_geo_vpn"
 
 	return c.PostAPI(geoVPN.Action, geoVPN, BasicCheck)
 }
 
 func (c *Client) DisableGeoVPN(geoVPN *GeoVPN) error {
-	geoVPN.CID = c.CID
+	geoVPN.CID = c.cid()
 	geoVPN.Action = "disable_geo_vpn"
 
 	return c.PostAPI(geoVPN.Action, geoVPN, BasicCheck)
@@ -110,7 +110,7 @@ func (c *Client) DisableGeoVPN(geoVPN *GeoVPN) error {
 
 func (c *Client) GetGeoVPNName(gateway *Gateway) (*GeoVPN, error) {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "get_geo_vpn_info",
 		"cloud_type": strconv.Itoa(gateway.CloudType),
 	}
@@ -21,7 +21,7 @@ type NetflowAgentResp struct {
 func (c *Client) EnableNetflowAgent(r *NetflowAgent) error {
 	params := map[string]string{
 		"action":               "enable_netflow_agent",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"server_ip":            r.ServerIp,
 		"port":                 strconv.Itoa(r.Port),
 		"version":              strconv.Itoa(r.Version),
@@ -34,7 +34,7 @@ func (c *Client) EnableNetflowAgent(r *NetflowAgent) error {
 func (c *Client) GetNetflowAgentStatus() (*NetflowAgentResp, error) {
 	params := map[string]string{
 		"action": "get_netflow_agent",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -60,7 +60,7 @@ func (c *Client) GetNetflowAgentStatus() (*NetflowAgentResp, error) {
 func (c *Client) DisableNetflowAgent() error {
 	params := map[string]string{
 		"action": "disable_netflow_agent",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	return c.PostAPI(params["action"], params, BasicCheck)
@@ -0,0 +1,44 @@
+package goaviatrix
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// listPageSize is the number of results requested per page from list actions that support pagination
+var listPageSize = 500
+
+// maxListPages bounds the number of pages listAllPages requests, in case a controller never reports
+// the last page
+const maxListPages = 10000
+
+// Pagination is returned by list actions that support pagination, along with a page of results.
+// Controllers that do not paginate omit it and return all results at once.
+type Pagination struct {
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// listAllPages calls getPage with the form of a list action for every page of results, starting with
+// the first one, until the controller reports the last page. The page parameters are added to a copy of
+// form. getPage sends the request, collects the results and returns the pagination of the response, or
+// nil if the response is not paginated.
+func listAllPages(form map[string]string, getPage func(form map[string]string) (*Pagination, error)) error {
+	for page := 1; page <= maxListPages; page++ {
+		pageForm := make(map[string]string, len(form)+2)
+		for key, val := range form {
+			pageForm[key] = val
+		}
+		pageForm["page"] = strconv.Itoa(page)
+		pageForm["page_size"] = strconv.Itoa(listPageSize)
+
+		pagination, err := getPage(pageForm)
+		if err != nil {
+			return err
+		}
+		if pagination == nil || page >= pagination.TotalPages {
+			return nil
+		}
+	}
+	return fmt.Errorf("rest API %s did not return the last page after %d pages", form["action"], maxListPages)
+}
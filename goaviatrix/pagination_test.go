@@ -0,0 +1,114 @@
+package goaviatrix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// paginatedServer serves the results of a list action in pages of pageSize results, as the pages
+// of the 'results' array of the response or, if asMap is set, of a 'results' object.
+func paginatedServer(t *testing.T, results []string, pageSize int, asMap bool) *httptest.Server {
+	totalPages := (len(results) + pageSize - 1) / pageSize
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 || page > totalPages {
+			t.Errorf("unexpected page %q requested", r.URL.Query().Get("page"))
+			respondJSON(`{"return":false,"reason":"invalid page"}`)(w)
+			return
+		}
+		end := page * pageSize
+		if end > len(results) {
+			end = len(results)
+		}
+
+		body := ""
+		for i, result := range results[(page-1)*pageSize : end] {
+			if i > 0 {
+				body += ","
+			}
+			body += result
+		}
+		if asMap {
+			body = "{" + body + "}"
+		} else {
+			body = "[" + body + "]"
+		}
+		respondJSON(fmt.Sprintf(`{"return":true,"results":%s,"pagination":{"page":%d,"total_pages":%d}}`, body, page, totalPages))(w)
+	}))
+}
+
+func TestListDevicesPaginated(t *testing.T) {
+	var results, want []string
+	for i := 1; i <= 5; i++ {
+		name := fmt.Sprintf("device-%d", i)
+		results = append(results, fmt.Sprintf(`{"rgw_name":%q,"address":{"city":"Santa Clara"}}`, name))
+		want = append(want, name)
+	}
+	srv := paginatedServer(t, results, 2, false)
+	defer srv.Close()
+
+	devices, err := newTestClient(srv).ListDevices(context.Background())
+	if err != nil {
+		t.Fatalf("ListDevices() unexpected error: %v", err)
+	}
+	var got []string
+	for _, device := range devices {
+		got = append(got, device.Name)
+		if device.City != "Santa Clara" {
+			t.Errorf("ListDevices() device %s city = %q, want %q", device.Name, device.City, "Santa Clara")
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDevices() got = %v, want %v", got, want)
+	}
+}
+
+func TestListAllTagsPaginated(t *testing.T) {
+	var results, want []string
+	for i := 1; i <= 5; i++ {
+		name := fmt.Sprintf("gw-%d", i)
+		results = append(results, fmt.Sprintf(`%q:{"usr_tags":{"name":%q}}`, name, name))
+		want = append(want, name)
+	}
+	srv := paginatedServer(t, results, 2, true)
+	defer srv.Close()
+
+	allTags, err := newTestClient(srv).ListAllTags(1, "gw")
+	if err != nil {
+		t.Fatalf("ListAllTags() unexpected error: %v", err)
+	}
+	var got []string
+	for name, tags := range allTags {
+		got = append(got, name)
+		if tags["name"] != name {
+			t.Errorf("ListAllTags() tags of %s = %v, want name tag %q", name, tags, name)
+		}
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAllTags() got = %v, want %v", got, want)
+	}
+}
+
+func TestListAllPagesNotPaginated(t *testing.T) {
+	calls := 0
+	err := listAllPages(map[string]string{"action": "list"}, func(form map[string]string) (*Pagination, error) {
+		calls++
+		if form["page"] != "1" || form["page_size"] != strconv.Itoa(listPageSize) {
+			t.Errorf("listAllPages() sent page %q and page_size %q, want 1 and %d", form["page"], form["page_size"], listPageSize)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("listAllPages() unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("listAllPages() requested %d pages of a response without pagination, want 1", calls)
+	}
+}
@@ -23,14 +23,14 @@ type PeriodicPingStatusResult struct {
 
 func (c *Client) CreatePeriodicPing(pp *PeriodicPing) error {
 	pp.Action = "enable_gateway_periodic_ping"
-	pp.CID = c.CID
+	pp.CID = c.cid()
 
 	return c.PostAPI(pp.Action, pp, BasicCheck)
 }
 
 func (c *Client) GetPeriodicPing(pp *PeriodicPing) (*PeriodicPing, error) {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "get_gateway_periodic_ping_status",
 		"gateway_name": pp.GwName,
 	}
@@ -55,7 +55,7 @@ func (c *Client) GetPeriodicPing(pp *PeriodicPing) (*PeriodicPing, error) {
 
 func (c *Client) DeletePeriodicPing(pp *PeriodicPing) error {
 	pp.Action = "disable_gateway_periodic_ping"
-	pp.CID = c.CID
+	pp.CID = c.cid()
 
 	return c.PostAPI(pp.Action, pp, BasicCheck)
 }
@@ -0,0 +1,128 @@
+package goaviatrix
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Private key algorithms returned by DetectPrivateKeyAlgorithm
+const (
+	PrivateKeyAlgorithmRSA     = "rsa"
+	PrivateKeyAlgorithmECDSA   = "ecdsa"
+	PrivateKeyAlgorithmEd25519 = "ed25519"
+)
+
+const opensshKeyMagic = "openssh-key-v1\x00"
+
+// DetectPrivateKeyAlgorithm returns the algorithm of the PEM encoded private key, one of
+// PrivateKeyAlgorithmRSA, PrivateKeyAlgorithmECDSA or PrivateKeyAlgorithmEd25519. PKCS#1, SEC 1,
+// unencrypted PKCS#8 and OpenSSH keys are supported, the latter also when encrypted.
+func DetectPrivateKeyAlgorithm(pemData []byte) (string, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return "", errors.New("no PEM encoded private key found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return PrivateKeyAlgorithmRSA, nil
+	case "EC PRIVATE KEY":
+		return PrivateKeyAlgorithmECDSA, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("could not parse PKCS#8 private key: %v", err)
+		}
+		switch key.(type) {
+		case *rsa.PrivateKey:
+			return PrivateKeyAlgorithmRSA, nil
+		case *ecdsa.PrivateKey:
+			return PrivateKeyAlgorithmECDSA, nil
+		case ed25519.PrivateKey:
+			return PrivateKeyAlgorithmEd25519, nil
+		}
+		return "", fmt.Errorf("unsupported PKCS#8 private key type %T", key)
+	case "OPENSSH PRIVATE KEY":
+		return detectOpenSSHKeyAlgorithm(block.Bytes)
+	}
+	return "", fmt.Errorf("unsupported PEM block type %q", block.Type)
+}
+
+// detectOpenSSHKeyAlgorithm reads the algorithm from the public key of an OpenSSH private key,
+// which is stored unencrypted even if the private key is encrypted.
+func detectOpenSSHKeyAlgorithm(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte(opensshKeyMagic)) {
+		return "", errors.New("invalid OpenSSH private key")
+	}
+	rest := data[len(opensshKeyMagic):]
+
+	// skip cipher name, kdf name and kdf options
+	var err error
+	for i := 0; i < 3; i++ {
+		if _, rest, err = readSSHString(rest); err != nil {
+			return "", err
+		}
+	}
+	if len(rest) < 4 {
+		return "", errors.New("invalid OpenSSH private key")
+	}
+	publicKey, _, err := readSSHString(rest[4:])
+	if err != nil {
+		return "", err
+	}
+	keyType, _, err := readSSHString(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case string(keyType) == "ssh-rsa":
+		return PrivateKeyAlgorithmRSA, nil
+	case strings.HasPrefix(string(keyType), "ecdsa-sha2-"):
+		return PrivateKeyAlgorithmECDSA, nil
+	case string(keyType) == "ssh-ed25519":
+		return PrivateKeyAlgorithmEd25519, nil
+	}
+	return "", fmt.Errorf("unsupported OpenSSH private key type %q", keyType)
+}
+
+func readSSHString(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, errors.New("invalid OpenSSH private key")
+	}
+	length := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint32(len(data)) < length {
+		return nil, nil, errors.New("invalid OpenSSH private key")
+	}
+	return data[:length], data[length:], nil
+}
+
+// keyAlgorithm returns the algorithm of the device's private key, or an empty string if there is
+// no private key or its algorithm can not be detected.
+func (d *Device) keyAlgorithm() string {
+	keyContent := []byte(d.KeyFileContent)
+	if d.KeyFileContent == "" {
+		if d.KeyFile == "" {
+			return ""
+		}
+		var err error
+		if keyContent, err = ioutil.ReadFile(d.KeyFile); err != nil {
+			return ""
+		}
+	}
+	algorithm, err := DetectPrivateKeyAlgorithm(keyContent)
+	if err != nil {
+		return ""
+	}
+	return algorithm
+}
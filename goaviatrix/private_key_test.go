@@ -0,0 +1,151 @@
+package goaviatrix
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sshString(s string) []byte {
+	b := make([]byte, 4, 4+len(s))
+	binary.BigEndian.PutUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// testOpenSSHKey returns an OpenSSH private key with the given public key type. Only the
+// unencrypted header is filled in, which is all DetectPrivateKeyAlgorithm reads.
+func testOpenSSHKey(keyType, cipher string) []byte {
+	data := []byte(opensshKeyMagic)
+	data = append(data, sshString(cipher)...)
+	data = append(data, sshString("none")...)
+	data = append(data, sshString("")...)
+	data = append(data, 0, 0, 0, 1)
+	data = append(data, sshString(string(sshString(keyType))+"public-key-data")...)
+	return pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: data})
+}
+
+func TestDetectPrivateKeyAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaDER, err := x509.MarshalECPrivateKey(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519DER, err := x509.MarshalPKCS8PrivateKey(ed25519Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPKCS8DER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pemData []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			"rsa pkcs1",
+			pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			PrivateKeyAlgorithmRSA,
+			false,
+		},
+		{
+			"rsa pkcs8",
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rsaPKCS8DER}),
+			PrivateKeyAlgorithmRSA,
+			false,
+		},
+		{
+			"ecdsa sec1",
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecdsaDER}),
+			PrivateKeyAlgorithmECDSA,
+			false,
+		},
+		{
+			"ed25519 pkcs8",
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ed25519DER}),
+			PrivateKeyAlgorithmEd25519,
+			false,
+		},
+		{
+			"rsa openssh",
+			testOpenSSHKey("ssh-rsa", "none"),
+			PrivateKeyAlgorithmRSA,
+			false,
+		},
+		{
+			"ecdsa openssh",
+			testOpenSSHKey("ecdsa-sha2-nistp256", "none"),
+			PrivateKeyAlgorithmECDSA,
+			false,
+		},
+		{
+			"encrypted ed25519 openssh",
+			testOpenSSHKey("ssh-ed25519", "aes256-ctr"),
+			PrivateKeyAlgorithmEd25519,
+			false,
+		},
+		{
+			"not pem",
+			[]byte("not a private key"),
+			"",
+			true,
+		},
+		{
+			"truncated openssh",
+			pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte(opensshKeyMagic)}),
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectPrivateKeyAlgorithm(tt.pemData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectPrivateKeyAlgorithm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectPrivateKeyAlgorithm() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDeviceKeyAlgorithmError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(`{"return":false,"reason":"failed to connect to device"}`)(w)
+	}))
+	defer srv.Close()
+
+	device := &Device{Name: "test-device", KeyFileContent: string(testOpenSSHKey("ssh-ed25519", "none"))}
+	err := newTestClient(srv).RegisterDevice(context.Background(), device)
+	if err == nil {
+		t.Fatalf("RegisterDevice() expected an error, got none")
+	}
+	if !strings.Contains(err.Error(), "failed to connect to device") || !strings.Contains(err.Error(), "ed25519 key") {
+		t.Errorf("RegisterDevice() error = %q, want controller reason and detected key algorithm", err.Error())
+	}
+}
@@ -51,7 +51,7 @@ type ProfileBasePolicyResp struct {
 
 func (c *Client) CreateProfile(profile *Profile) error {
 	form1 := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "add_user_profile",
 		"profile_name": profile.Name,
 		"base_policy":  profile.BaseRule,
@@ -64,7 +64,7 @@ func (c *Client) CreateProfile(profile *Profile) error {
 
 	policyStr, _ := json.Marshal(profile.Policy)
 	form2 := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "update_profile_policy",
 		"profile_name": profile.Name,
 		"policy":       string(policyStr),
@@ -77,7 +77,7 @@ func (c *Client) CreateProfile(profile *Profile) error {
 
 	for _, user := range profile.UserList {
 		form := map[string]string{
-			"CID":          c.CID,
+			"CID":          c.cid(),
 			"action":       "add_profile_member",
 			"profile_name": profile.Name,
 			"username":     user,
@@ -93,7 +93,7 @@ func (c *Client) CreateProfile(profile *Profile) error {
 
 func (c *Client) GetProfile(profile *Profile) (*Profile, error) {
 	form1 := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "list_profile_policies",
 		"profile_name": profile.Name,
 	}
@@ -119,7 +119,7 @@ func (c *Client) GetProfile(profile *Profile) (*Profile, error) {
 	log.Tracef("Profile policy %s", profile.Policy)
 
 	form2 := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_user_profile_names",
 	}
 
@@ -140,7 +140,7 @@ func (c *Client) UpdateProfilePolicy(profile *Profile) error {
 
 	policyStr, _ := json.Marshal(profile.Policy)
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "update_profile_policy",
 		"profile_name": profile.Name,
 		"policy":       string(policyStr),
@@ -154,7 +154,7 @@ func (c *Client) AttachUsers(profile *Profile) error {
 
 	for _, user := range profile.UserList {
 		form := map[string]string{
-			"CID":          c.CID,
+			"CID":          c.cid(),
 			"action":       "add_profile_member",
 			"profile_name": profile.Name,
 			"username":     user,
@@ -173,7 +173,7 @@ func (c *Client) DetachUsers(profile *Profile) error {
 
 	for _, user := range profile.UserList {
 		form := map[string]string{
-			"CID":          c.CID,
+			"CID":          c.cid(),
 			"action":       "del_profile_member",
 			"profile_name": profile.Name,
 			"username":     user,
@@ -189,7 +189,7 @@ func (c *Client) DetachUsers(profile *Profile) error {
 
 func (c *Client) DeleteProfile(profile *Profile) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "del_user_profile",
 		"profile_name": profile.Name,
 	}
@@ -199,7 +199,7 @@ func (c *Client) DeleteProfile(profile *Profile) error {
 
 func (c *Client) GetProfileBasePolicy(profile *Profile) (*Profile, error) {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "get_profile_base_policy",
 		"profile_name": profile.Name,
 	}
@@ -19,7 +19,7 @@ type ProxyConfigResp struct {
 func (c *Client) CreateProxyConfig(proxyConfig *ProxyConfig) error {
 	action := "apply_proxy_config"
 	params := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      action,
 		"http_proxy":  proxyConfig.HttpProxy,
 		"https_proxy": proxyConfig.HttpsProxy,
@@ -43,7 +43,7 @@ func (c *Client) CreateProxyConfig(proxyConfig *ProxyConfig) error {
 func (c *Client) GetProxyConfig() (*ProxyConfig, error) {
 	formData := map[string]string{
 		"action": "show_proxy_config",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	var data ProxyConfigResp
 	err := c.GetAPI(&data, formData["action"], formData, BasicCheck)
@@ -65,7 +65,7 @@ func (c *Client) DeleteProxyConfig() error {
 	action := "delete_proxy_config"
 	data := map[string]interface{}{
 		"action": action,
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	return c.PostAPI(action, data, BasicCheck)
 }
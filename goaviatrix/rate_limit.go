@@ -0,0 +1,101 @@
+package goaviatrix
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that allows on average rate requests per second,
+// with bursts of up to burst requests.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	burst := math.Max(1, math.Floor(rate))
+	return &rateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent, or returns an error once ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// reserve a token, waiting for it to be refilled if the bucket is empty
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give the reserved token back so later requests do not wait for it
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// rateLimitedTransport waits for the limiter before sending each request
+type rateLimitedTransport struct {
+	limiter *rateLimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// SetRateLimit limits the requests sent to the controller by this client to requestsPerSecond
+// on average, shared between reads and writes. Bursts of up to requestsPerSecond requests are
+// allowed. A value of 0 or less removes the limit.
+func (c *Client) SetRateLimit(requestsPerSecond float64) {
+	httpClient := &http.Client{}
+	if c.HTTPClient != nil {
+		// copy the client so the rate limit does not apply to other users of the same http.Client
+		*httpClient = *c.HTTPClient
+	}
+	next := httpClient.Transport
+	if t, ok := next.(*rateLimitedTransport); ok {
+		next = t.next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if requestsPerSecond <= 0 {
+		httpClient.Transport = next
+	} else {
+		httpClient.Transport = &rateLimitedTransport{
+			limiter: newRateLimiter(requestsPerSecond),
+			next:    next,
+		}
+	}
+	c.HTTPClient = httpClient
+}
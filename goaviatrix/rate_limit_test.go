@@ -0,0 +1,58 @@
+package goaviatrix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetRateLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		respondJSON(`{"return":true,"results":[]}`)(w)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	originalHTTPClient := client.HTTPClient
+	originalTransport := originalHTTPClient.Transport
+	client.SetRateLimit(20)
+	if originalHTTPClient.Transport != originalTransport {
+		t.Errorf("SetRateLimit() modified the http.Client passed to the client")
+	}
+
+	// the first 20 requests are a burst, the next 10 have to wait 50ms each
+	start := time.Now()
+	for i := 0; i < 30; i++ {
+		if _, err := client.ListDevices(context.Background()); err != nil {
+			t.Fatalf("ListDevices() unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("30 requests at 20 requests per second took %s, want at least 400ms", elapsed)
+	}
+	if calls != 30 {
+		t.Errorf("server got %d calls, want 30", calls)
+	}
+
+	client.SetRateLimit(0)
+	if _, ok := client.HTTPClient.Transport.(*rateLimitedTransport); ok {
+		t.Errorf("SetRateLimit(0) did not remove the rate limit")
+	}
+}
+
+func TestRateLimiterWaitContextCanceled(t *testing.T) {
+	limiter := newRateLimiter(0.1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() for burst unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Errorf("Wait() with empty bucket and expiring context expected an error, got none")
+	}
+}
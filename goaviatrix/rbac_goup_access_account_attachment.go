@@ -18,7 +18,7 @@ type RbacGroupAccessAccountAttachmentListResp struct {
 }
 
 func (c *Client) CreateRbacGroupAccessAccountAttachment(rbacGroupAccessAccountAttachment *RbacGroupAccessAccountAttachment) error {
-	rbacGroupAccessAccountAttachment.CID = c.CID
+	rbacGroupAccessAccountAttachment.CID = c.cid()
 	rbacGroupAccessAccountAttachment.Action = "add_access_accounts_to_rbac_group"
 
 	return c.PostAPI(rbacGroupAccessAccountAttachment.Action, rbacGroupAccessAccountAttachment, BasicCheck)
@@ -26,7 +26,7 @@ func (c *Client) CreateRbacGroupAccessAccountAttachment(rbacGroupAccessAccountAt
 
 func (c *Client) GetRbacGroupAccessAccountAttachment(rbacGroupAccessAccountAttachment *RbacGroupAccessAccountAttachment) (*RbacGroupAccessAccountAttachment, error) {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "list_access_accounts_in_rbac_group",
 		"group_name": rbacGroupAccessAccountAttachment.GroupName,
 	}
@@ -54,7 +54,7 @@ func (c *Client) GetRbacGroupAccessAccountAttachment(rbacGroupAccessAccountAttac
 
 func (c *Client) DeleteRbacGroupAccessAccountAttachment(rbacGroupAccessAccountAttachment *RbacGroupAccessAccountAttachment) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "delete_access_accounts_from_rbac_group",
 		"group_name": rbacGroupAccessAccountAttachment.GroupName,
 		"accounts":   rbacGroupAccessAccountAttachment.AccessAccountName,
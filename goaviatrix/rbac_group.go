@@ -28,7 +28,7 @@ type RbacGroupListDetailsResp struct {
 }
 
 func (c *Client) CreatePermissionGroup(rbacGroup *RbacGroup) error {
-	rbacGroup.CID = c.CID
+	rbacGroup.CID = c.cid()
 	rbacGroup.Action = "add_permission_group"
 
 	return c.PostAPI(rbacGroup.Action, rbacGroup, BasicCheck)
@@ -36,7 +36,7 @@ func (c *Client) CreatePermissionGroup(rbacGroup *RbacGroup) error {
 
 func (c *Client) GetPermissionGroup(rbacGroup *RbacGroup) (*RbacGroup, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_permission_groups",
 	}
 
@@ -61,7 +61,7 @@ func (c *Client) GetPermissionGroup(rbacGroup *RbacGroup) (*RbacGroup, error) {
 
 func (c *Client) DeletePermissionGroup(rbacGroup *RbacGroup) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "delete_permission_group",
 		"group_name": rbacGroup.GroupName,
 	}
@@ -72,7 +72,7 @@ func (c *Client) DeletePermissionGroup(rbacGroup *RbacGroup) error {
 func (c *Client) EnableLocalLoginForRBACGroup(GroupName string) error {
 	data := map[string]string{
 		"action":     "enable_local_login",
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"group_name": GroupName,
 	}
 	return c.PostAPI("disable_local_login", data, BasicCheck)
@@ -81,7 +81,7 @@ func (c *Client) EnableLocalLoginForRBACGroup(GroupName string) error {
 func (c *Client) DisableLocalLoginForRBACGroup(GroupName string) error {
 	data := map[string]string{
 		"action":     "disable_local_login",
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"group_name": GroupName,
 	}
 	return c.PostAPI("disable_local_login", data, BasicCheck)
@@ -89,7 +89,7 @@ func (c *Client) DisableLocalLoginForRBACGroup(GroupName string) error {
 
 func (c *Client) GetPermissionGroupDetails(GroupName string) (*RbacGroupResponse, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_permission_group_details",
 	}
 
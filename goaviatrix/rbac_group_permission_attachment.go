@@ -25,7 +25,7 @@ type PermissionAttachmentInfo struct {
 }
 
 func (c *Client) CreateRbacGroupPermissionAttachment(rbacGroupPermissionAttachment *RbacGroupPermissionAttachment) error {
-	rbacGroupPermissionAttachment.CID = c.CID
+	rbacGroupPermissionAttachment.CID = c.cid()
 	rbacGroupPermissionAttachment.Action = "add_permissions_to_rbac_group"
 
 	return c.PostAPI(rbacGroupPermissionAttachment.Action, rbacGroupPermissionAttachment, BasicCheck)
@@ -33,7 +33,7 @@ func (c *Client) CreateRbacGroupPermissionAttachment(rbacGroupPermissionAttachme
 
 func (c *Client) GetRbacGroupPermissionAttachment(rbacGroupPermissionAttachment *RbacGroupPermissionAttachment) (*RbacGroupPermissionAttachment, error) {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "list_rbac_group_permissions",
 		"group_name": rbacGroupPermissionAttachment.GroupName,
 	}
@@ -61,7 +61,7 @@ func (c *Client) GetRbacGroupPermissionAttachment(rbacGroupPermissionAttachment
 
 func (c *Client) DeleteRbacGroupPermissionAttachment(rbacGroupPermissionAttachment *RbacGroupPermissionAttachment) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "delete_permissions_from_rbac_group",
 		"group_name":  rbacGroupPermissionAttachment.GroupName,
 		"permissions": rbacGroupPermissionAttachment.PermissionName,
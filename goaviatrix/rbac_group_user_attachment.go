@@ -18,7 +18,7 @@ type RbacGroupUserAttachmentListResp struct {
 }
 
 func (c *Client) CreateRbacGroupUserAttachment(rbacGroupUserAttachment *RbacGroupUserAttachment) error {
-	rbacGroupUserAttachment.CID = c.CID
+	rbacGroupUserAttachment.CID = c.cid()
 	rbacGroupUserAttachment.Action = "add_users_to_rbac_group"
 
 	return c.PostAPI(rbacGroupUserAttachment.Action, rbacGroupUserAttachment, BasicCheck)
@@ -26,7 +26,7 @@ func (c *Client) CreateRbacGroupUserAttachment(rbacGroupUserAttachment *RbacGrou
 
 func (c *Client) GetRbacGroupUserAttachment(rbacGroupUserAttachment *RbacGroupUserAttachment) (*RbacGroupUserAttachment, error) {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "list_users_in_rbac_group",
 		"group_name": rbacGroupUserAttachment.GroupName,
 	}
@@ -54,7 +54,7 @@ func (c *Client) GetRbacGroupUserAttachment(rbacGroupUserAttachment *RbacGroupUs
 
 func (c *Client) DeleteRbacGroupUserAttachment(rbacGroupUserAttachment *RbacGroupUserAttachment) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "delete_users_from_rbac_group",
 		"group_name": rbacGroupUserAttachment.GroupName,
 		"users":      rbacGroupUserAttachment.UserName,
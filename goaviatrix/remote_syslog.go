@@ -34,7 +34,7 @@ type RemoteSyslogResp struct {
 func (c *Client) EnableRemoteSyslog(r *RemoteSyslog) error {
 	params := map[string]string{
 		"action":               "enable_remote_syslog_logging",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"index":                strconv.Itoa(r.Index),
 		"name":                 r.Name,
 		"server":               r.Server,
@@ -82,7 +82,7 @@ func (c *Client) EnableRemoteSyslog(r *RemoteSyslog) error {
 func (c *Client) GetRemoteSyslogStatus(idx int) (*RemoteSyslogResp, error) {
 	params := map[string]string{
 		"action": "get_remote_syslog_logging_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"index":  strconv.Itoa(idx),
 	}
 
@@ -109,7 +109,7 @@ func (c *Client) GetRemoteSyslogStatus(idx int) (*RemoteSyslogResp, error) {
 func (c *Client) DisableRemoteSyslog(idx int) error {
 	params := map[string]string{
 		"action": "disable_remote_syslog_logging",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"index":  strconv.Itoa(idx),
 	}
 
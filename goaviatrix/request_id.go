@@ -0,0 +1,88 @@
+package goaviatrix
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader is the HTTP header carrying the ID generated for each request to the controller.
+// The ID is logged and included in error messages, to correlate failures with the controller logs.
+const RequestIDHeader = "X-Request-ID"
+
+// doWithRequestID sends the request with a new request ID and logs its outcome
+func (c *Client) doWithRequestID(req *http.Request, action string) (*http.Response, error) {
+	requestID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate request ID: %v", err)
+	}
+	req.Header.Set(RequestIDHeader, requestID)
+	if c.DebugHTTP {
+		logDebugRequest(req, requestID, action)
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	fields := log.Fields{
+		"request_id": requestID,
+		"action":     action,
+		"method":     req.Method,
+		"duration":   time.Since(start).String(),
+	}
+	if err != nil {
+		fields["err"] = err.Error()
+		log.WithFields(fields).Debug("HTTP request failed")
+		return resp, fmt.Errorf("%w (request ID %s)", err, requestID)
+	}
+	fields["status"] = resp.StatusCode
+	log.WithFields(fields).Debug("HTTP request completed")
+	if c.DebugHTTP {
+		logDebugResponse(resp, requestID, action)
+	}
+	return resp, nil
+}
+
+// withRequestID adds the ID of the request that resp answers to err. ErrNotFound is returned
+// unchanged, since callers compare it directly.
+func withRequestID(err error, resp *http.Response) error {
+	if err == nil || err == ErrNotFound || resp == nil || resp.Request == nil {
+		return err
+	}
+	requestID := resp.Request.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		return err
+	}
+	return fmt.Errorf("%w (request ID %s)", err, requestID)
+}
+
+// requestAction returns the API action of a request, read from the form data i or from the
+// query of a GET request's URL
+func requestAction(req *http.Request, i interface{}) string {
+	switch form := i.(type) {
+	case map[string]string:
+		return form["action"]
+	case map[string]interface{}:
+		if action, ok := form["action"].(string); ok {
+			return action
+		}
+		return ""
+	case nil:
+		return req.URL.Query().Get("action")
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("Action"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
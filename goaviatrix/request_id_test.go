@@ -0,0 +1,60 @@
+package goaviatrix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var requestIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get(RequestIDHeader))
+		respondJSON(`{"return":false,"reason":"device not found"}`)(w)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	err := client.DeregisterDevice(context.Background(), &Device{Name: "test-device"})
+	if err == nil {
+		t.Fatalf("DeregisterDevice() expected an error, got none")
+	}
+	if _, err := client.GetDeviceConnectionStatus(context.Background(), &Device{Name: "test-device"}); err == nil {
+		t.Fatalf("GetDeviceConnectionStatus() expected an error, got none")
+	}
+	if len(requestIDs) != 2 || requestIDs[0] == "" || requestIDs[0] == requestIDs[1] {
+		t.Fatalf("controller got request IDs %q, want 2 different IDs", requestIDs)
+	}
+	if !strings.Contains(err.Error(), "(request ID "+requestIDs[0]+")") {
+		t.Errorf("DeregisterDevice() error = %q, want it to contain the request ID %s", err.Error(), requestIDs[0])
+	}
+
+	checkNotFound := func(action, method, reason string, ret bool) error {
+		return ErrNotFound
+	}
+	if err := client.GetAPI(nil, "test_action", map[string]string{"action": "test_action"}, checkNotFound); err != ErrNotFound {
+		t.Errorf("GetAPI() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRequestAction(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://controller/v1/api?action=list_version_info&CID=cid", nil)
+	tests := []struct {
+		name string
+		form interface{}
+		want string
+	}{
+		{"string map", map[string]string{"action": "login"}, "login"},
+		{"interface map", map[string]interface{}{"action": "login"}, "login"},
+		{"struct", &Device{Action: "register_cloudwan_device"}, "register_cloudwan_device"},
+		{"get query", nil, "list_version_info"},
+		{"no action", 1, ""},
+	}
+	for _, tt := range tests {
+		if got := requestAction(req, tt.form); got != tt.want {
+			t.Errorf("requestAction(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
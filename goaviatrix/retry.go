@@ -0,0 +1,173 @@
+package goaviatrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultMaxRetries is the number of attempts made for a call that retries transient failures
+// when Client.MaxRetries is not set.
+const DefaultMaxRetries = 5
+
+// retryBackoff is the wait before the first retry, it is doubled after every failed try.
+// It is the default of Client.RetryBaseDelay.
+var retryBackoff = 500 * time.Millisecond
+
+// DefaultRetryMaxDelay is the longest wait between two tries when Client.RetryMaxDelay is not set
+const DefaultRetryMaxDelay = 30 * time.Second
+
+// SetRetrySeed makes the jitter of the waits between retries deterministic, e.g. for tests
+func (c *Client) SetRetrySeed(seed int64) {
+	c.retryRandMu.Lock()
+	defer c.retryRandMu.Unlock()
+	c.retryRand = rand.New(rand.NewSource(seed))
+}
+
+// retryDelay returns the wait before the retry after the given failed try, counting from 1. It uses full
+// jitter: a random duration up to the exponential backoff, RetryBaseDelay doubled after every failed try
+// and capped at RetryMaxDelay. Clients retrying at the same time so spread out their retries.
+func (c *Client) retryDelay(try int) time.Duration {
+	base, maxDelay := c.RetryBaseDelay, c.RetryMaxDelay
+	if base <= 0 {
+		base = retryBackoff
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	backoff := base
+	for i := 1; i < try && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > maxDelay {
+		backoff = maxDelay
+	}
+
+	c.retryRandMu.Lock()
+	defer c.retryRandMu.Unlock()
+	if c.retryRand == nil {
+		c.retryRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return time.Duration(c.retryRand.Int63n(int64(backoff) + 1))
+}
+
+// nonIdempotentActions are the actions that must not be sent again once the controller may have received
+// them, e.g. registering a device, which fails when repeated after the first request succeeded.
+var nonIdempotentActions = map[string]bool{
+	"register_cloudwan_device": true,
+}
+
+// PostAPIWithRetry behaves like PostAPI but retries transient failures with exponential backoff.
+// Transient failures are network errors, HTTP 5xx responses and responses where the controller
+// reports it is busy. Authentication and validation errors are returned immediately. Network errors
+// of nonIdempotentActions are only retried if the connection could not be established.
+func (c *Client) PostAPIWithRetry(action string, d interface{}, checkFunc CheckAPIResponseFunc) error {
+	return c.PostAPIWithRetryContext(context.Background(), action, d, checkFunc)
+}
+
+// PostAPIWithRetryContext is the context aware version of PostAPIWithRetry.
+func (c *Client) PostAPIWithRetryContext(ctx context.Context, action string, d interface{}, checkFunc CheckAPIResponseFunc) error {
+	return c.doWithRetry(ctx, action, checkFunc, func() (*http.Response, error) {
+		return c.PostContext(ctx, c.baseURL, d)
+	})
+}
+
+// PostFileAPIWithRetry behaves like PostFileAPI but retries transient failures with exponential backoff.
+// See PostAPIWithRetry for which failures are retried.
+func (c *Client) PostFileAPIWithRetry(params map[string]string, files []File, checkFunc CheckAPIResponseFunc) error {
+	return c.PostFileAPIWithRetryContext(context.Background(), params, files, checkFunc)
+}
+
+// PostFileAPIWithRetryContext is the context aware version of PostFileAPIWithRetry.
+func (c *Client) PostFileAPIWithRetryContext(ctx context.Context, params map[string]string, files []File, checkFunc CheckAPIResponseFunc) error {
+	if params["action"] == "" {
+		return fmt.Errorf("cannot PostFileAPIWithRetry without an 'action' in params map")
+	}
+	return c.doWithRetry(ctx, params["action"], checkFunc, func() (*http.Response, error) {
+		return c.postFileRefreshingCID(ctx, params, files)
+	})
+}
+
+// doWithRetry calls send until it succeeds, fails with a non transient error or the maximum number of
+// attempts is reached. The response of the last try is decoded and checked with checkFunc.
+func (c *Client) doWithRetry(ctx context.Context, action string, checkFunc CheckAPIResponseFunc, send func() (*http.Response, error)) error {
+	maxTries := c.MaxRetries
+	if maxTries <= 0 {
+		maxTries = DefaultMaxRetries
+	}
+
+	for try := 1; ; try++ {
+		retry, err := tryRequest(action, checkFunc, send)
+		if err == nil || !retry || try >= maxTries || ctx.Err() != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"try":    try,
+			"action": action,
+			"err":    err.Error(),
+		}).Warnf("HTTP POST request failed with a transient error, retrying")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.retryDelay(try)):
+		}
+	}
+}
+
+// tryRequest sends a single request and reports whether a failure is transient and worth retrying.
+func tryRequest(action string, checkFunc CheckAPIResponseFunc, send func() (*http.Response, error)) (bool, error) {
+	resp, err := send()
+	if resp != nil && resp.StatusCode >= http.StatusInternalServerError {
+		if resp.Body != nil {
+			defer resp.Body.Close()
+		}
+		// The controller may still explain the failure in the body
+		var data APIResp
+		if resp.Body != nil && json.NewDecoder(resp.Body).Decode(&data) == nil && data.Reason != "" {
+			return true, withRequestID(fmt.Errorf("HTTP POST %q failed with status: %s: %s", action, resp.Status, data.Reason), resp)
+		}
+		return true, withRequestID(fmt.Errorf("HTTP POST %q failed with status: %s", action, resp.Status), resp)
+	}
+	if err != nil {
+		// A nil response means the request never got an answer, e.g. connection refused or timed out.
+		// Retrying is pointless while the circuit breaker is open.
+		retry := resp == nil && !errors.Is(err, ErrControllerUnavailable) && (!nonIdempotentActions[action] || isDialError(err))
+		return retry, fmt.Errorf("HTTP POST %q failed: %w", action, err)
+	}
+
+	var b bytes.Buffer
+	_, err = b.ReadFrom(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		// the controller answered, so it may have carried out the action
+		return !nonIdempotentActions[action], fmt.Errorf("reading response body %q failed: %v", action, err)
+	}
+
+	var data APIResp
+	if err = json.NewDecoder(&b).Decode(&data); err != nil {
+		return false, fmt.Errorf("json Decode %q failed: %v\n Body: %s", action, err, b.String())
+	}
+
+	if err = checkFunc(action, "Post", data.Reason, data.Return); err != nil {
+		return !data.Return && strings.Contains(strings.ToLower(data.Reason), "busy"), withRequestID(categorizeStatus(err, resp), resp)
+	}
+	return false, nil
+}
+
+// isDialError returns true if err shows that the connection to the controller could not be established,
+// so the request was never sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
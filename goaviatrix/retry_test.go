@@ -0,0 +1,256 @@
+package goaviatrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a Client that sends all API calls to the given test server.
+func newTestClient(srv *httptest.Server) *Client {
+	return &Client{
+		HTTPClient: srv.Client(),
+		CID:        "test-cid",
+		baseURL:    srv.URL,
+	}
+}
+
+func TestRegisterDeviceRetry(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			"success",
+			[]func(w http.ResponseWriter){
+				respondJSON(`{"return":true,"results":"ok"}`),
+			},
+			1,
+			false,
+		},
+		{
+			"5xx then success",
+			[]func(w http.ResponseWriter){
+				respondStatus(http.StatusBadGateway),
+				respondStatus(http.StatusServiceUnavailable),
+				respondJSON(`{"return":true,"results":"ok"}`),
+			},
+			3,
+			false,
+		},
+		{
+			"controller busy then success",
+			[]func(w http.ResponseWriter){
+				respondJSON(`{"return":false,"reason":"Controller is busy, please try again later"}`),
+				respondJSON(`{"return":true,"results":"ok"}`),
+			},
+			2,
+			false,
+		},
+		{
+			"validation error is not retried",
+			[]func(w http.ResponseWriter){
+				respondJSON(`{"return":false,"reason":"device name already exists"}`),
+				respondJSON(`{"return":true,"results":"ok"}`),
+			},
+			1,
+			true,
+		},
+		{
+			"auth error is not retried",
+			[]func(w http.ResponseWriter){
+				respondStatus(http.StatusUnauthorized),
+				respondJSON(`{"return":true,"results":"ok"}`),
+			},
+			1,
+			true,
+		},
+		{
+			"dropped connection is not retried",
+			[]func(w http.ResponseWriter){
+				dropConnection,
+				respondJSON(`{"return":true,"results":"ok"}`),
+			},
+			1,
+			true,
+		},
+		{
+			"max attempts reached",
+			[]func(w http.ResponseWriter){
+				respondStatus(http.StatusInternalServerError),
+			},
+			DefaultMaxRetries,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the handler may still be running when a dropped connection fails the request
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := int(atomic.AddInt32(&calls, 1)) - 1
+				if i >= len(tt.responses) {
+					i = len(tt.responses) - 1
+				}
+				tt.responses[i](w)
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).RegisterDevice(context.Background(), &Device{Name: "test-device"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterDevice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if n := int(atomic.LoadInt32(&calls)); n != tt.wantCalls {
+				t.Errorf("RegisterDevice() made %d calls, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRegisterDeviceRetryMaxRetries(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = 500 * time.Millisecond }()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		respondStatus(http.StatusServiceUnavailable)(w)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.MaxRetries = 2
+	if err := client.RegisterDevice(context.Background(), &Device{Name: "test-device"}); err == nil {
+		t.Errorf("RegisterDevice() expected an error, got none")
+	}
+	if calls != 2 {
+		t.Errorf("RegisterDevice() made %d calls, want 2", calls)
+	}
+}
+
+// closeRecorder records whether a response body was closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestTryRequest(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "https://controller/v1/api", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	readErr := &url.Error{Op: "Post", URL: "https://controller/v1/api", Err: io.ErrUnexpectedEOF}
+	tests := []struct {
+		name      string
+		action    string
+		err       error
+		wantRetry bool
+	}{
+		{"network error", "list_cloudwan_devices_summary", readErr, true},
+		{"dial error", "list_cloudwan_devices_summary", dialErr, true},
+		{"network error of a non idempotent action", "register_cloudwan_device", readErr, false},
+		{"dial error of a non idempotent action", "register_cloudwan_device", dialErr, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, err := tryRequest(tt.action, BasicCheck, func() (*http.Response, error) {
+				return nil, tt.err
+			})
+			if err == nil {
+				t.Fatalf("tryRequest() expected an error, got none")
+			}
+			if retry != tt.wantRetry {
+				t.Errorf("tryRequest() retry = %v, want %v", retry, tt.wantRetry)
+			}
+		})
+	}
+
+	t.Run("5xx response body is closed", func(t *testing.T) {
+		body := &closeRecorder{Reader: strings.NewReader(`{"return":false,"reason":"internal error"}`)}
+		retry, err := tryRequest("register_cloudwan_device", BasicCheck, func() (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", Body: body}, nil
+		})
+		if err == nil || !retry {
+			t.Errorf("tryRequest() = %v, %v, want a transient error", retry, err)
+		}
+		if !body.closed {
+			t.Errorf("tryRequest() did not close the body of the 5xx response")
+		}
+	})
+}
+
+// dropConnection closes the connection without a response, as if it was lost after the request was sent
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		panic(err)
+	}
+	conn.Close()
+}
+
+func respondJSON(body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func respondStatus(status int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		http.Error(w, http.StatusText(status), status)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &Client{RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second}
+	client.SetRetrySeed(42)
+
+	var delays []time.Duration
+	for try := 1; try <= 10; try++ {
+		backoff := 100 * time.Millisecond << uint(try-1)
+		if backoff > time.Second {
+			backoff = time.Second
+		}
+		delay := client.retryDelay(try)
+		if delay < 0 || delay > backoff {
+			t.Errorf("retryDelay(%d) = %s, want between 0 and %s", try, delay, backoff)
+		}
+		delays = append(delays, delay)
+	}
+
+	// the same seed gives the same delays, e.g. to reproduce a test
+	other := &Client{RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second}
+	other.SetRetrySeed(42)
+	for try := 1; try <= 10; try++ {
+		if delay := other.retryDelay(try); delay != delays[try-1] {
+			t.Errorf("retryDelay(%d) with the same seed = %s, want %s", try, delay, delays[try-1])
+		}
+	}
+
+	// the delays are spread out instead of all being the full backoff
+	spread := false
+	for _, delay := range delays[1:] {
+		if delay != delays[0] {
+			spread = true
+		}
+	}
+	if !spread {
+		t.Errorf("retryDelay() returned the same delay %s for every try", delays[0])
+	}
+}
@@ -44,7 +44,7 @@ type SamlResp struct {
 }
 
 func (c *Client) CreateSamlEndpoint(samlEndpoint *SamlEndpoint) error {
-	samlEndpoint.CID = c.CID
+	samlEndpoint.CID = c.cid()
 	samlEndpoint.Action = "create_saml_endpoint"
 
 	return c.PostAPI(samlEndpoint.Action, samlEndpoint, BasicCheck)
@@ -52,7 +52,7 @@ func (c *Client) CreateSamlEndpoint(samlEndpoint *SamlEndpoint) error {
 
 func (c *Client) GetSamlEndpoint(samlEndpoint *SamlEndpoint) (*SamlEndpointInfo, error) {
 	form := map[string]string{
-		"CID":           c.CID,
+		"CID":           c.cid(),
 		"action":        "get_saml_endpoint_information",
 		"endpoint_name": samlEndpoint.EndPointName,
 	}
@@ -79,7 +79,7 @@ func (c *Client) GetSamlEndpoint(samlEndpoint *SamlEndpoint) (*SamlEndpointInfo,
 }
 
 func (c *Client) EditSamlEndpoint(samlEndpoint *SamlEndpoint) error {
-	samlEndpoint.CID = c.CID
+	samlEndpoint.CID = c.cid()
 	samlEndpoint.Action = "edit_saml_endpoint"
 
 	return c.PostAPI("edit_saml_endpoint", samlEndpoint, BasicCheck)
@@ -87,7 +87,7 @@ func (c *Client) EditSamlEndpoint(samlEndpoint *SamlEndpoint) error {
 
 func (c *Client) DeleteSamlEndpoint(samlEndpoint *SamlEndpoint) error {
 	form := map[string]string{
-		"CID":           c.CID,
+		"CID":           c.cid(),
 		"action":        "delete_saml_endpoint",
 		"endpoint_name": samlEndpoint.EndPointName,
 	}
@@ -67,7 +67,7 @@ type IntraDomainInspection struct {
 }
 
 func (c *Client) CreateSecurityDomain(securityDomain *SecurityDomain) error {
-	securityDomain.CID = c.CID
+	securityDomain.CID = c.cid()
 	securityDomain.Action = "add_route_domain"
 
 	return c.PostAPI(securityDomain.Action, securityDomain, BasicCheck)
@@ -75,7 +75,7 @@ func (c *Client) CreateSecurityDomain(securityDomain *SecurityDomain) error {
 
 func (c *Client) GetSecurityDomain(securityDomain *SecurityDomain) (string, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_route_domain_names",
 		"tgw_name": securityDomain.AwsTgwName,
 	}
@@ -105,7 +105,7 @@ func (c *Client) UpdateSecurityDomain(securityDomain *SecurityDomain) error {
 }
 
 func (c *Client) DeleteSecurityDomain(securityDomain *SecurityDomain) error {
-	securityDomain.CID = c.CID
+	securityDomain.CID = c.cid()
 	securityDomain.Action = "delete_route_domain"
 
 	return c.PostAPI(securityDomain.Action, securityDomain, BasicCheck)
@@ -113,7 +113,7 @@ func (c *Client) DeleteSecurityDomain(securityDomain *SecurityDomain) error {
 
 func (c *Client) CreateDomainConnection(awsTgw *AWSTgw, sourceDomain string, destinationDomain string) error {
 	form := map[string]string{
-		"CID":                           c.CID,
+		"CID":                           c.cid(),
 		"action":                        "add_connection_between_route_domains",
 		"account_name":                  awsTgw.AccountName,
 		"region":                        awsTgw.Region,
@@ -127,7 +127,7 @@ func (c *Client) CreateDomainConnection(awsTgw *AWSTgw, sourceDomain string, des
 
 func (c *Client) DeleteDomainConnection(awsTgw *AWSTgw, sourceDomain string, destinationDomain string) error {
 	form := map[string]string{
-		"CID":                           c.CID,
+		"CID":                           c.cid(),
 		"action":                        "delete_connection_between_route_domains",
 		"tgw_name":                      awsTgw.Name,
 		"source_route_domain_name":      sourceDomain,
@@ -157,7 +157,7 @@ func (c *Client) SecurityDomainRuleValidation(securityDomainRule *SecurityDomain
 func (c *Client) GetSecurityDomainDetails(ctx context.Context, domain *SecurityDomain) (*SecurityDomainDetails, error) {
 	params := map[string]string{
 		"action":            "list_tgw_security_domain_details",
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"tgw_name":          domain.AwsTgwName,
 		"route_domain_name": domain.Name,
 	}
@@ -185,7 +185,7 @@ func (c *Client) GetSecurityDomainDetails(ctx context.Context, domain *SecurityD
 func (c *Client) EnableIntraDomainInspection(ctx context.Context, intraDomainInspection *IntraDomainInspection) error {
 	params := map[string]string{
 		"action":               "enable_tgw_intra_domain_inspection",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"tgw_name":             intraDomainInspection.TgwName,
 		"route_domain_name":    intraDomainInspection.RouteDomainName,
 		"firewall_domain_name": intraDomainInspection.FirewallDomainName,
@@ -197,7 +197,7 @@ func (c *Client) EnableIntraDomainInspection(ctx context.Context, intraDomainIns
 func (c *Client) DisableIntraDomainInspection(ctx context.Context, intraDomainInspection *IntraDomainInspection) error {
 	params := map[string]string{
 		"action":            "disable_tgw_intra_domain_inspection",
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"tgw_name":          intraDomainInspection.TgwName,
 		"route_domain_name": intraDomainInspection.RouteDomainName,
 	}
@@ -208,7 +208,7 @@ func (c *Client) DisableIntraDomainInspection(ctx context.Context, intraDomainIn
 func (c *Client) GetIntraDomainInspectionStatus(ctx context.Context, intraDomainInspection *IntraDomainInspection) error {
 	params := map[string]string{
 		"action": "list_all_tgw_security_domains",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type DomainDetails struct {
@@ -19,7 +19,7 @@ func (c *Client) CreateSegmentationSecurityDomain(domain *SegmentationSecurityDo
 	action := "add_multi_cloud_security_domain"
 	data := map[string]interface{}{
 		"action":      action,
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"domain_name": domain.DomainName,
 	}
 	return c.PostAPI(action, data, BasicCheck)
@@ -29,7 +29,7 @@ func (c *Client) DeleteSegmentationSecurityDomain(domain *SegmentationSecurityDo
 	action := "delete_multi_cloud_security_domain"
 	data := map[string]interface{}{
 		"action":      action,
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"domain_name": domain.DomainName,
 	}
 	return c.PostAPI(action, data, BasicCheck)
@@ -37,7 +37,7 @@ func (c *Client) DeleteSegmentationSecurityDomain(domain *SegmentationSecurityDo
 
 func (c *Client) GetSegmentationSecurityDomain(domain *SegmentationSecurityDomain) (*SegmentationSecurityDomain, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_multi_cloud_security_domain_names",
 	}
 
@@ -65,7 +65,7 @@ func (c *Client) CreateSegmentationSecurityDomainConnectionPolicy(policy *Segmen
 	action := "connect_multi_cloud_security_domains"
 	data := map[string]interface{}{
 		"action":            action,
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"domain_name":       policy.Domain1.DomainName,
 		"other_domain_name": policy.Domain2.DomainName,
 	}
@@ -76,7 +76,7 @@ func (c *Client) DeleteSegmentationSecurityDomainConnectionPolicy(policy *Segmen
 	action := "disconnect_multi_cloud_security_domains"
 	data := map[string]interface{}{
 		"action":            action,
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"domain_name":       policy.Domain1.DomainName,
 		"other_domain_name": policy.Domain2.DomainName,
 	}
@@ -85,7 +85,7 @@ func (c *Client) DeleteSegmentationSecurityDomainConnectionPolicy(policy *Segmen
 
 func (c *Client) GetSegmentationSecurityDomainConnectionPolicy(policy *SegmentationSecurityDomainConnectionPolicy) (*SegmentationSecurityDomainConnectionPolicy, error) {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "list_multi_cloud_security_domain_connection_policy",
 		"domain_name": policy.Domain1.DomainName,
 	}
@@ -120,7 +120,7 @@ func (c *Client) CreateSegmentationSecurityDomainAssociation(association *Segmen
 	action := "associate_attachment_to_multi_cloud_security_domain"
 	data := map[string]interface{}{
 		"action":          action,
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"attachment_name": association.AttachmentName,
 		"domain_name":     association.SecurityDomainName,
 	}
@@ -131,7 +131,7 @@ func (c *Client) DeleteSegmentationSecurityDomainAssociation(association *Segmen
 	action := "disassociate_attachment_from_multi_cloud_security_domain"
 	data := map[string]interface{}{
 		"action":          action,
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"attachment_name": association.AttachmentName,
 		"domain_name":     association.SecurityDomainName,
 	}
@@ -140,7 +140,7 @@ func (c *Client) DeleteSegmentationSecurityDomainAssociation(association *Segmen
 
 func (c *Client) GetSegmentationSecurityDomainAssociation(association *SegmentationSecurityDomainAssociation) (*SegmentationSecurityDomainAssociation, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_multi_cloud_domain_attachments",
 	}
 
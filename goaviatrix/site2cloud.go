@@ -194,8 +194,8 @@ type AlgorithmInfo struct {
 
 func (c *Client) CreateSite2Cloud(site2cloud *Site2Cloud) error {
 	form := map[string]string{}
-	form["CID"] = c.CID
-	form["CID"] = c.CID
+	form["CID"] = c.cid()
+	form["CID"] = c.cid()
 	form["action"] = "add_site2cloud"
 	form["vpc_id"] = site2cloud.VpcID
 	form["connection_name"] = site2cloud.TunnelName
@@ -276,7 +276,7 @@ func (c *Client) CreateSite2Cloud(site2cloud *Site2Cloud) error {
 
 func (c *Client) GetSite2Cloud(site2cloud *Site2Cloud) (*Site2Cloud, error) {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "list_site2cloud_conn",
 		"connection_name": site2cloud.TunnelName,
 	}
@@ -299,7 +299,7 @@ func (c *Client) GetSite2Cloud(site2cloud *Site2Cloud) (*Site2Cloud, error) {
 
 func (c *Client) GetSite2CloudConnDetail(site2cloud *Site2Cloud) (*Site2Cloud, error) {
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "get_site2cloud_conn_detail",
 		"conn_name": site2cloud.TunnelName,
 		"vpc_id":    site2cloud.VpcID,
@@ -420,14 +420,14 @@ func (c *Client) GetSite2CloudConnDetail(site2cloud *Site2Cloud) (*Site2Cloud, e
 }
 
 func (c *Client) UpdateSite2Cloud(site2cloud *EditSite2Cloud) error {
-	site2cloud.CID = c.CID
+	site2cloud.CID = c.cid()
 	site2cloud.Action = "edit_site2cloud_conn"
 
 	return c.PostAPI(site2cloud.Action, site2cloud, BasicCheck)
 }
 
 func (c *Client) DeleteSite2Cloud(site2cloud *Site2Cloud) error {
-	site2cloud.CID = c.CID
+	site2cloud.CID = c.cid()
 	site2cloud.Action = "delete_site2cloud_connection"
 
 	return c.PostAPI(site2cloud.Action, site2cloud, BasicCheck)
@@ -435,7 +435,7 @@ func (c *Client) DeleteSite2Cloud(site2cloud *Site2Cloud) error {
 
 func (c *Client) EnableDeadPeerDetection(site2cloud *Site2Cloud) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "enable_dpd_config",
 		"vpc_id":          site2cloud.VpcID,
 		"connection_name": site2cloud.TunnelName,
@@ -446,7 +446,7 @@ func (c *Client) EnableDeadPeerDetection(site2cloud *Site2Cloud) error {
 
 func (c *Client) DisableDeadPeerDetection(site2cloud *Site2Cloud) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "disable_dpd_config",
 		"vpc_id":          site2cloud.VpcID,
 		"connection_name": site2cloud.TunnelName,
@@ -457,7 +457,7 @@ func (c *Client) DisableDeadPeerDetection(site2cloud *Site2Cloud) error {
 
 func (c *Client) EnableSite2cloudActiveActive(site2cloud *Site2Cloud) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "enable_site2cloud_active_active_ha",
 		"vpc_id":          site2cloud.VpcID,
 		"connection_name": site2cloud.TunnelName,
@@ -478,7 +478,7 @@ func (c *Client) EnableSite2cloudActiveActive(site2cloud *Site2Cloud) error {
 
 func (c *Client) DisableSite2cloudActiveActive(site2cloud *Site2Cloud) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "disable_site2cloud_active_active_ha",
 		"vpc_id":          site2cloud.VpcID,
 		"connection_name": site2cloud.TunnelName,
@@ -489,7 +489,7 @@ func (c *Client) DisableSite2cloudActiveActive(site2cloud *Site2Cloud) error {
 
 func (c *Client) EnableSpokeMappedSite2CloudForwarding(site2cloud *Site2Cloud) error {
 	data := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "enable_spoke_mapped_site2cloud_forwarding",
 		"vpc_id":          site2cloud.VpcID,
 		"connection_name": site2cloud.TunnelName,
@@ -499,7 +499,7 @@ func (c *Client) EnableSpokeMappedSite2CloudForwarding(site2cloud *Site2Cloud) e
 
 func (c *Client) DisableSpokeMappedSite2CloudForwarding(site2cloud *Site2Cloud) error {
 	data := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "disable_spoke_mapped_site2cloud_forwarding",
 		"vpc_id":          site2cloud.VpcID,
 		"connection_name": site2cloud.TunnelName,
@@ -509,7 +509,7 @@ func (c *Client) DisableSpokeMappedSite2CloudForwarding(site2cloud *Site2Cloud)
 
 func (c *Client) EnableSite2CloudEventTriggeredHA(vpcID, connectionName string) error {
 	data := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "enable_site2cloud_event_triggered_ha",
 		"vpc_id":          vpcID,
 		"connection_name": connectionName,
@@ -519,7 +519,7 @@ func (c *Client) EnableSite2CloudEventTriggeredHA(vpcID, connectionName string)
 
 func (c *Client) DisableSite2CloudEventTriggeredHA(vpcID, connectionName string) error {
 	data := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "disable_site2cloud_event_triggered_ha",
 		"vpc_id":          vpcID,
 		"connection_name": connectionName,
@@ -34,7 +34,7 @@ type SplitTunnelUnit struct {
 
 func (c *Client) GetSplitTunnel(splitTunnel *SplitTunnel) (*SplitTunnelUnit, error) {
 	form := map[string]string{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  "modify_split_tunnel",
 		"command": "get",
 		"vpc_id":  splitTunnel.VpcID,
@@ -53,7 +53,7 @@ func (c *Client) GetSplitTunnel(splitTunnel *SplitTunnel) (*SplitTunnelUnit, err
 
 func (c *Client) ModifySplitTunnel(splitTunnel *SplitTunnel) error {
 	form := map[string]string{
-		"CID":              c.CID,
+		"CID":              c.cid(),
 		"action":           "modify_split_tunnel",
 		"command":          "modify",
 		"vpc_id":           splitTunnel.VpcID,
@@ -26,7 +26,7 @@ func (c *Client) EnableSplunkLogging(r *SplunkLogging) error {
 	if r.UseConfigFile {
 		params := map[string]string{
 			"action":               "enable_splunk_logging",
-			"CID":                  c.CID,
+			"CID":                  c.cid(),
 			"custom_input_cfg":     r.CustomConfig,
 			"exclude_gateway_list": r.ExcludedGatewaysInput,
 		}
@@ -44,7 +44,7 @@ func (c *Client) EnableSplunkLogging(r *SplunkLogging) error {
 	} else {
 		params := map[string]string{
 			"action":               "enable_splunk_logging",
-			"CID":                  c.CID,
+			"CID":                  c.cid(),
 			"server_ip":            r.Server,
 			"port":                 strconv.Itoa(r.Port),
 			"custom_input_cfg":     r.CustomConfig,
@@ -58,7 +58,7 @@ func (c *Client) EnableSplunkLogging(r *SplunkLogging) error {
 func (c *Client) GetSplunkLoggingStatus() (*SplunkLoggingResp, error) {
 	params := map[string]string{
 		"action": "get_splunk_logging_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -84,7 +84,7 @@ func (c *Client) GetSplunkLoggingStatus() (*SplunkLoggingResp, error) {
 func (c *Client) DisableSplunkLogging() error {
 	params := map[string]string{
 		"action": "disable_splunk_logging",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	return c.PostAPI(params["action"], params, BasicCheck)
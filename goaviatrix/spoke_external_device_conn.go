@@ -11,7 +11,7 @@ func (c *Client) EditSpokeExternalDeviceConnASPathPrepend(externalDeviceConn *Ex
 		ConnectionName string `form:"connection_name"`
 		PrependASPath  string `form:"connection_as_path_prepend"`
 	}{
-		CID:            c.CID,
+		CID:            c.cid(),
 		Action:         action,
 		GatewayName:    externalDeviceConn.GwName,
 		ConnectionName: externalDeviceConn.ConnectionName,
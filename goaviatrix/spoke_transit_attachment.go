@@ -17,14 +17,14 @@ type SpokeTransitAttachment struct {
 
 func (c *Client) CreateSpokeTransitAttachment(spokeTransitAttachment *SpokeTransitAttachment) error {
 	action := "attach_spoke_to_transit_gw"
-	spokeTransitAttachment.CID = c.CID
+	spokeTransitAttachment.CID = c.cid()
 	spokeTransitAttachment.Action = action
 	return c.PostAPI(action, spokeTransitAttachment, BasicCheck)
 }
 
 func (c *Client) GetSpokeTransitAttachment(spokeTransitAttachment *SpokeTransitAttachment) (*SpokeTransitAttachment, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "list_vpc_by_name",
 		"vpc_name": spokeTransitAttachment.SpokeGwName,
 	}
@@ -60,7 +60,7 @@ func (c *Client) GetSpokeTransitAttachment(spokeTransitAttachment *SpokeTransitA
 
 func (c *Client) DeleteSpokeTransitAttachment(spokeTransitAttachment *SpokeTransitAttachment) error {
 	action := "detach_spoke_from_transit_gw"
-	spokeTransitAttachment.CID = c.CID
+	spokeTransitAttachment.CID = c.cid()
 	spokeTransitAttachment.Action = action
 	return c.PostAPI(action, spokeTransitAttachment, BasicCheck)
 }
@@ -89,7 +89,7 @@ type SpokeGatewayAdvancedConfigRespResult struct {
 }
 
 func (c *Client) LaunchSpokeVpc(spoke *SpokeVpc) error {
-	spoke.CID = c.CID
+	spoke.CID = c.cid()
 	spoke.Action = "create_spoke_gw"
 
 	return c.PostAPI(spoke.Action, spoke, BasicCheck)
@@ -97,7 +97,7 @@ func (c *Client) LaunchSpokeVpc(spoke *SpokeVpc) error {
 
 func (c *Client) SpokeJoinTransit(spoke *SpokeVpc) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "attach_spoke_to_transit_gw",
 		"spoke_gw":   spoke.GwName,
 		"transit_gw": spoke.TransitGateway,
@@ -108,7 +108,7 @@ func (c *Client) SpokeJoinTransit(spoke *SpokeVpc) error {
 
 func (c *Client) SpokeLeaveAllTransit(spoke *SpokeVpc) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "detach_spoke_from_transit_gw",
 		"spoke_gw": spoke.GwName,
 	}
@@ -129,7 +129,7 @@ func (c *Client) SpokeLeaveAllTransit(spoke *SpokeVpc) error {
 func (c *Client) SpokeLeaveTransit(spoke *SpokeVpc) error {
 	action := "detach_spoke_from_transit_gw"
 	data := map[string]interface{}{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     action,
 		"spoke_gw":   spoke.GwName,
 		"transit_gw": spoke.TransitGateway,
@@ -139,7 +139,7 @@ func (c *Client) SpokeLeaveTransit(spoke *SpokeVpc) error {
 
 func (c *Client) EnableHaSpokeVpc(spoke *SpokeVpc) error {
 	form := map[string]string{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  "enable_spoke_ha",
 		"gw_name": spoke.GwName,
 		"eip":     spoke.Eip,
@@ -166,7 +166,7 @@ func (c *Client) EnableHaSpokeVpc(spoke *SpokeVpc) error {
 }
 
 func (c *Client) EnableHaSpokeGateway(gateway *SpokeVpc) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "create_peering_ha_gateway"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
@@ -175,7 +175,7 @@ func (c *Client) EnableHaSpokeGateway(gateway *SpokeVpc) error {
 func (c *Client) EnableAutoAdvertiseS2CCidrs(gateway *Gateway) error {
 	form := map[string]string{
 		"action":       "enable_auto_advertise_s2c_cidrs",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 	}
 	return c.PostAPI(form["action"], form, BasicCheck)
@@ -184,7 +184,7 @@ func (c *Client) EnableAutoAdvertiseS2CCidrs(gateway *Gateway) error {
 func (c *Client) DisableAutoAdvertiseS2CCidrs(gateway *Gateway) error {
 	form := map[string]string{
 		"action":       "disable_auto_advertise_s2c_cidrs",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 	}
 	return c.PostAPI(form["action"], form, BasicCheck)
@@ -192,7 +192,7 @@ func (c *Client) DisableAutoAdvertiseS2CCidrs(gateway *Gateway) error {
 
 func (c *Client) GetSpokeGatewayAdvancedConfig(spokeGateway *SpokeVpc) (*SpokeGatewayAdvancedConfig, error) {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "list_aviatrix_spoke_advanced_config",
 		"gateway_name": spokeGateway.GwName,
 	}
@@ -246,7 +246,7 @@ func (c *Client) GetSpokeGatewayAdvancedConfig(spokeGateway *SpokeVpc) (*SpokeGa
 func (c *Client) EnableSpokeConnectionLearnedCIDRApproval(gwName, connName string) error {
 	data := map[string]string{
 		"action":          "enable_transit_connection_learned_cidrs_approval",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"gateway_name":    gwName,
 		"connection_name": connName,
 	}
@@ -256,7 +256,7 @@ func (c *Client) EnableSpokeConnectionLearnedCIDRApproval(gwName, connName strin
 func (c *Client) DisableSpokeConnectionLearnedCIDRApproval(gwName, connName string) error {
 	data := map[string]string{
 		"action":          "disable_transit_connection_learned_cidrs_approval",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"gateway_name":    gwName,
 		"connection_name": connName,
 	}
@@ -266,7 +266,7 @@ func (c *Client) DisableSpokeConnectionLearnedCIDRApproval(gwName, connName stri
 func (c *Client) UpdateSpokeConnectionPendingApprovedCidrs(gwName, connName string, approvedCidrs []string) error {
 	data := map[string]string{
 		"action":                            "update_transit_connection_pending_approved_cidrs",
-		"CID":                               c.CID,
+		"CID":                               c.cid(),
 		"gateway_name":                      gwName,
 		"connection_name":                   connName,
 		"connection_approved_learned_cidrs": strings.Join(approvedCidrs, ","),
@@ -277,7 +277,7 @@ func (c *Client) UpdateSpokeConnectionPendingApprovedCidrs(gwName, connName stri
 func (c *Client) EditSpokeConnectionBGPManualAdvertiseCIDRs(gwName, connName string, cidrs []string) error {
 	data := map[string]string{
 		"action":                                "edit_spoke_connection_bgp_manual_advertise_cidrs",
-		"CID":                                   c.CID,
+		"CID":                                   c.cid(),
 		"gateway_name":                          gwName,
 		"connection_name":                       connName,
 		"connection_bgp_manual_advertise_cidrs": strings.Join(cidrs, ","),
@@ -295,7 +295,7 @@ func (c *Client) SetBgpEcmpSpoke(spokeGateway *SpokeVpc, enabled bool) error {
 		Action      string `form:"action"`
 		GatewayName string `form:"gateway_name"`
 	}{
-		CID:         c.CID,
+		CID:         c.cid(),
 		Action:      action,
 		GatewayName: spokeGateway.GwName,
 	}, BasicCheck)
@@ -304,7 +304,7 @@ func (c *Client) SetBgpEcmpSpoke(spokeGateway *SpokeVpc, enabled bool) error {
 func (c *Client) EnableActiveStandbySpoke(spokeGateway *SpokeVpc) error {
 	action := "enable_active_standby"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": spokeGateway.GwName,
 	}
@@ -314,7 +314,7 @@ func (c *Client) EnableActiveStandbySpoke(spokeGateway *SpokeVpc) error {
 func (c *Client) DisableActiveStandbySpoke(spokeGateway *SpokeVpc) error {
 	action := "disable_active_standby"
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       action,
 		"gateway_name": spokeGateway.GwName,
 	}
@@ -330,7 +330,7 @@ func (c *Client) SetPrependASPathSpoke(spokeGateway *SpokeVpc, prependASPath []s
 		GatewayName   string `form:"gateway_name"`
 		PrependASPath string `form:"bgp_prepend_as_path"`
 	}{
-		CID:           c.CID,
+		CID:           c.cid(),
 		Action:        action,
 		Subaction:     subaction,
 		GatewayName:   spokeGateway.GwName,
@@ -346,7 +346,7 @@ func (c *Client) SetBgpPollingTimeSpoke(spokeGateway *SpokeVpc, newPollingTime s
 		GatewayName string `form:"gateway_name"`
 		PollingTime string `form:"bgp_polling_time"`
 	}{
-		CID:         c.CID,
+		CID:         c.cid(),
 		Action:      action,
 		GatewayName: spokeGateway.GwName,
 		PollingTime: newPollingTime,
@@ -355,7 +355,7 @@ func (c *Client) SetBgpPollingTimeSpoke(spokeGateway *SpokeVpc, newPollingTime s
 
 func (c *Client) SetSpokeBgpManualAdvertisedNetworks(spokeGateway *SpokeVpc) error {
 	form := map[string]string{
-		"CID":                              c.CID,
+		"CID":                              c.cid(),
 		"action":                           "edit_aviatrix_spoke_advanced_config",
 		"subaction":                        "bgp_manual_spoke",
 		"gateway_name":                     spokeGateway.GwName,
@@ -367,7 +367,7 @@ func (c *Client) SetSpokeBgpManualAdvertisedNetworks(spokeGateway *SpokeVpc) err
 
 func (c *Client) EnableSpokeLearnedCidrsApproval(gateway *SpokeVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_transit_learned_cidrs_approval",
 		"gateway_name": gateway.GwName,
 	}
@@ -377,7 +377,7 @@ func (c *Client) EnableSpokeLearnedCidrsApproval(gateway *SpokeVpc) error {
 
 func (c *Client) DisableSpokeLearnedCidrsApproval(gateway *SpokeVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_transit_learned_cidrs_approval",
 		"gateway_name": gateway.GwName,
 	}
@@ -387,7 +387,7 @@ func (c *Client) DisableSpokeLearnedCidrsApproval(gateway *SpokeVpc) error {
 
 func (c *Client) UpdateSpokePendingApprovedCidrs(gateway *SpokeVpc) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "update_transit_pending_approved_cidrs",
 		"gateway_name":           gateway.GwName,
 		"approved_learned_cidrs": strings.Join(gateway.ApprovedLearnedCidrs, ","),
@@ -404,7 +404,7 @@ func (c *Client) SetLocalASNumberSpoke(spokeGateway *SpokeVpc, localASNumber str
 		GatewayName   string `form:"gateway_name"`
 		LocalASNumber string `form:"local_as_num"`
 	}{
-		CID:           c.CID,
+		CID:           c.cid(),
 		Action:        action,
 		GatewayName:   spokeGateway.GwName,
 		LocalASNumber: localASNumber,
@@ -21,7 +21,7 @@ type SumologicForwarderResp struct {
 func (c *Client) EnableSumologicForwarder(r *SumologicForwarder) error {
 	params := map[string]string{
 		"action":               "enable_sumologic_logging",
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"access_id":            r.AccessID,
 		"access_key":           r.AccessKey,
 		"source_category":      r.SourceCategory,
@@ -35,7 +35,7 @@ func (c *Client) EnableSumologicForwarder(r *SumologicForwarder) error {
 func (c *Client) GetSumologicForwarderStatus() (*SumologicForwarderResp, error) {
 	params := map[string]string{
 		"action": "get_sumologic_logging_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	type Resp struct {
@@ -61,7 +61,7 @@ func (c *Client) GetSumologicForwarderStatus() (*SumologicForwarderResp, error)
 func (c *Client) DisableSumologicForwarder() error {
 	params := map[string]string{
 		"action": "disable_sumologic_logging",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 
 	return c.PostAPI(params["action"], params, BasicCheck)
@@ -1,7 +1,15 @@
 package goaviatrix
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // Tags simple struct to hold tag details
@@ -11,66 +19,866 @@ type Tags struct {
 	CloudType    int    `form:"cloud_type,omitempty"`
 	ResourceType string `form:"resource_type,omitempty"`
 	ResourceName string `form:"resource_name,omitempty"`
+	ResourceID   string `form:"-"` // cloud ID or ARN of the resource, used by GetTagsMap if the name is not found
 	TagList      string `form:"new_tag_list,omitempty"`
 	Tags         map[string]string
 	TagJson      string `form:"new_tag_json,omitempty"`
+	Scope        string `form:"-"` // TagScopeCloud or TagScopeController, defaults to TagScopeCloud when empty
+	ReplaceAll   bool   `form:"-"` // when set, UpdateTags deletes existing tags that are not in Tags
+	VerifyDelete bool   `form:"-"` // when set, DeleteTags reads the tags back and fails if deleted tags still exist
+	KeyPrefix    string `form:"-"` // when set, GetTagsMap only returns the tags whose keys start with it
+	// Version of the tags as read by GetTagsMap, if the controller supplies one. When set, UpdateTags
+	// sends it so that the controller rejects the update if the tags were changed since they were read.
+	Version string `form:"version,omitempty"`
+}
+
+// Tag scopes select which tags of a resource a Tags refers to. Cloud tags are applied to the resource in
+// the cloud provider, controller tags are metadata labels only stored on the controller, e.g. to label
+// managed CloudN (CaaG) appliances.
+const (
+	TagScopeCloud      = "cloud"
+	TagScopeController = "controller"
+)
+
+// IgnoreTagsConfig selects the tags that are managed outside of the provider, by exact key or key prefix
+type IgnoreTagsConfig struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+// Ignored returns true if the tag key is ignored
+func (c IgnoreTagsConfig) Ignored(key string) bool {
+	for _, ignored := range c.Keys {
+		if key == ignored {
+			return true
+		}
+	}
+	for _, prefix := range c.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the tags without the ignored ones. It returns tags itself if none is ignored.
+func (c IgnoreTagsConfig) filter(tags map[string]string) map[string]string {
+	ignoredAny := false
+	for key := range tags {
+		if c.Ignored(key) {
+			ignoredAny = true
+			break
+		}
+	}
+	if !ignoredAny {
+		return tags
+	}
+	filtered := make(map[string]string, len(tags))
+	for key, val := range tags {
+		if !c.Ignored(key) {
+			filtered[key] = val
+		}
+	}
+	return filtered
+}
+
+// dropIgnoredTags removes the ignored tags from tags.Tags and returns true if any was removed. The tags
+// to send are then generated from tags.Tags again, instead of the TagList or TagJson of the caller.
+func (c *Client) dropIgnoredTags(tags *Tags) bool {
+	filtered := c.IgnoreTags.filter(tags.Tags)
+	if len(filtered) == len(tags.Tags) {
+		return false
+	}
+	log.Infof("not changing ignored tags of %s %s", tags.ResourceType, tags.ResourceName)
+	tags.Tags = filtered
+	tags.TagList = ""
+	tags.TagJson = ""
+	return true
+}
+
+// tagsToSend returns the tags to write, from tags.Tags, or parsed from TagJson or TagList if Tags is empty
+func tagsToSend(tags *Tags) (map[string]string, error) {
+	if len(tags.Tags) > 0 {
+		return tags.Tags, nil
+	}
+	if tags.TagJson != "" {
+		var tagsMap map[string]string
+		if err := json.Unmarshal([]byte(tags.TagJson), &tagsMap); err != nil {
+			return nil, fmt.Errorf("could not parse tag json: %v", err)
+		}
+		return tagsMap, nil
+	}
+	if tags.TagList == "" {
+		return nil, nil
+	}
+	tagsMap := make(map[string]string)
+	for _, entry := range strings.Split(tags.TagList, ",") {
+		kv := strings.SplitN(entry, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid tag %q in tag list, expected 'key:value'", entry)
+		}
+		tagsMap[kv[0]] = strings.ReplaceAll(kv[1], "\\\\:", ":")
+	}
+	return tagsMap, nil
+}
+
+// addTagPrefix has the tags sent with the client's TagPrefix prepended to their keys, as TagJson.
+// tags.Tags keeps the keys without the prefix, as they are read by GetTagsMap.
+func (c *Client) addTagPrefix(tags *Tags) error {
+	if c.TagPrefix == "" {
+		return nil
+	}
+	tagsMap, err := tagsToSend(tags)
+	if err != nil || len(tagsMap) == 0 {
+		return err
+	}
+	b, err := json.Marshal(c.prefixTagKeys(tagsMap))
+	if err != nil {
+		return fmt.Errorf("could not marshal tags to json: %v", err)
+	}
+	tags.TagJson = string(b)
+	tags.TagList = ""
+	return nil
+}
+
+// PrepareCreateTags prepares the tags of a resource created with its tags, e.g. a gateway, so that they
+// are sent like UpdateTags sends them: ignored tags are dropped and the keys get the client's TagPrefix.
+// The resulting TagList or TagJson is to be copied into the create request.
+func (c *Client) PrepareCreateTags(tags *Tags) error {
+	if len(tags.Tags) == 0 {
+		tagsMap, err := tagsToSend(tags)
+		if err != nil {
+			return err
+		}
+		tags.Tags = tagsMap
+	}
+	if c.dropIgnoredTags(tags) && len(tags.Tags) == 0 {
+		return nil
+	}
+	if err := c.addTagPrefix(tags); err != nil {
+		return err
+	}
+	return tags.setTagJsonIfRequired()
+}
+
+// prefixTagKeys returns the tags with the client's TagPrefix prepended to their keys
+func (c *Client) prefixTagKeys(tagsMap map[string]string) map[string]string {
+	if c.TagPrefix == "" {
+		return tagsMap
+	}
+	prefixed := make(map[string]string, len(tagsMap))
+	for key, val := range tagsMap {
+		prefixed[c.TagPrefix+key] = val
+	}
+	return prefixed
+}
+
+// stripTagPrefix returns the tags whose keys start with the client's TagPrefix, without the prefix.
+// Tags without the prefix belong to other namespaces and are left out.
+func (c *Client) stripTagPrefix(tagsMap map[string]string) map[string]string {
+	if c.TagPrefix == "" || tagsMap == nil {
+		return tagsMap
+	}
+	stripped := make(map[string]string, len(tagsMap))
+	for key, val := range tagsMap {
+		if strings.HasPrefix(key, c.TagPrefix) {
+			stripped[strings.TrimPrefix(key, c.TagPrefix)] = val
+		}
+	}
+	return stripped
+}
+
+// controllerTagActions maps the actions on cloud tags to the actions on controller tags
+var controllerTagActions = map[string]string{
+	"add_resource_tags":    "add_resource_labels",
+	"list_resource_tags":   "list_resource_labels",
+	"delete_resource_tag":  "delete_resource_label",
+	"update_resource_tags": "update_resource_labels",
+}
+
+// action returns the controller action for the scope of the tags, given the action on cloud tags
+func (tags *Tags) action(cloudAction string) string {
+	if tags.Scope == TagScopeController {
+		return controllerTagActions[cloudAction]
+	}
+	return cloudAction
+}
+
+// scope returns the scope of the tags, TagScopeCloud if none is set
+func (tags *Tags) scope() string {
+	if tags.Scope == "" {
+		return TagScopeCloud
+	}
+	return tags.Scope
+}
+
+// TagAPIResult holds the tags of a single resource as returned by ListAllTags
+type TagAPIResult struct {
+	UsrTags map[string]string `json:"usr_tags"`
 }
 
 type TagAPIResp struct {
 	Return  bool                         `json:"return"`
 	Results map[string]map[string]string `json:"results"`
 	Reason  string                       `json:"reason"`
+	Version string                       `json:"version"` // not returned by all controller versions
+}
+
+// maxTagUpdateConflicts is the number of times UpdateTags writes tags that the controller rejects
+// because they were changed concurrently, before giving up
+const maxTagUpdateConflicts = 3
+
+// tagListDelimiters are the characters the controller splits a new_tag_list on
+const tagListDelimiters = ",:"
+
+// setTagJsonIfRequired marshals Tags into TagJson when no TagList was given, or when a key or value
+// contains a character that cannot be represented in the new_tag_list format. TagJson is left
+// untouched if already set.
+func (tags *Tags) setTagJsonIfRequired() error {
+	if tags.TagJson != "" || len(tags.Tags) == 0 {
+		return nil
+	}
+	useJson := tags.TagList == ""
+	for key, val := range tags.Tags {
+		if strings.ContainsAny(key, tagListDelimiters) || strings.ContainsAny(val, tagListDelimiters) {
+			useJson = true
+		}
+	}
+	if !useJson {
+		return nil
+	}
+
+	b, err := json.Marshal(tags.Tags)
+	if err != nil {
+		return fmt.Errorf("could not marshal tags to json: %v", err)
+	}
+	tags.TagJson = string(b)
+	tags.TagList = ""
+	return nil
 }
 
 func (c *Client) AddTags(tags *Tags) error {
-	tags.CID = c.CID
-	tags.Action = "add_resource_tags"
+	defer c.invalidateTagCache(tags)
+
+	if c.dropIgnoredTags(tags) && len(tags.Tags) == 0 {
+		return nil
+	}
+	if err := c.addTagPrefix(tags); err != nil {
+		return err
+	}
+	if err := tags.setTagJsonIfRequired(); err != nil {
+		return err
+	}
+
+	tags.CID = c.cid()
+	tags.Action = tags.action("add_resource_tags")
 
 	return c.PostAPI(tags.Action, tags, BasicCheck)
 }
 
+// GetTags returns the tags of a resource as a list of 'key:value' strings. Like GetTagsMap, it returns
+// ErrNotFound if the resource does not exist, and no error for a resource without tags, and only returns
+// the tags with KeyPrefix if set.
+// Prefer GetTagsMap, as the list format is ambiguous when a key or value contains a colon.
 func (c *Client) GetTags(tags *Tags) ([]string, error) {
+	tagsMap, err := c.GetTagsMap(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagList []string
+	for key, val := range tagsMap {
+		tagStr := key + ":" + val
+		tagList = append(tagList, tagStr)
+	}
+
+	return tagList, nil
+}
+
+// GetGatewayTags returns the tags of a gateway with the tag prefix and the ignored tags applied, as they
+// are to the tags written. The tags come from the gateway details, and only if the details do not include
+// them, e.g. from some controller versions, from the tag API.
+func (c *Client) GetGatewayTags(gateway *Gateway) (map[string]string, error) {
+	if gateway.Tags != nil {
+		return c.IgnoreTags.filter(c.stripTagPrefix(gateway.Tags)), nil
+	}
+	return c.GetTagsMap(&Tags{
+		CloudType:    gateway.CloudType,
+		ResourceType: "gw",
+		ResourceName: gateway.GwName,
+	})
+}
+
+// tagCacheKey identifies the resource whose tags are cached by GetTagsMap
+type tagCacheKey struct {
+	cloudType    int
+	resourceType string
+	resourceName string
+	scope        string
+	keyPrefix    string
+}
+
+func newTagCacheKey(tags *Tags) tagCacheKey {
+	return tagCacheKey{
+		cloudType:    tags.CloudType,
+		resourceType: tags.ResourceType,
+		resourceName: tags.ResourceName,
+		scope:        tags.scope(),
+		keyPrefix:    tags.KeyPrefix,
+	}
+}
+
+// tagCacheEntry holds the cached tags of a resource and their version, if the controller supplies one
+type tagCacheEntry struct {
+	tags    map[string]string
+	version string
+}
+
+// cachedTags returns a copy of the cached tags of the resource and their version, if any
+func (c *Client) cachedTags(tags *Tags) (map[string]string, string, bool) {
+	c.tagCacheMu.Lock()
+	defer c.tagCacheMu.Unlock()
+
+	cached, ok := c.tagCache[newTagCacheKey(tags)]
+	if !ok || cached.tags == nil {
+		return nil, cached.version, ok
+	}
+	tagsMap := make(map[string]string, len(cached.tags))
+	for key, val := range cached.tags {
+		tagsMap[key] = val
+	}
+	return tagsMap, cached.version, true
+}
+
+func (c *Client) cacheTags(tags *Tags, tagsMap map[string]string, version string) {
+	cached := tagCacheEntry{version: version}
+	if tagsMap != nil {
+		cached.tags = make(map[string]string, len(tagsMap))
+		for key, val := range tagsMap {
+			cached.tags[key] = val
+		}
+	}
+
+	c.tagCacheMu.Lock()
+	defer c.tagCacheMu.Unlock()
+	if c.tagCache == nil {
+		c.tagCache = make(map[tagCacheKey]tagCacheEntry)
+	}
+	c.tagCache[newTagCacheKey(tags)] = cached
+}
+
+// invalidateTagCache removes the cached tags of the resource, for any key prefix, after its tags were changed
+func (c *Client) invalidateTagCache(tags *Tags) {
+	invalidated := newTagCacheKey(tags)
+	c.tagCacheMu.Lock()
+	defer c.tagCacheMu.Unlock()
+	for key := range c.tagCache {
+		if key.cloudType == invalidated.cloudType && key.resourceType == invalidated.resourceType &&
+			key.resourceName == invalidated.resourceName && key.scope == invalidated.scope {
+			delete(c.tagCache, key)
+		}
+	}
+}
+
+// invalidateTagCacheByName removes the cached tags of all resources with the given name, after the
+// resource was deleted. A resource created later with the same name must not get its tags.
+func (c *Client) invalidateTagCacheByName(resourceName string) {
+	c.tagCacheMu.Lock()
+	defer c.tagCacheMu.Unlock()
+	for key := range c.tagCache {
+		if key.resourceName == resourceName {
+			delete(c.tagCache, key)
+		}
+	}
+}
+
+// GetTagsMap returns the tags of a resource as a map of key to value. The map is also stored in tags.Tags,
+// and the version of the tags in tags.Version if the controller supplies one.
+// The resource is looked up by ResourceName. If it is not found by name, or no name is given, and
+// ResourceID is set, it is looked up by its cloud ID or ARN instead, e.g. for imported resources.
+// It returns ErrNotFound if the controller reports that the resource does not exist, and a nil map
+// without error if the resource exists but has no tags.
+// If KeyPrefix is set, only the tags whose keys start with it are returned. The controller is asked to
+// filter them, and they are filtered again on the client for controllers that do not support it.
+// Results of lookups by name are cached on the client until the tags of the resource are changed through
+// the client, so resources sharing a client do not repeat the same call within a run. Set DisableTagCache
+// to always query the controller.
+func (c *Client) GetTagsMap(tags *Tags) (map[string]string, error) {
+	if tags.ResourceName != "" {
+		tagsMap, err := c.getTagsMapByName(tags)
+		if err != ErrNotFound || tags.ResourceID == "" {
+			return tagsMap, err
+		}
+		log.Infof("resource %s of type %s not found by name, looking up its tags by ID %s", tags.ResourceName, tags.ResourceType, tags.ResourceID)
+	}
+	if tags.ResourceID == "" {
+		return nil, fmt.Errorf("resource name or ID is required to get tags")
+	}
+
+	tagsMap, version, err := c.listResourceTags(tags, "resource_id", tags.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if tagsMap != nil {
+		tags.Tags = tagsMap
+	}
+	tags.Version = version
+	return tagsMap, nil
+}
+
+// getTagsMapByName returns the tags of the resource named ResourceName, using the tag cache
+func (c *Client) getTagsMapByName(tags *Tags) (map[string]string, error) {
+	if !c.DisableTagCache {
+		if tagsMap, version, ok := c.cachedTags(tags); ok {
+			if tagsMap != nil {
+				tags.Tags = tagsMap
+			}
+			tags.Version = version
+			return tagsMap, nil
+		}
+	}
+
+	tagsMap, version, err := c.listResourceTags(tags, "resource_name", tags.ResourceName)
+	if err != nil {
+		return nil, err
+	}
+	if !c.DisableTagCache {
+		c.cacheTags(tags, tagsMap, version)
+	}
+	if tagsMap != nil {
+		tags.Tags = tagsMap
+	}
+	tags.Version = version
+	return tagsMap, nil
+}
+
+// listResourceTags queries the controller for the tags and their version of the resource identified by
+// the given parameter, resource_name or resource_id
+func (c *Client) listResourceTags(tags *Tags, key, value string) (map[string]string, string, error) {
 	data := map[string]string{
-		"action":        "list_resource_tags",
-		"CID":           c.CID,
-		"cloud_type":    strconv.Itoa(tags.CloudType),
+		"action":        tags.action("list_resource_tags"),
+		"CID":           c.cid(),
 		"resource_type": tags.ResourceType,
-		"resource_name": tags.ResourceName,
+		key:             value,
+	}
+	if tags.CloudType != 0 {
+		data["cloud_type"] = strconv.Itoa(tags.CloudType)
+	}
+	if tags.KeyPrefix != "" {
+		// the controller stores the keys with the client's tag prefix
+		data["key_prefix"] = c.TagPrefix + tags.KeyPrefix
+	}
+	checkFunc := func(act, method, reason string, ret bool) error {
+		if !ret {
+			if isResourceNotFoundReason(reason, value) {
+				return ErrNotFound
+			}
+			return newAPIError(act, method, reason)
+		}
+		return nil
 	}
 	var resp TagAPIResp
-	err := c.GetAPI(&resp, data["action"], data, BasicCheck)
+	err := c.GetAPI(&resp, data["action"], data, checkFunc)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
+	tagsMap := c.IgnoreTags.filter(c.stripTagPrefix(resp.Results["usr_tags"]))
+	return filterTagsByKeyPrefix(tagsMap, tags.KeyPrefix), resp.Version, nil
+}
 
-	var tagList []string
-	if tagsMap, ok := resp.Results["usr_tags"]; ok {
-		tags.Tags = tagsMap
-		for key, val := range tagsMap {
-			tagStr := key + ":" + val
-			tagList = append(tagList, tagStr)
+// filterTagsByKeyPrefix returns the tags whose keys start with keyPrefix. It returns tags itself if
+// keyPrefix is empty, and nil if no key matches.
+func filterTagsByKeyPrefix(tags map[string]string, keyPrefix string) map[string]string {
+	if keyPrefix == "" {
+		return tags
+	}
+	var filtered map[string]string
+	for key, val := range tags {
+		if strings.HasPrefix(key, keyPrefix) {
+			if filtered == nil {
+				filtered = make(map[string]string)
+			}
+			filtered[key] = val
 		}
 	}
+	return filtered
+}
 
-	return tagList, nil
+// resourceNotFoundReasonRegexp matches the reason the controller gives for rejecting a tag request
+// because the tagged resource does not exist, e.g. "Gateway gw-1 does not exist."
+var resourceNotFoundReasonRegexp = regexp.MustCompile(`(?i)^(?:resource|gateway|device) (\S+) does not exist\.?$`)
+
+// isResourceNotFoundReason returns true if the controller rejected a tag request because the tagged
+// resource, identified by its name or ID, does not exist. Other reasons, e.g. about a missing account,
+// do not match.
+func isResourceNotFoundReason(reason, resource string) bool {
+	match := resourceNotFoundReasonRegexp.FindStringSubmatch(strings.TrimSpace(reason))
+	return match != nil && strings.EqualFold(match[1], resource)
+}
+
+// ListAllTags returns the tags of all resources of the given cloud type and resource type,
+// as a map of resource name to the tags of that resource. Controllers that paginate the result are
+// asked for every page. Only cloud tags are listed.
+func (c *Client) ListAllTags(cloudType int, resourceType string) (map[string]map[string]string, error) {
+	data := map[string]string{
+		"action":        "list_all_resource_tags",
+		"CID":           c.cid(),
+		"cloud_type":    strconv.Itoa(cloudType),
+		"resource_type": resourceType,
+	}
+	allTags := make(map[string]map[string]string)
+	err := listAllPages(data, func(pageData map[string]string) (*Pagination, error) {
+		var resp struct {
+			Return     bool                    `json:"return"`
+			Results    map[string]TagAPIResult `json:"results"`
+			Reason     string                  `json:"reason"`
+			Pagination *Pagination             `json:"pagination"`
+		}
+		if err := c.GetAPI(&resp, pageData["action"], pageData, BasicCheck); err != nil {
+			return nil, err
+		}
+		for resourceName, result := range resp.Results {
+			allTags[resourceName] = c.IgnoreTags.filter(c.stripTagPrefix(result.UsrTags))
+		}
+		return resp.Pagination, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allTags, nil
 }
 
+// DeleteTags deletes the comma separated tags in TagList from the resource.
+// Use DeleteTagsByKeys if a key may contain a comma.
 func (c *Client) DeleteTags(tags *Tags) error {
+	return c.DeleteTagsByKeys(tags, strings.Split(tags.TagList, ","))
+}
+
+// DeleteTagsByKeys deletes the given tag keys from the resource. The keys are sent as a comma separated
+// list when possible, or as a JSON array when any key contains a comma, or a delimiter once prefixed
+// with the client's TagPrefix.
+// The controller silently ignores tags it does not find, e.g. because of a wrong resource type. If
+// VerifyDelete is set, the tags are read back after deleting them and an error is returned if any of
+// them still exists. Nothing is sent without any non-empty key.
+func (c *Client) DeleteTagsByKeys(tags *Tags, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	defer c.invalidateTagCache(tags)
+
+	var managedKeys []string
+	for _, key := range keys {
+		// empty keys come from splitting an empty TagList, keys may also be 'key:value' pairs
+		if key != "" && !c.IgnoreTags.Ignored(key) && !c.IgnoreTags.Ignored(strings.SplitN(key, ":", 2)[0]) {
+			managedKeys = append(managedKeys, key)
+		}
+	}
+	if len(managedKeys) == 0 {
+		return nil
+	}
+	keys = managedKeys
+	sentKeys := keys
+	if c.TagPrefix != "" {
+		// only the keys are sent, a 'key:value' pair would be split on a colon in the prefix
+		sentKeys = make([]string, len(keys))
+		for i, key := range keys {
+			sentKeys[i] = c.TagPrefix + strings.SplitN(key, ":", 2)[0]
+		}
+	}
+
 	params := map[string]string{
-		"action":        "delete_resource_tag",
-		"CID":           c.CID,
-		"cloud_type":    strconv.Itoa(tags.CloudType),
-		"del_tag_list":  tags.TagList,
+		"action":        tags.action("delete_resource_tag"),
+		"CID":           c.cid(),
 		"resource_name": tags.ResourceName,
 		"resource_type": tags.ResourceType,
 	}
+	if tags.CloudType != 0 {
+		params["cloud_type"] = strconv.Itoa(tags.CloudType)
+	}
+
+	useJson := false
+	for _, key := range sentKeys {
+		if strings.Contains(key, ",") || (c.TagPrefix != "" && strings.ContainsAny(key, tagListDelimiters)) {
+			useJson = true
+			break
+		}
+	}
+	if useJson {
+		b, err := json.Marshal(sentKeys)
+		if err != nil {
+			return fmt.Errorf("could not marshal tag keys to json: %v", err)
+		}
+		params["del_tag_json"] = string(b)
+	} else {
+		params["del_tag_list"] = strings.Join(sentKeys, ",")
+	}
+
+	if err := c.PostAPI(params["action"], params, BasicCheck); err != nil {
+		return err
+	}
+	if !tags.VerifyDelete {
+		return nil
+	}
+
+	current, _, err := c.listResourceTags(tags, "resource_name", tags.ResourceName)
+	if err != nil {
+		return fmt.Errorf("could not read tags of %s %s to verify deleting them: %w", tags.ResourceType, tags.ResourceName, err)
+	}
+	if remaining := remainingTags(current, keys); len(remaining) > 0 {
+		return fmt.Errorf("tags %s of %s %s still exist after deleting them, check that the resource type is correct",
+			strings.Join(remaining, ", "), tags.ResourceType, tags.ResourceName)
+	}
+	return nil
+}
 
-	return c.PostAPI(params["action"], params, BasicCheck)
+// remainingTags returns the sorted keys of the current tags that match any of the deleted tags, which
+// are either keys or 'key:value' pairs
+func remainingTags(current map[string]string, deleted []string) []string {
+	var remaining []string
+	for _, entry := range deleted {
+		if _, ok := current[entry]; ok {
+			remaining = append(remaining, entry)
+			continue
+		}
+		if kv := strings.SplitN(entry, ":", 2); len(kv) == 2 {
+			if val, ok := current[kv[0]]; ok && val == strings.ReplaceAll(kv[1], "\\\\:", ":") {
+				remaining = append(remaining, kv[0])
+			}
+		}
+	}
+	sort.Strings(remaining)
+	return remaining
 }
 
+// UpdateTags updates the tags of a resource. By default the given tags are added to the existing ones.
+// If ReplaceAll is set, existing tags whose keys are not in Tags are deleted first, so that the
+// resource ends up with exactly the given tags.
+//
+// If Version is set, the controller rejects the update if the tags were changed concurrently since they
+// were read. The current tags are then read again and the update is retried, up to maxTagUpdateConflicts
+// times. Controllers that do not supply versions can not detect concurrent updates, so the tags are read
+// back after writing them and a warning is logged if another client overwrote the update.
+//
+// Tags ignored by the client's IgnoreTags are neither written nor deleted. If the client has a TagPrefix,
+// it is prepended to the keys written.
 func (c *Client) UpdateTags(tags *Tags) error {
-	tags.CID = c.CID
-	tags.Action = "update_resource_tags"
+	if c.dropIgnoredTags(tags) && len(tags.Tags) == 0 {
+		if !tags.ReplaceAll {
+			return nil
+		}
+		// only the deletion of the other tags is left
+		c.invalidateTagCache(tags)
+		defer c.invalidateTagCache(tags)
+		return c.deleteTagsNotIn(tags)
+	}
+	if err := c.addTagPrefix(tags); err != nil {
+		return err
+	}
+	if err := tags.setTagJsonIfRequired(); err != nil {
+		return err
+	}
+
+	locked := tags.Version != ""
+	err := c.updateTags(tags)
+	for conflicts := 1; errors.Is(err, ErrConflict) && conflicts < maxTagUpdateConflicts; conflicts++ {
+		log.Warnf("tags of %s %s were changed concurrently, reading them again before updating (attempt %d of %d)",
+			tags.ResourceType, tags.ResourceName, conflicts+1, maxTagUpdateConflicts)
+		// only the version is needed, the update itself does not depend on the current tags
+		_, version, readErr := c.listResourceTags(tags, "resource_name", tags.ResourceName)
+		if readErr != nil {
+			return fmt.Errorf("could not read tags again after a concurrent update: %w", readErr)
+		}
+		tags.Version = version
+		err = c.updateTags(tags)
+	}
+	c.invalidateTagCache(tags)
+	if err != nil || locked {
+		return err
+	}
+
+	c.checkTagsUpdated(tags)
+	return nil
+}
+
+// updateTags sends a single update of the tags of a resource
+func (c *Client) updateTags(tags *Tags) error {
+	if tags.ReplaceAll {
+		// read the current tags from the controller, not from the cache
+		c.invalidateTagCache(tags)
+		if err := c.deleteTagsNotIn(tags); err != nil {
+			return err
+		}
+	}
+
+	tags.CID = c.cid()
+	tags.Action = tags.action("update_resource_tags")
 
 	return c.PostAPI(tags.Action, tags, BasicCheck)
 }
+
+// checkTagsUpdated reads back the tags of a resource after an update without a version and logs a
+// warning if they do not match the update, e.g. because a parallel apply updated them at the same time.
+// The tags read are cached, so that reading the resource after the update does not query them again.
+func (c *Client) checkTagsUpdated(tags *Tags) {
+	if len(tags.Tags) == 0 {
+		return
+	}
+	current := &Tags{
+		CloudType:    tags.CloudType,
+		ResourceType: tags.ResourceType,
+		ResourceName: tags.ResourceName,
+		Scope:        tags.Scope,
+	}
+	currentMap, err := c.getTagsMapByName(current)
+	if err != nil {
+		log.Warnf("could not read tags of %s %s to check the update: %v", tags.ResourceType, tags.ResourceName, err)
+		return
+	}
+	if tagsUpdateLost(tags.Tags, currentMap, tags.ReplaceAll) {
+		log.Warnf("tags of %s %s do not match the update after writing them, they were probably changed "+
+			"concurrently by another client: updated to %v, found %v", tags.ResourceType, tags.ResourceName, tags.Tags, currentMap)
+	}
+}
+
+// tagsUpdateLost returns true if the current tags of a resource do not contain the updated tags, or,
+// if replaceAll is set, are not exactly the updated tags
+func tagsUpdateLost(updated, current map[string]string, replaceAll bool) bool {
+	if replaceAll && len(updated) != len(current) {
+		return true
+	}
+	for key, val := range updated {
+		if currentVal, ok := current[key]; !ok || currentVal != val {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteTagsNotIn deletes the tags of the resource whose keys are not in tags.Tags
+func (c *Client) deleteTagsNotIn(tags *Tags) error {
+	existing := &Tags{
+		CloudType:    tags.CloudType,
+		ResourceType: tags.ResourceType,
+		ResourceName: tags.ResourceName,
+		Scope:        tags.Scope,
+	}
+	if _, err := c.GetTagsMap(existing); err != nil {
+		return fmt.Errorf("could not get existing tags: %w", err)
+	}
+
+	var removed []string
+	for key := range existing.Tags {
+		if _, ok := tags.Tags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	sort.Strings(removed)
+
+	// the keys are passed as is, so keys with commas are sent as a JSON array instead of being split
+	if err := c.DeleteTagsByKeys(existing, removed); err != nil {
+		return fmt.Errorf("could not delete removed tags: %w", err)
+	}
+	return nil
+}
+
+// BulkTagError is returned by AddTagsBulk when tagging some of the resources failed
+type BulkTagError struct {
+	Errors map[string]error // resource name to the error tagging it
+}
+
+// FailedResourceNames returns the sorted names of the resources that could not be tagged
+func (e *BulkTagError) FailedResourceNames() []string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *BulkTagError) Error() string {
+	var failures []string
+	for _, name := range e.FailedResourceNames() {
+		failures = append(failures, fmt.Sprintf("%s: %v", name, e.Errors[name]))
+	}
+	return fmt.Sprintf("could not add tags to %d resource(s): %s", len(failures), strings.Join(failures, "; "))
+}
+
+// isUnsupportedActionError returns true if the controller rejected a request because it does not
+// know the action, e.g. because it runs an older version
+func isUnsupportedActionError(err error) bool {
+	reason := strings.ToLower(err.Error())
+	return strings.Contains(reason, "valid action required") || strings.Contains(reason, "unknown action")
+}
+
+// AddTagsBulk adds the same tags to all the named resources of the given cloud and resource type in a
+// single call. Controllers that do not support adding tags to multiple resources at once are sent one
+// AddTags call per resource instead. If any resource could not be tagged, a *BulkTagError naming the
+// failed resources is returned, so that only those need to be retried. Only cloud tags are added.
+// Like AddTags, ignored tags are dropped and the keys get the client's TagPrefix.
+func (c *Client) AddTagsBulk(cloudType int, resourceType string, resourceNames []string, tags map[string]string) error {
+	if len(resourceNames) == 0 {
+		return nil
+	}
+	defer func() {
+		for _, name := range resourceNames {
+			c.invalidateTagCache(&Tags{CloudType: cloudType, ResourceType: resourceType, ResourceName: name})
+		}
+	}()
+
+	bulkTags := &Tags{
+		CloudType:    cloudType,
+		ResourceType: resourceType,
+		ResourceName: strings.Join(resourceNames, ","),
+		Tags:         tags,
+	}
+	if c.dropIgnoredTags(bulkTags) && len(bulkTags.Tags) == 0 {
+		return nil
+	}
+	tags = bulkTags.Tags
+
+	b, err := json.Marshal(c.prefixTagKeys(tags))
+	if err != nil {
+		return fmt.Errorf("could not marshal tags to json: %v", err)
+	}
+
+	form := map[string]string{
+		"action":             "add_resource_tags_bulk",
+		"CID":                c.cid(),
+		"cloud_type":         strconv.Itoa(cloudType),
+		"resource_type":      resourceType,
+		"resource_name_list": strings.Join(resourceNames, ","),
+		"new_tag_json":       string(b),
+	}
+	err = c.PostAPI(form["action"], form, BasicCheck)
+	if err == nil {
+		return nil
+	}
+	if !isUnsupportedActionError(err) {
+		bulkErr := &BulkTagError{Errors: make(map[string]error, len(resourceNames))}
+		for _, name := range resourceNames {
+			bulkErr.Errors[name] = err
+		}
+		return bulkErr
+	}
+
+	bulkErr := &BulkTagError{Errors: make(map[string]error)}
+	for _, name := range resourceNames {
+		resourceTags := &Tags{
+			CloudType:    cloudType,
+			ResourceType: resourceType,
+			ResourceName: name,
+			Tags:         tags,
+		}
+		if err := c.AddTags(resourceTags); err != nil {
+			bulkErr.Errors[name] = err
+		}
+	}
+	if len(bulkErr.Errors) > 0 {
+		return bulkErr
+	}
+	return nil
+}
@@ -0,0 +1,1063 @@
+package goaviatrix
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// tagsTestServer fakes the tag API of the controller, storing the tags of a single resource. If version
+// is set, it is returned with the tags and updates with a different version are rejected.
+type tagsTestServer struct {
+	tags    map[string]string
+	version int
+	actions []string
+	deleted string
+	delJson string
+	// keyPrefix is the key_prefix of the last list request, the fake does not filter by it
+	keyPrefix string
+}
+
+func (s *tagsTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	action := r.FormValue("action")
+	s.actions = append(s.actions, action)
+	switch action {
+	case "list_resource_tags":
+		s.keyPrefix = r.FormValue("key_prefix")
+		tags, _ := json.Marshal(s.tags)
+		version := ""
+		if s.version != 0 {
+			version = strconv.Itoa(s.version)
+		}
+		respondJSON(`{"return":true,"results":{"usr_tags":` + string(tags) + `},"version":"` + version + `"}`)(w)
+	case "add_resource_tags", "update_resource_tags":
+		if version := r.FormValue("version"); s.version != 0 && version != "" {
+			if version != strconv.Itoa(s.version) {
+				respondJSON(`{"return":false,"reason":"tag version mismatch"}`)(w)
+				return
+			}
+			s.version++
+		}
+		if tagJson := r.FormValue("new_tag_json"); tagJson != "" {
+			var newTags map[string]string
+			if err := json.Unmarshal([]byte(tagJson), &newTags); err != nil {
+				respondJSON(`{"return":false,"reason":"invalid new_tag_json"}`)(w)
+				return
+			}
+			if s.tags == nil {
+				s.tags = map[string]string{}
+			}
+			for key, val := range newTags {
+				s.tags[key] = val
+			}
+		}
+		respondJSON(`{"return":true}`)(w)
+	case "create_spoke_gw":
+		// the tags a gateway is created with are stored as given, like the controller does
+		s.tags = map[string]string{}
+		if tagJson := r.FormValue("tag_json"); tagJson != "" {
+			if err := json.Unmarshal([]byte(tagJson), &s.tags); err != nil {
+				respondJSON(`{"return":false,"reason":"invalid tag_json"}`)(w)
+				return
+			}
+		}
+		if tagList := r.FormValue("tag_string"); tagList != "" {
+			for _, entry := range strings.Split(tagList, ",") {
+				kv := strings.SplitN(entry, ":", 2)
+				s.tags[kv[0]] = kv[1]
+			}
+		}
+		respondJSON(`{"return":true}`)(w)
+	case "delete_resource_tag":
+		s.deleted = r.FormValue("del_tag_list")
+		s.delJson = r.FormValue("del_tag_json")
+		respondJSON(`{"return":true}`)(w)
+	default:
+		respondJSON(`{"return":true}`)(w)
+	}
+}
+
+func TestUpdateTagsReplaceAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    map[string]string
+		tags        map[string]string
+		replaceAll  bool
+		wantActions []string
+		wantDeleted string
+		wantDelJson string
+	}{
+		{
+			"additive",
+			map[string]string{"a": "1", "b": "2"},
+			map[string]string{"a": "1"},
+			false,
+			[]string{"update_resource_tags", "list_resource_tags"},
+			"",
+			"",
+		},
+		{
+			"replace all deletes removed keys",
+			map[string]string{"a": "1", "b": "2", "c": "3"},
+			map[string]string{"a": "10"},
+			true,
+			[]string{"list_resource_tags", "delete_resource_tag", "update_resource_tags", "list_resource_tags"},
+			"b,c",
+			"",
+		},
+		{
+			"replace all deletes removed keys with commas",
+			map[string]string{"a": "1", "b,c": "2"},
+			map[string]string{"a": "10"},
+			true,
+			[]string{"list_resource_tags", "delete_resource_tag", "update_resource_tags", "list_resource_tags"},
+			"",
+			`["b,c"]`,
+		},
+		{
+			"replace all without removed keys",
+			map[string]string{"a": "1"},
+			map[string]string{"a": "1", "b": "2"},
+			true,
+			[]string{"list_resource_tags", "update_resource_tags", "list_resource_tags"},
+			"",
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &tagsTestServer{tags: tt.existing}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			err := newTestClient(srv).UpdateTags(&Tags{
+				CloudType:    1,
+				ResourceType: "gw",
+				ResourceName: "test-gw",
+				Tags:         tt.tags,
+				ReplaceAll:   tt.replaceAll,
+			})
+			if err != nil {
+				t.Fatalf("UpdateTags() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fake.actions, tt.wantActions) {
+				t.Errorf("UpdateTags() called actions %v, want %v", fake.actions, tt.wantActions)
+			}
+			if fake.deleted != tt.wantDeleted {
+				t.Errorf("UpdateTags() deleted %q, want %q", fake.deleted, tt.wantDeleted)
+			}
+			if fake.delJson != tt.wantDelJson {
+				t.Errorf("UpdateTags() del_tag_json = %q, want %q", fake.delJson, tt.wantDelJson)
+			}
+		})
+	}
+}
+
+func TestUpdateTagsVersionConflict(t *testing.T) {
+	fake := &tagsTestServer{tags: map[string]string{"owner": "network"}, version: 2}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	// the tags were read at version 1 and changed concurrently since
+	err := newTestClient(srv).UpdateTags(&Tags{
+		CloudType:    1,
+		ResourceType: "gw",
+		ResourceName: "test-gw",
+		Tags:         map[string]string{"env": "prod"},
+		Version:      "1",
+	})
+	if err != nil {
+		t.Fatalf("UpdateTags() unexpected error: %v", err)
+	}
+	wantActions := []string{"update_resource_tags", "list_resource_tags", "update_resource_tags"}
+	if !reflect.DeepEqual(fake.actions, wantActions) {
+		t.Errorf("UpdateTags() called actions %v, want %v", fake.actions, wantActions)
+	}
+	if want := map[string]string{"owner": "network", "env": "prod"}; !reflect.DeepEqual(fake.tags, want) {
+		t.Errorf("UpdateTags() tags = %v, want %v", fake.tags, want)
+	}
+}
+
+func TestUpdateTagsVersionConflictMaxTries(t *testing.T) {
+	updates := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("action") == "update_resource_tags" {
+			updates++
+			respondJSON(`{"return":false,"reason":"tag version mismatch"}`)(w)
+			return
+		}
+		respondJSON(`{"return":true,"results":{"usr_tags":{}},"version":"` + strconv.Itoa(updates+1) + `"}`)(w)
+	}))
+	defer srv.Close()
+
+	err := newTestClient(srv).UpdateTags(&Tags{
+		CloudType:    1,
+		ResourceType: "gw",
+		ResourceName: "test-gw",
+		Tags:         map[string]string{"env": "prod"},
+		Version:      "1",
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("UpdateTags() error = %v, want %v", err, ErrConflict)
+	}
+	if updates != maxTagUpdateConflicts {
+		t.Errorf("UpdateTags() sent %d updates, want %d", updates, maxTagUpdateConflicts)
+	}
+}
+
+func TestDeleteTagsVerify(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		verify       bool
+		wantErr      bool
+	}{
+		{"deleted", "gw", true, false},
+		{"wrong resource type", "vpc", true, true},
+		{"wrong resource type without verification", "vpc", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := map[string]string{"owner": "network", "env": "prod"}
+			var actions []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				actions = append(actions, r.FormValue("action"))
+				switch r.FormValue("action") {
+				case "delete_resource_tag":
+					// the controller ignores tags of resources of another type
+					if r.FormValue("resource_type") == "gw" {
+						delete(tags, "env")
+					}
+					respondJSON(`{"return":true}`)(w)
+				default:
+					b, _ := json.Marshal(tags)
+					respondJSON(`{"return":true,"results":{"usr_tags":` + string(b) + `}}`)(w)
+				}
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).DeleteTags(&Tags{
+				CloudType:    1,
+				ResourceType: tt.resourceType,
+				ResourceName: "test-gw",
+				TagList:      "env:prod",
+				VerifyDelete: tt.verify,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteTags() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if wantCalls := map[bool]int{true: 2, false: 1}[tt.verify]; len(actions) != wantCalls {
+				t.Errorf("DeleteTags() called actions %v, want %d calls", actions, wantCalls)
+			}
+		})
+	}
+}
+
+func TestRemainingTags(t *testing.T) {
+	current := map[string]string{"owner": "network", "url": "http://example.com"}
+	tests := []struct {
+		name    string
+		deleted []string
+		want    []string
+	}{
+		{"deleted keys", []string{"env"}, nil},
+		{"remaining key", []string{"owner", "env"}, []string{"owner"}},
+		{"remaining key value pair", []string{"owner:network"}, []string{"owner"}},
+		{"escaped colon", []string{"url:http\\\\://example.com"}, []string{"url"}},
+		{"other value", []string{"owner:security"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remainingTags(current, tt.deleted); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("remainingTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagsUpdateLost(t *testing.T) {
+	tests := []struct {
+		name       string
+		updated    map[string]string
+		current    map[string]string
+		replaceAll bool
+		want       bool
+	}{
+		{"updated", map[string]string{"a": "1"}, map[string]string{"a": "1", "b": "2"}, false, false},
+		{"value overwritten", map[string]string{"a": "1"}, map[string]string{"a": "2"}, false, true},
+		{"key deleted", map[string]string{"a": "1"}, map[string]string{"b": "2"}, false, true},
+		{"replaced", map[string]string{"a": "1"}, map[string]string{"a": "1"}, true, false},
+		{"key added after replace", map[string]string{"a": "1"}, map[string]string{"a": "1", "b": "2"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagsUpdateLost(tt.updated, tt.current, tt.replaceAll); got != tt.want {
+				t.Errorf("tagsUpdateLost() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTagsMap(t *testing.T) {
+	fake := &tagsTestServer{tags: map[string]string{
+		"created": "2023-01-01T00:00:00",
+		"owner":   "network:team",
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	tags := &Tags{
+		CloudType:    1,
+		ResourceType: "gw",
+		ResourceName: "test-gw",
+	}
+	got, err := newTestClient(srv).GetTagsMap(tags)
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, fake.tags) {
+		t.Errorf("GetTagsMap() got = %v, want %v", got, fake.tags)
+	}
+	if !reflect.DeepEqual(tags.Tags, fake.tags) {
+		t.Errorf("GetTagsMap() did not store tags, got = %v, want %v", tags.Tags, fake.tags)
+	}
+}
+
+func TestTagsRoundTripSpecialCharacters(t *testing.T) {
+	tests := []struct {
+		name string
+		tags map[string]string
+	}{
+		{"comma", map[string]string{"cost-centers": "1234,5678"}},
+		{"colon", map[string]string{"created": "2023-01-01T00:00:00"}},
+		{"colon in key", map[string]string{"team:network": "owner"}},
+		{"spaces", map[string]string{"owner name": "network team"}},
+		{"all delimiters", map[string]string{"owner name": "network team, emea: west"}},
+	}
+	for _, tt := range tests {
+		for _, action := range []string{"AddTags", "UpdateTags"} {
+			t.Run(tt.name+" "+action, func(t *testing.T) {
+				fake := &tagsTestServer{}
+				srv := httptest.NewServer(fake)
+				defer srv.Close()
+
+				client := newTestClient(srv)
+				tags := &Tags{
+					CloudType:    1,
+					ResourceType: "gw",
+					ResourceName: "test-gw",
+					Tags:         tt.tags,
+				}
+				var err error
+				if action == "AddTags" {
+					err = client.AddTags(tags)
+				} else {
+					err = client.UpdateTags(tags)
+				}
+				if err != nil {
+					t.Fatalf("%s() unexpected error: %v", action, err)
+				}
+
+				got, err := client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+				if err != nil {
+					t.Fatalf("GetTagsMap() unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.tags) {
+					t.Errorf("%s() round trip got = %v, want %v", action, got, tt.tags)
+				}
+			})
+		}
+	}
+}
+
+func TestDeleteTagsByKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        []string
+		wantDeleted string
+		wantDelJson string
+	}{
+		{"single key", []string{"a"}, "a", ""},
+		{"simple keys", []string{"a", "b:c"}, "a,b:c", ""},
+		{"key with comma", []string{"a", "b,c"}, "", `["a","b,c"]`},
+		{"empty key dropped", []string{"a", ""}, "a", ""},
+		{"no keys", nil, "", ""},
+		{"only empty key", []string{""}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &tagsTestServer{}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			err := newTestClient(srv).DeleteTagsByKeys(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"}, tt.keys)
+			if err != nil {
+				t.Fatalf("DeleteTagsByKeys() unexpected error: %v", err)
+			}
+			if tt.wantDeleted == "" && tt.wantDelJson == "" && len(fake.actions) != 0 {
+				t.Errorf("DeleteTagsByKeys() without keys called actions %v, want none", fake.actions)
+			}
+			if fake.deleted != tt.wantDeleted {
+				t.Errorf("DeleteTagsByKeys() del_tag_list = %q, want %q", fake.deleted, tt.wantDeleted)
+			}
+			if fake.delJson != tt.wantDelJson {
+				t.Errorf("DeleteTagsByKeys() del_tag_json = %q, want %q", fake.delJson, tt.wantDelJson)
+			}
+		})
+	}
+}
+
+func TestDeleteTagsEmptyTagList(t *testing.T) {
+	fake := &tagsTestServer{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	if err := newTestClient(srv).DeleteTags(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"}); err != nil {
+		t.Fatalf("DeleteTags() unexpected error: %v", err)
+	}
+	if len(fake.actions) != 0 {
+		t.Errorf("DeleteTags() with an empty TagList called actions %v, want none", fake.actions)
+	}
+}
+
+func TestAddTagsBulk(t *testing.T) {
+	tests := []struct {
+		name         string
+		bulkResponse string
+		failResource string
+		wantActions  []string
+		wantFailed   []string
+	}{
+		{
+			"bulk supported",
+			`{"return":true}`,
+			"",
+			[]string{"add_resource_tags_bulk"},
+			nil,
+		},
+		{
+			"bulk failed",
+			`{"return":false,"reason":"gateway gw-2 does not exist"}`,
+			"",
+			[]string{"add_resource_tags_bulk"},
+			[]string{"gw-1", "gw-2"},
+		},
+		{
+			"fallback to single calls",
+			`{"return":false,"reason":"Valid action required: add_resource_tags_bulk"}`,
+			"",
+			[]string{"add_resource_tags_bulk", "add_resource_tags", "add_resource_tags"},
+			nil,
+		},
+		{
+			"fallback with partial failure",
+			`{"return":false,"reason":"Valid action required: add_resource_tags_bulk"}`,
+			"gw-2",
+			[]string{"add_resource_tags_bulk", "add_resource_tags", "add_resource_tags"},
+			[]string{"gw-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actions []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				actions = append(actions, r.FormValue("action"))
+				switch {
+				case r.FormValue("action") == "add_resource_tags_bulk":
+					respondJSON(tt.bulkResponse)(w)
+				case r.FormValue("resource_name") == tt.failResource:
+					respondJSON(`{"return":false,"reason":"resource not found"}`)(w)
+				default:
+					respondJSON(`{"return":true}`)(w)
+				}
+			}))
+			defer srv.Close()
+
+			err := newTestClient(srv).AddTagsBulk(1, "gw", []string{"gw-1", "gw-2"}, map[string]string{"env": "prod"})
+			if !reflect.DeepEqual(actions, tt.wantActions) {
+				t.Errorf("AddTagsBulk() actions = %v, want %v", actions, tt.wantActions)
+			}
+			if tt.wantFailed == nil {
+				if err != nil {
+					t.Errorf("AddTagsBulk() unexpected error: %v", err)
+				}
+				return
+			}
+			bulkErr, ok := err.(*BulkTagError)
+			if !ok {
+				t.Fatalf("AddTagsBulk() error = %v, want a *BulkTagError", err)
+			}
+			if got := bulkErr.FailedResourceNames(); !reflect.DeepEqual(got, tt.wantFailed) {
+				t.Errorf("AddTagsBulk() failed resources = %v, want %v", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestAddTagsBulkIgnoreTagsAndPrefix(t *testing.T) {
+	tests := []struct {
+		name         string
+		bulkResponse string
+		tags         map[string]string
+		wantActions  []string
+		wantTagJson  string
+	}{
+		{
+			"bulk",
+			`{"return":true}`,
+			map[string]string{"env": "prod", "compliance": "pci"},
+			[]string{"add_resource_tags_bulk"},
+			`{"team-a:env":"prod"}`,
+		},
+		{
+			"fallback to single calls",
+			`{"return":false,"reason":"Valid action required: add_resource_tags_bulk"}`,
+			map[string]string{"env": "prod", "compliance": "pci"},
+			[]string{"add_resource_tags_bulk", "add_resource_tags", "add_resource_tags"},
+			`{"team-a:env":"prod"}`,
+		},
+		{
+			"only ignored tags",
+			`{"return":true}`,
+			map[string]string{"compliance": "pci"},
+			nil,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actions, tagJsons []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				actions = append(actions, r.FormValue("action"))
+				if r.FormValue("action") == "add_resource_tags_bulk" {
+					tagJsons = append(tagJsons, r.FormValue("new_tag_json"))
+					respondJSON(tt.bulkResponse)(w)
+					return
+				}
+				tagJsons = append(tagJsons, r.FormValue("new_tag_json"))
+				respondJSON(`{"return":true}`)(w)
+			}))
+			defer srv.Close()
+
+			client := newTestClient(srv)
+			client.IgnoreTags = IgnoreTagsConfig{Keys: []string{"compliance"}}
+			client.TagPrefix = "team-a:"
+			if err := client.AddTagsBulk(1, "gw", []string{"gw-1", "gw-2"}, tt.tags); err != nil {
+				t.Fatalf("AddTagsBulk() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actions, tt.wantActions) {
+				t.Errorf("AddTagsBulk() actions = %v, want %v", actions, tt.wantActions)
+			}
+			for i, tagJson := range tagJsons {
+				if tagJson != tt.wantTagJson {
+					t.Errorf("AddTagsBulk() %s sent tags %s, want %s", actions[i], tagJson, tt.wantTagJson)
+				}
+			}
+			if _, ok := tt.tags["compliance"]; !ok {
+				t.Errorf("AddTagsBulk() removed the ignored tag from the tags of the caller")
+			}
+		})
+	}
+}
+
+func TestGetTagsMapByResourceID(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceName string
+		wantQueries  []string
+	}{
+		{"name not found", "test-gw", []string{"name:test-gw", "id:i-0123456789abcdef0"}},
+		{"no name", "", []string{"id:i-0123456789abcdef0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if name := r.URL.Query().Get("resource_name"); name != "" {
+					queries = append(queries, "name:"+name)
+					respondJSON(`{"return":false,"reason":"Resource test-gw does not exist"}`)(w)
+					return
+				}
+				queries = append(queries, "id:"+r.URL.Query().Get("resource_id"))
+				respondJSON(`{"return":true,"results":{"usr_tags":{"owner":"network"}}}`)(w)
+			}))
+			defer srv.Close()
+
+			tags := &Tags{CloudType: 1, ResourceType: "gw", ResourceName: tt.resourceName, ResourceID: "i-0123456789abcdef0"}
+			got, err := newTestClient(srv).GetTagsMap(tags)
+			if err != nil {
+				t.Fatalf("GetTagsMap() unexpected error: %v", err)
+			}
+			if want := map[string]string{"owner": "network"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetTagsMap() got = %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(queries, tt.wantQueries) {
+				t.Errorf("GetTagsMap() queried %v, want %v", queries, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestGetTagsMapNotFoundWithoutResourceID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(`{"return":false,"reason":"Resource test-gw does not exist"}`)(w)
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv).GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+	if err != ErrNotFound {
+		t.Errorf("GetTagsMap() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestIsResourceNotFoundReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   bool
+	}{
+		{"Gateway test-gw does not exist.", true},
+		{"Resource test-gw does not exist", true},
+		{"device TEST-GW does not exist", true},
+		{"Gateway other-gw does not exist.", false},
+		{"tag key not found", false},
+		{"Account test-account does not exist", false},
+		{"Gateway test-gw does not exist in account test-account", false},
+	}
+	for _, tt := range tests {
+		if got := isResourceNotFoundReason(tt.reason, "test-gw"); got != tt.want {
+			t.Errorf("isResourceNotFoundReason(%q) = %t, want %t", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestGetTagsMapDevice(t *testing.T) {
+	var sentCloudType bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, sentCloudType = r.URL.Query()["cloud_type"]
+		respondJSON(`{"return":true,"results":{"usr_tags":{"owner":"network"}}}`)(w)
+	}))
+	defer srv.Close()
+
+	tags := NewDeviceTags("test-device", false, nil)
+	got, err := newTestClient(srv).GetTagsMap(tags)
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if want := map[string]string{"owner": "network"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTagsMap() got = %v, want %v", got, want)
+	}
+	if sentCloudType {
+		t.Errorf("GetTagsMap() sent cloud_type for a device")
+	}
+	if tags.ResourceType != "device" {
+		t.Errorf("NewDeviceTags() resource type = %q, want %q", tags.ResourceType, "device")
+	}
+}
+
+func TestGetTagsMapCached(t *testing.T) {
+	listCalls := func(fake *tagsTestServer) int {
+		calls := 0
+		for _, action := range fake.actions {
+			if action == "list_resource_tags" {
+				calls++
+			}
+		}
+		return calls
+	}
+	tags := func() *Tags {
+		return &Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"}
+	}
+
+	fake := &tagsTestServer{tags: map[string]string{"owner": "network"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTagsMap(tags()); err != nil {
+			t.Fatalf("GetTagsMap() unexpected error: %v", err)
+		}
+	}
+	if got := listCalls(fake); got != 1 {
+		t.Errorf("GetTagsMap() listed tags %d times, want 1", got)
+	}
+
+	// the cached tags must not be modified through a returned map
+	got, _ := client.GetTagsMap(tags())
+	got["owner"] = "modified"
+
+	newTags := tags()
+	newTags.Tags = map[string]string{"env": "prod"}
+	if err := client.AddTags(newTags); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+	got, err := client.GetTagsMap(tags())
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if want := map[string]string{"owner": "network", "env": "prod"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTagsMap() after AddTags got = %v, want %v", got, want)
+	}
+	if got := listCalls(fake); got != 2 {
+		t.Errorf("GetTagsMap() after AddTags listed tags %d times in total, want 2", got)
+	}
+
+	client.DisableTagCache = true
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTagsMap(tags()); err != nil {
+			t.Fatalf("GetTagsMap() unexpected error: %v", err)
+		}
+	}
+	if got := listCalls(fake); got != 4 {
+		t.Errorf("GetTagsMap() with DisableTagCache listed tags %d times in total, want 4", got)
+	}
+}
+
+func TestGetTagsMapNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     map[string]string
+		wantErr  error
+	}{
+		{
+			"tags",
+			`{"return":true,"results":{"usr_tags":{"owner":"network"}}}`,
+			map[string]string{"owner": "network"},
+			nil,
+		},
+		{
+			"no tags",
+			`{"return":true,"results":{}}`,
+			nil,
+			nil,
+		},
+		{
+			"resource does not exist",
+			`{"return":false,"reason":"Gateway test-gw does not exist."}`,
+			nil,
+			ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				respondJSON(tt.response)(w)
+			}))
+			defer srv.Close()
+
+			got, err := newTestClient(srv).GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+			if err != tt.wantErr {
+				t.Fatalf("GetTagsMap() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTagsMap() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagsControllerScope(t *testing.T) {
+	var actions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action := r.URL.Query().Get("action")
+		if action == "" {
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			action = r.FormValue("action")
+		}
+		actions = append(actions, action)
+		switch action {
+		case "list_resource_tags":
+			respondJSON(`{"return":true,"results":{"usr_tags":{"owner":"network"}}}`)(w)
+		case "list_resource_labels":
+			respondJSON(`{"return":true,"results":{"usr_tags":{"site":"branch-1","role":"edge"}}}`)(w)
+		default:
+			respondJSON(`{"return":true}`)(w)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	cloudTags, err := client.GetTagsMap(&Tags{ResourceType: "gw", ResourceName: "caag-1"})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cloudTags, map[string]string{"owner": "network"}) {
+		t.Errorf("GetTagsMap() of cloud tags got = %v", cloudTags)
+	}
+	// the cached cloud tags must not be returned as controller tags
+	controllerTags, err := client.GetTagsMap(&Tags{ResourceType: "gw", ResourceName: "caag-1", Scope: TagScopeController})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(controllerTags, map[string]string{"site": "branch-1", "role": "edge"}) {
+		t.Errorf("GetTagsMap() of controller tags got = %v", controllerTags)
+	}
+
+	actions = nil
+	err = client.UpdateTags(&Tags{
+		ResourceType: "gw",
+		ResourceName: "caag-1",
+		Tags:         map[string]string{"site": "branch-1"},
+		ReplaceAll:   true,
+		Scope:        TagScopeController,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTags() unexpected error: %v", err)
+	}
+	wantActions := []string{"list_resource_labels", "delete_resource_label", "update_resource_labels", "list_resource_labels"}
+	if !reflect.DeepEqual(actions, wantActions) {
+		t.Errorf("UpdateTags() called actions %v, want %v", actions, wantActions)
+	}
+}
+
+func TestIgnoreTags(t *testing.T) {
+	fake := &tagsTestServer{tags: map[string]string{"owner": "network", "compliance": "pci", "aws:createdBy": "scanner"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.IgnoreTags = IgnoreTagsConfig{Keys: []string{"compliance"}, KeyPrefixes: []string{"aws:"}}
+
+	tagsMap, err := client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tagsMap, map[string]string{"owner": "network"}) {
+		t.Errorf("GetTagsMap() got = %v, want only the tags that are not ignored", tagsMap)
+	}
+
+	// replacing all tags must neither delete nor write the ignored tags
+	err = client.UpdateTags(&Tags{
+		CloudType:    1,
+		ResourceType: "gw",
+		ResourceName: "test-gw",
+		Tags:         map[string]string{"env": "prod", "compliance": "none"},
+		ReplaceAll:   true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTags() unexpected error: %v", err)
+	}
+	if fake.deleted != "owner" {
+		t.Errorf("UpdateTags() deleted %q, want %q", fake.deleted, "owner")
+	}
+	if fake.tags["compliance"] != "pci" {
+		t.Errorf("UpdateTags() changed ignored tag compliance to %q", fake.tags["compliance"])
+	}
+
+	fake.actions = nil
+	if err := client.DeleteTagsByKeys(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"}, []string{"aws:createdBy"}); err != nil {
+		t.Fatalf("DeleteTagsByKeys() unexpected error: %v", err)
+	}
+	if len(fake.actions) != 0 {
+		t.Errorf("DeleteTagsByKeys() of ignored tags called actions %v, want none", fake.actions)
+	}
+}
+
+func TestTagPrefix(t *testing.T) {
+	fake := &tagsTestServer{tags: map[string]string{"team:owner": "network", "other:owner": "security", "env": "dev"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.TagPrefix = "team:"
+
+	tagsMap, err := client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tagsMap, map[string]string{"owner": "network"}) {
+		t.Errorf("GetTagsMap() got = %v, want only the prefixed tags without the prefix", tagsMap)
+	}
+
+	// replacing all tags only deletes tags with the prefix
+	err = client.UpdateTags(&Tags{
+		CloudType:    1,
+		ResourceType: "gw",
+		ResourceName: "test-gw",
+		Tags:         map[string]string{"env": "prod"},
+		ReplaceAll:   true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTags() unexpected error: %v", err)
+	}
+	if fake.delJson != `["team:owner"]` {
+		t.Errorf("UpdateTags() deleted %q, want %q", fake.delJson, `["team:owner"]`)
+	}
+	if fake.tags["team:env"] != "prod" || fake.tags["env"] != "dev" {
+		t.Errorf("UpdateTags() got tags %v, want team:env=prod and env unchanged", fake.tags)
+	}
+
+	if err := client.AddTags(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw", TagList: "cost:10"}); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+	if fake.tags["team:cost"] != "10" {
+		t.Errorf("AddTags() got tags %v, want team:cost=10", fake.tags)
+	}
+
+	// the tags written are read back with the keys used to write them
+	tagsMap, err = client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if tagsMap["env"] != "prod" || tagsMap["cost"] != "10" {
+		t.Errorf("GetTagsMap() got = %v, want env=prod and cost=10", tagsMap)
+	}
+}
+
+func TestGetGatewayTagsImport(t *testing.T) {
+	controllerTags := map[string]string{"team:owner": "network", "team:compliance": "pci", "env": "dev"}
+	tests := []struct {
+		name        string
+		detailTags  map[string]string
+		wantActions []string
+	}{
+		{"tags in gateway details", controllerTags, nil},
+		{"no tags in gateway details", nil, []string{"list_resource_tags"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &tagsTestServer{tags: controllerTags}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			client := newTestClient(srv)
+			client.TagPrefix = "team:"
+			client.IgnoreTags = IgnoreTagsConfig{Keys: []string{"compliance"}}
+
+			// an imported gateway that already has tags on the controller
+			gateway := &Gateway{CloudType: 1, GwName: "test-gw", Tags: tt.detailTags}
+			tagsMap, err := client.GetGatewayTags(gateway)
+			if err != nil {
+				t.Fatalf("GetGatewayTags() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tagsMap, map[string]string{"owner": "network"}) {
+				t.Errorf("GetGatewayTags() got = %v, want the tags managed by the provider", tagsMap)
+			}
+			if !reflect.DeepEqual(fake.actions, tt.wantActions) {
+				t.Errorf("GetGatewayTags() called actions %v, want %v", fake.actions, tt.wantActions)
+			}
+		})
+	}
+}
+
+func TestGetTagsMapKeyPrefix(t *testing.T) {
+	fake := &tagsTestServer{tags: map[string]string{
+		"team:owner":    "network",
+		"team:env":      "dev",
+		"team:app:name": "web",
+		"team:app:tier": "frontend",
+		"cost-center":   "42",
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.TagPrefix = "team:"
+
+	tagsMap, err := client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw", KeyPrefix: "app:"})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if want := map[string]string{"app:name": "web", "app:tier": "frontend"}; !reflect.DeepEqual(tagsMap, want) {
+		t.Errorf("GetTagsMap() with key prefix got = %v, want %v", tagsMap, want)
+	}
+	if fake.keyPrefix != "team:app:" {
+		t.Errorf("GetTagsMap() sent key_prefix %q, want %q", fake.keyPrefix, "team:app:")
+	}
+
+	// the filtered tags are cached separately from all tags
+	tagsMap, err = client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+	if err != nil {
+		t.Fatalf("GetTagsMap() unexpected error: %v", err)
+	}
+	if len(tagsMap) != 4 {
+		t.Errorf("GetTagsMap() without key prefix got = %v, want all 4 tags with the tag prefix", tagsMap)
+	}
+
+	// updating the tags invalidates the cached filtered tags
+	if err := client.UpdateTags(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw", Tags: map[string]string{"app:version": "2"}}); err != nil {
+		t.Fatalf("UpdateTags() unexpected error: %v", err)
+	}
+	tagList, err := client.GetTags(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw", KeyPrefix: "app:v"})
+	if err != nil {
+		t.Fatalf("GetTags() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tagList, []string{"app:version:2"}) {
+		t.Errorf("GetTags() with key prefix got = %v, want [app:version:2]", tagList)
+	}
+
+	tagsMap, err = client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw", KeyPrefix: "none:"})
+	if err != nil || tagsMap != nil {
+		t.Errorf("GetTagsMap() with unmatched key prefix got = %v, %v, want no tags and no error", tagsMap, err)
+	}
+}
+
+func TestPrepareCreateTagsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tags *Tags
+	}{
+		{"tags", &Tags{Tags: map[string]string{"env": "prod", "cost": "10", "compliance": "none"}}},
+		{"tag list", &Tags{TagList: "env:prod,cost:10,compliance:none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &tagsTestServer{}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			client := newTestClient(srv)
+			client.TagPrefix = "team:"
+			client.IgnoreTags = IgnoreTagsConfig{Keys: []string{"compliance"}}
+
+			tt.tags.CloudType, tt.tags.ResourceType, tt.tags.ResourceName = 1, "gw", "test-gw"
+			if err := client.PrepareCreateTags(tt.tags); err != nil {
+				t.Fatalf("PrepareCreateTags() unexpected error: %v", err)
+			}
+			gateway := &SpokeVpc{CloudType: 1, GwName: "test-gw", TagList: tt.tags.TagList, TagJson: tt.tags.TagJson}
+			if err := client.LaunchSpokeVpc(gateway); err != nil {
+				t.Fatalf("LaunchSpokeVpc() unexpected error: %v", err)
+			}
+			if want := map[string]string{"team:env": "prod", "team:cost": "10"}; !reflect.DeepEqual(fake.tags, want) {
+				t.Errorf("LaunchSpokeVpc() created the gateway with tags %v, want %v", fake.tags, want)
+			}
+
+			tagsMap, err := client.GetTagsMap(&Tags{CloudType: 1, ResourceType: "gw", ResourceName: "test-gw"})
+			if err != nil {
+				t.Fatalf("GetTagsMap() unexpected error: %v", err)
+			}
+			if want := map[string]string{"env": "prod", "cost": "10"}; !reflect.DeepEqual(tagsMap, want) {
+				t.Errorf("GetTagsMap() got = %v, want %v", tagsMap, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package goaviatrix
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxThrottledTries is the number of attempts made for a request the controller keeps throttling
+const maxThrottledTries = 5
+
+// maxRetryAfter bounds the wait requested by the controller in a Retry-After header
+var maxRetryAfter = time.Minute
+
+// do sends the request and logs its outcome. While the controller throttles the request with
+// HTTP 429 Too Many Requests, it waits as long as the Retry-After header asks for and sends it again.
+func (c *Client) do(req *http.Request, action string) (*http.Response, error) {
+	backoff := retryBackoff
+	for try := 1; ; try++ {
+		resp, err := c.doWithRequestID(req, action)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || try >= maxThrottledTries {
+			return resp, err
+		}
+
+		// the body was consumed by the first try, a request whose body can not be read again is not retried
+		retry := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			if retry.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+		resp.Body.Close()
+
+		wait := retryAfter(resp.Header.Get("Retry-After"), backoff)
+		log.WithFields(log.Fields{
+			"try":    try,
+			"action": action,
+			"wait":   wait.String(),
+		}).Warnf("HTTP request throttled by the controller, retrying")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		req = retry
+		// Double the backoff time after each throttled try without a Retry-After header
+		backoff *= 2
+	}
+}
+
+// retryAfter returns the wait requested by the value of a Retry-After header, given either in
+// seconds or as an HTTP date, up to maxRetryAfter. Without a valid value it returns fallback.
+func retryAfter(value string, fallback time.Duration) time.Duration {
+	value = strings.TrimSpace(value)
+	wait := fallback
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	}
+
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return wait
+}
@@ -0,0 +1,98 @@
+package goaviatrix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestThrottledRequestRetried(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(c *Client) error
+	}{
+		{
+			"PostAPI",
+			func(c *Client) error {
+				return c.PostAPI("test_action", map[string]string{"CID": c.CID, "action": "test_action", "name": "test"}, BasicCheck)
+			},
+		},
+		{
+			"GetAPI",
+			func(c *Client) error {
+				var data APIResp
+				return c.GetAPI(&data, "test_action", map[string]string{"CID": c.CID, "action": "test_action", "name": "test"}, BasicCheck)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				// the request must be sent again in full after being throttled
+				if name := r.FormValue("name"); name != "test" {
+					t.Errorf("try %d sent name %q, want %q", calls, name, "test")
+				}
+				if calls == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				respondJSON(`{"return":true}`)(w)
+			}))
+			defer srv.Close()
+
+			if err := tt.call(newTestClient(srv)); err != nil {
+				t.Fatalf("%s() unexpected error: %v", tt.name, err)
+			}
+			if calls != 2 {
+				t.Errorf("%s() sent %d requests, want 2", tt.name, calls)
+			}
+		})
+	}
+}
+
+func TestThrottledRequestMaxTries(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = 500 * time.Millisecond }()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	resp, err := newTestClient(srv).PostContext(context.Background(), srv.URL, map[string]string{"action": "test_action"})
+	if err == nil && resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("PostContext() got status %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+	if calls != maxThrottledTries {
+		t.Errorf("PostContext() sent %d requests, want %d", calls, maxThrottledTries)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"seconds", "3", 3 * time.Second},
+		{"no header", "", time.Second},
+		{"invalid", "soon", time.Second},
+		{"capped", "3600", maxRetryAfter},
+		{"negative", "-1", 0},
+		{"date in the past", "Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.value, time.Second); got != tt.want {
+				t.Errorf("retryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
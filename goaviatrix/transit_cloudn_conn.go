@@ -35,7 +35,7 @@ type TransitCloudnConn struct {
 
 func (c *Client) CreateTransitCloudnConn(ctx context.Context, transitCloudnConn *TransitCloudnConn) error {
 	transitCloudnConn.Action = "connect_transit_gw_to_aviatrix_cloudn"
-	transitCloudnConn.CID = c.CID
+	transitCloudnConn.CID = c.cid()
 	// The backend API checks if enable_load_balancing != false. enable_load_balancing will be empty if false when using
 	// it as a bool. enable_load_balancing must be converted to a string first.
 	transitCloudnConn.EnableLoadBalancingStr = strconv.FormatBool(transitCloudnConn.EnableLoadBalancing)
@@ -45,7 +45,7 @@ func (c *Client) CreateTransitCloudnConn(ctx context.Context, transitCloudnConn
 
 func (c *Client) GetTransitCloudnConn(ctx context.Context, transitCloudnConn *TransitCloudnConn) (*TransitCloudnConn, error) {
 	params := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "get_site2cloud_conn_detail",
 		"conn_name": transitCloudnConn.ConnectionName,
 		"vpc_id":    transitCloudnConn.VpcID,
@@ -116,7 +116,7 @@ func (c *Client) GetTransitCloudnConn(ctx context.Context, transitCloudnConn *Tr
 }
 
 func (c *Client) DeleteTransitCloudnConn(ctx context.Context, transitCloudnConn *TransitCloudnConn) error {
-	transitCloudnConn.CID = c.CID
+	transitCloudnConn.CID = c.cid()
 	transitCloudnConn.Action = "disconnect_transit_gw"
 
 	return c.PostAPIContext(ctx, transitCloudnConn.Action, transitCloudnConn, BasicCheck)
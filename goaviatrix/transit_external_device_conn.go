@@ -102,7 +102,7 @@ type ExternalDeviceConnDetailList struct {
 }
 
 func (c *Client) CreateExternalDeviceConn(externalDeviceConn *ExternalDeviceConn) error {
-	externalDeviceConn.CID = c.CID
+	externalDeviceConn.CID = c.cid()
 	externalDeviceConn.Action = "connect_transit_gw_to_external_device"
 
 	return c.PostAPI(externalDeviceConn.Action, externalDeviceConn, BasicCheck)
@@ -110,7 +110,7 @@ func (c *Client) CreateExternalDeviceConn(externalDeviceConn *ExternalDeviceConn
 
 func (c *Client) GetExternalDeviceConnDetail(externalDeviceConn *ExternalDeviceConn) (*ExternalDeviceConn, error) {
 	params := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "get_site2cloud_conn_detail",
 		"conn_name": externalDeviceConn.ConnectionName,
 		"vpc_id":    externalDeviceConn.VpcID,
@@ -286,7 +286,7 @@ func (c *Client) GetExternalDeviceConnDetail(externalDeviceConn *ExternalDeviceC
 }
 
 func (c *Client) DeleteExternalDeviceConn(externalDeviceConn *ExternalDeviceConn) error {
-	externalDeviceConn.CID = c.CID
+	externalDeviceConn.CID = c.cid()
 	externalDeviceConn.Action = "disconnect_transit_gw"
 
 	return c.PostAPI(externalDeviceConn.Action, externalDeviceConn, BasicCheck)
@@ -362,7 +362,7 @@ func (c *Client) EditTransitExternalDeviceConnASPathPrepend(externalDeviceConn *
 		ConnectionName string `form:"connection_name"`
 		PrependASPath  string `form:"connection_as_path_prepend"`
 	}{
-		CID:            c.CID,
+		CID:            c.cid(),
 		Action:         action,
 		GatewayName:    externalDeviceConn.GwName,
 		ConnectionName: externalDeviceConn.ConnectionName,
@@ -23,7 +23,7 @@ type TransitFireNetPolicyEdit struct {
 
 func (c *Client) CreateTransitFireNetPolicy(transitFireNetPolicy *TransitFireNetPolicy) error {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "add_spoke_to_transit_firenet_inspection",
 		"firenet_gateway_name": transitFireNetPolicy.TransitFireNetGatewayName,
 		"spoke_gateway_name":   transitFireNetPolicy.InspectedResourceName,
@@ -34,7 +34,7 @@ func (c *Client) CreateTransitFireNetPolicy(transitFireNetPolicy *TransitFireNet
 
 func (c *Client) GetTransitFireNetPolicy(transitFireNetPolicy *TransitFireNetPolicy) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_transit_firenet_spoke_policies",
 	}
 
@@ -66,7 +66,7 @@ func (c *Client) GetTransitFireNetPolicy(transitFireNetPolicy *TransitFireNetPol
 
 func (c *Client) DeleteTransitFireNetPolicy(transitFireNetPolicy *TransitFireNetPolicy) error {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "delete_spoke_from_transit_firenet_inspection",
 		"firenet_gateway_name": transitFireNetPolicy.TransitFireNetGatewayName,
 		"spoke_gateway_name":   transitFireNetPolicy.InspectedResourceName,
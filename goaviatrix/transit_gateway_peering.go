@@ -60,14 +60,14 @@ type TunnelsDetail struct {
 }
 
 func (c *Client) CreateTransitGatewayPeering(transitGatewayPeering *TransitGatewayPeering) error {
-	transitGatewayPeering.CID = c.CID
+	transitGatewayPeering.CID = c.cid()
 	transitGatewayPeering.Action = "create_inter_transit_gateway_peering"
 	return c.PostAPI(transitGatewayPeering.Action, transitGatewayPeering, BasicCheck)
 }
 
 func (c *Client) GetTransitGatewayPeering(transitGatewayPeering *TransitGatewayPeering) error {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_inter_transit_gateway_peering",
 	}
 
@@ -103,7 +103,7 @@ func (c *Client) GetTransitGatewayPeering(transitGatewayPeering *TransitGatewayP
 func (c *Client) GetTransitGatewayPeeringDetails(transitGatewayPeering *TransitGatewayPeering) (*TransitGatewayPeering, error) {
 	form := map[string]string{
 		"action":   "get_inter_transit_gateway_peering_details",
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"gateway1": transitGatewayPeering.TransitGatewayName1,
 		"gateway2": transitGatewayPeering.TransitGatewayName2,
 	}
@@ -140,7 +140,7 @@ func (c *Client) GetTransitGatewayPeeringDetails(transitGatewayPeering *TransitG
 }
 
 func (c *Client) UpdateTransitGatewayPeering(transitGatewayPeering *TransitGatewayPeering) error {
-	transitGatewayPeering.CID = c.CID
+	transitGatewayPeering.CID = c.cid()
 	transitGatewayPeering.Action = "edit_inter_transit_gateway_peering"
 
 	return c.PostAPI(transitGatewayPeering.Action, transitGatewayPeering, BasicCheck)
@@ -148,7 +148,7 @@ func (c *Client) UpdateTransitGatewayPeering(transitGatewayPeering *TransitGatew
 
 func (c *Client) DeleteTransitGatewayPeering(transitGatewayPeering *TransitGatewayPeering) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "delete_inter_transit_gateway_peering",
 		"gateway1": transitGatewayPeering.TransitGatewayName1,
 		"gateway2": transitGatewayPeering.TransitGatewayName2,
@@ -166,7 +166,7 @@ func (c *Client) EditTransitConnectionASPathPrepend(transitGatewayPeering *Trans
 		ConnectionName string `form:"connection_name"`
 		PrependASPath  string `form:"connection_as_path_prepend"`
 	}{
-		CID:            c.CID,
+		CID:            c.cid(),
 		Action:         action,
 		GatewayName:    transitGatewayPeering.TransitGatewayName1,
 		ConnectionName: transitGatewayPeering.TransitGatewayName2 + "-peering",
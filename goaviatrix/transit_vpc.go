@@ -139,14 +139,14 @@ type TransitGatewayBgpLanIpInfo struct {
 }
 
 func (c *Client) LaunchTransitVpc(gateway *TransitVpc) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "create_transit_gw"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
 }
 
 func (c *Client) EnableHaTransitGateway(gateway *TransitVpc) error {
-	gateway.CID = c.CID
+	gateway.CID = c.cid()
 	gateway.Action = "create_peering_ha_gateway"
 
 	return c.PostAPI(gateway.Action, gateway, BasicCheck)
@@ -154,7 +154,7 @@ func (c *Client) EnableHaTransitGateway(gateway *TransitVpc) error {
 
 func (c *Client) EnableHaTransitVpc(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  "enable_transit_ha",
 		"gw_name": gateway.GwName,
 		"eip":     gateway.Eip,
@@ -182,7 +182,7 @@ func (c *Client) EnableHaTransitVpc(gateway *TransitVpc) error {
 
 func (c *Client) AttachTransitGWForHybrid(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_transit_gateway_interface_to_aws_tgw",
 		"gateway_name": gateway.GwName,
 	}
@@ -202,7 +202,7 @@ func (c *Client) AttachTransitGWForHybrid(gateway *TransitVpc) error {
 
 func (c *Client) DetachTransitGWForHybrid(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_transit_gateway_interface_to_aws_tgw",
 		"gateway_name": gateway.GwName,
 	}
@@ -212,7 +212,7 @@ func (c *Client) DetachTransitGWForHybrid(gateway *TransitVpc) error {
 
 func (c *Client) EnableConnectedTransit(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_connected_transit_on_gateway",
 		"gateway_name": gateway.GwName,
 	}
@@ -222,7 +222,7 @@ func (c *Client) EnableConnectedTransit(gateway *TransitVpc) error {
 
 func (c *Client) DisableConnectedTransit(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_connected_transit_on_gateway",
 		"gateway_name": gateway.GwName,
 	}
@@ -232,7 +232,7 @@ func (c *Client) DisableConnectedTransit(gateway *TransitVpc) error {
 
 func (c *Client) EnableGatewayFireNetInterfaces(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_gateway_firenet_interfaces",
 		"gateway_name": gateway.GwName,
 	}
@@ -242,7 +242,7 @@ func (c *Client) EnableGatewayFireNetInterfaces(gateway *TransitVpc) error {
 
 func (c *Client) DisableGatewayFireNetInterfaces(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"action":  "disable_gateway_firenet_interfaces",
 		"gateway": gateway.GwName,
 	}
@@ -253,7 +253,7 @@ func (c *Client) DisableGatewayFireNetInterfaces(gateway *TransitVpc) error {
 func (c *Client) EnableGatewayFireNetInterfacesWithGWLB(gateway *TransitVpc) error {
 	data := map[string]string{
 		"action":       "enable_gateway_firenet_interfaces",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gateway.GwName,
 		"mode":         "gwlb",
 	}
@@ -262,7 +262,7 @@ func (c *Client) EnableGatewayFireNetInterfacesWithGWLB(gateway *TransitVpc) err
 
 func (c *Client) EnableAdvertiseTransitCidr(transitGw *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_advertise_transit_cidr",
 		"gateway_name": transitGw.GwName,
 	}
@@ -278,7 +278,7 @@ func (c *Client) EnableAdvertiseTransitCidr(transitGw *TransitVpc) error {
 
 func (c *Client) DisableAdvertiseTransitCidr(transitGw *TransitVpc) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "disable_advertise_transit_cidr",
 		"gateway_name":           transitGw.GwName,
 		"advertise_transit_cidr": "no",
@@ -289,7 +289,7 @@ func (c *Client) DisableAdvertiseTransitCidr(transitGw *TransitVpc) error {
 
 func (c *Client) SetBgpManualSpokeAdvertisedNetworks(transitGw *TransitVpc) error {
 	form := map[string]string{
-		"CID":                              c.CID,
+		"CID":                              c.cid(),
 		"action":                           "edit_aviatrix_transit_advanced_config",
 		"subaction":                        "bgp_manual_spoke",
 		"gateway_name":                     transitGw.GwName,
@@ -301,7 +301,7 @@ func (c *Client) SetBgpManualSpokeAdvertisedNetworks(transitGw *TransitVpc) erro
 
 func (c *Client) EnableTransitLearnedCidrsApproval(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "enable_transit_learned_cidrs_approval",
 		"gateway_name": gateway.GwName,
 	}
@@ -311,7 +311,7 @@ func (c *Client) EnableTransitLearnedCidrsApproval(gateway *TransitVpc) error {
 
 func (c *Client) DisableTransitLearnedCidrsApproval(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "disable_transit_learned_cidrs_approval",
 		"gateway_name": gateway.GwName,
 	}
@@ -321,7 +321,7 @@ func (c *Client) DisableTransitLearnedCidrsApproval(gateway *TransitVpc) error {
 
 func (c *Client) UpdateTransitPendingApprovedCidrs(gateway *TransitVpc) error {
 	form := map[string]string{
-		"CID":                    c.CID,
+		"CID":                    c.cid(),
 		"action":                 "update_transit_pending_approved_cidrs",
 		"gateway_name":           gateway.GwName,
 		"approved_learned_cidrs": strings.Join(gateway.ApprovedLearnedCidrs, ","),
@@ -338,7 +338,7 @@ func (c *Client) SetBgpPollingTime(transitGateway *TransitVpc, newPollingTime st
 		GatewayName string `form:"gateway_name"`
 		PollingTime string `form:"bgp_polling_time"`
 	}{
-		CID:         c.CID,
+		CID:         c.cid(),
 		Action:      action,
 		GatewayName: transitGateway.GwName,
 		PollingTime: newPollingTime,
@@ -354,7 +354,7 @@ func (c *Client) SetPrependASPath(transitGateway *TransitVpc, prependASPath []st
 		GatewayName   string `form:"gateway_name"`
 		PrependASPath string `form:"bgp_prepend_as_path"`
 	}{
-		CID:           c.CID,
+		CID:           c.cid(),
 		Action:        action,
 		Subaction:     subaction,
 		GatewayName:   transitGateway.GwName,
@@ -370,7 +370,7 @@ func (c *Client) SetLocalASNumber(transitGateway *TransitVpc, localASNumber stri
 		GatewayName   string `form:"gateway_name"`
 		LocalASNumber string `form:"local_as_num"`
 	}{
-		CID:           c.CID,
+		CID:           c.cid(),
 		Action:        action,
 		GatewayName:   transitGateway.GwName,
 		LocalASNumber: localASNumber,
@@ -396,7 +396,7 @@ func (c *Client) SetBgpEcmp(transitGateway *TransitVpc, enabled bool) error {
 		Action      string `form:"action"`
 		GatewayName string `form:"gateway_name"`
 	}{
-		CID:         c.CID,
+		CID:         c.cid(),
 		Action:      action,
 		GatewayName: transitGateway.GwName,
 	}, BasicCheck)
@@ -404,7 +404,7 @@ func (c *Client) SetBgpEcmp(transitGateway *TransitVpc, enabled bool) error {
 
 func (c *Client) GetTransitGatewayAdvancedConfig(transitGateway *TransitVpc) (*TransitGatewayAdvancedConfig, error) {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "list_aviatrix_transit_advanced_config",
 		"transit_gateway_name": transitGateway.GwName,
 	}
@@ -458,7 +458,7 @@ func (c *Client) GetTransitGatewayAdvancedConfig(transitGateway *TransitVpc) (*T
 func (c *Client) SetTransitLearnedCIDRsApprovalMode(gw *TransitVpc, mode string) error {
 	data := map[string]string{
 		"action":       "set_transit_learned_cidrs_approval_mode",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"gateway_name": gw.GwName,
 		"mode":         mode,
 	}
@@ -468,7 +468,7 @@ func (c *Client) SetTransitLearnedCIDRsApprovalMode(gw *TransitVpc, mode string)
 func (c *Client) EnableTransitConnectionLearnedCIDRApproval(gwName, connName string) error {
 	data := map[string]string{
 		"action":          "enable_transit_connection_learned_cidrs_approval",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"gateway_name":    gwName,
 		"connection_name": connName,
 	}
@@ -478,7 +478,7 @@ func (c *Client) EnableTransitConnectionLearnedCIDRApproval(gwName, connName str
 func (c *Client) DisableTransitConnectionLearnedCIDRApproval(gwName, connName string) error {
 	data := map[string]string{
 		"action":          "disable_transit_connection_learned_cidrs_approval",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"gateway_name":    gwName,
 		"connection_name": connName,
 	}
@@ -488,7 +488,7 @@ func (c *Client) DisableTransitConnectionLearnedCIDRApproval(gwName, connName st
 func (c *Client) UpdateTransitConnectionPendingApprovedCidrs(gwName, connName string, approvedCidrs []string) error {
 	data := map[string]string{
 		"action":                            "update_transit_connection_pending_approved_cidrs",
-		"CID":                               c.CID,
+		"CID":                               c.cid(),
 		"gateway_name":                      gwName,
 		"connection_name":                   connName,
 		"connection_approved_learned_cidrs": strings.Join(approvedCidrs, ","),
@@ -499,7 +499,7 @@ func (c *Client) UpdateTransitConnectionPendingApprovedCidrs(gwName, connName st
 func (c *Client) EditTransitConnectionBGPManualAdvertiseCIDRs(gwName, connName string, cidrs []string) error {
 	data := map[string]string{
 		"action":                                "edit_transit_connection_bgp_manual_advertise_cidrs",
-		"CID":                                   c.CID,
+		"CID":                                   c.cid(),
 		"gateway_name":                          gwName,
 		"connection_name":                       connName,
 		"connection_bgp_manual_advertise_cidrs": strings.Join(cidrs, ","),
@@ -512,7 +512,7 @@ func (c *Client) ChangeBgpHoldTime(gwName string, holdTime int) error {
 		"action":        "change_bgp_hold_time",
 		"gateway_name":  gwName,
 		"bgp_hold_time": strconv.Itoa(holdTime),
-		"CID":           c.CID,
+		"CID":           c.cid(),
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
@@ -521,7 +521,7 @@ func (c *Client) EnableSummarizeCidrToTgw(gwName string) error {
 	data := map[string]string{
 		"action":       "enable_transit_summarize_cidr_to_tgw",
 		"gateway_name": gwName,
-		"CID":          c.CID,
+		"CID":          c.cid(),
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
@@ -530,7 +530,7 @@ func (c *Client) DisableSummarizeCidrToTgw(gwName string) error {
 	data := map[string]string{
 		"action":       "disable_transit_summarize_cidr_to_tgw",
 		"gateway_name": gwName,
-		"CID":          c.CID,
+		"CID":          c.cid(),
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
@@ -539,7 +539,7 @@ func (c *Client) EnableMultitierTransit(gwName string) error {
 	data := map[string]string{
 		"action":       "enable_multitier_transit",
 		"gateway_name": gwName,
-		"CID":          c.CID,
+		"CID":          c.cid(),
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
@@ -548,7 +548,7 @@ func (c *Client) DisableMultitierTransit(gwName string) error {
 	data := map[string]string{
 		"action":       "disable_multitier_transit",
 		"gateway_name": gwName,
-		"CID":          c.CID,
+		"CID":          c.cid(),
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
 }
@@ -556,7 +556,7 @@ func (c *Client) DisableMultitierTransit(gwName string) error {
 func (c *Client) EditTransitConnectionRemoteSubnet(vpcId, connName, remoteSubnet string) error {
 	data := map[string]string{
 		"action":            "edit_site2cloud_conn",
-		"CID":               c.CID,
+		"CID":               c.cid(),
 		"vpc_id":            vpcId,
 		"conn_name":         connName,
 		"network_type":      "2",
@@ -567,7 +567,7 @@ func (c *Client) EditTransitConnectionRemoteSubnet(vpcId, connName, remoteSubnet
 
 func (c *Client) GetBgpLanIPList(transitGateway *TransitVpc) (*TransitGatewayBgpLanIpInfo, error) {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "list_aviatrix_transit_advanced_config",
 		"transit_gateway_name": transitGateway.GwName,
 	}
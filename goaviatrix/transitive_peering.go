@@ -28,7 +28,7 @@ type TransPeerListResp struct {
 }
 
 func (c *Client) CreateTransPeer(transPeer *TransPeer) error {
-	transPeer.CID = c.CID
+	transPeer.CID = c.cid()
 	transPeer.Action = "add_extended_vpc_peer"
 
 	return c.PostAPI(transPeer.Action, transPeer, BasicCheck)
@@ -36,7 +36,7 @@ func (c *Client) CreateTransPeer(transPeer *TransPeer) error {
 
 func (c *Client) GetTransPeer(transPeer *TransPeer) (*TransPeer, error) {
 	// TODO: use GetAPI - need API details
-	transPeer.CID = c.CID
+	transPeer.CID = c.cid()
 	transPeer.Action = "list_extended_vpc_peer"
 	resp, err := c.Post(c.baseURL, transPeer)
 	if err != nil {
@@ -69,7 +69,7 @@ func (c *Client) UpdateTransPeer(transPeer *TransPeer) error {
 }
 
 func (c *Client) DeleteTransPeer(transPeer *TransPeer) error {
-	transPeer.CID = c.CID
+	transPeer.CID = c.cid()
 	transPeer.Action = "delete_extended_vpc_peer"
 
 	return c.PostAPI(transPeer.Action, transPeer, BasicCheck)
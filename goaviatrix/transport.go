@@ -0,0 +1,47 @@
+package goaviatrix
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Defaults of the connection settings of the transport to the controller. Connections are kept alive
+// and dropped after a short idle time, so that firewalls between the provider and the controller do
+// not silently drop idle connections that the next request would stall on.
+const (
+	DefaultDialTimeout         = 30 * time.Second
+	DefaultKeepAlive           = 15 * time.Second
+	DefaultIdleConnTimeout     = 30 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// TransportSettings are the connection settings of the transport to the controller. Zero values
+// use the defaults.
+type TransportSettings struct {
+	DialTimeout     time.Duration // timeout for establishing a connection
+	KeepAlive       time.Duration // interval of TCP keep-alive probes on open connections
+	IdleConnTimeout time.Duration // time after which idle connections are closed
+}
+
+// ConfigureTransport applies the connection settings to tr.
+func ConfigureTransport(tr *http.Transport, settings TransportSettings) {
+	if settings.DialTimeout == 0 {
+		settings.DialTimeout = DefaultDialTimeout
+	}
+	if settings.KeepAlive == 0 {
+		settings.KeepAlive = DefaultKeepAlive
+	}
+	if settings.IdleConnTimeout == 0 {
+		settings.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+
+	tr.DialContext = (&net.Dialer{
+		Timeout:   settings.DialTimeout,
+		KeepAlive: settings.KeepAlive,
+	}).DialContext
+	tr.IdleConnTimeout = settings.IdleConnTimeout
+	if tr.TLSHandshakeTimeout == 0 {
+		tr.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	}
+}
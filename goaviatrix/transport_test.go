@@ -0,0 +1,33 @@
+package goaviatrix
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConfigureTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		settings     TransportSettings
+		wantIdleConn time.Duration
+	}{
+		{"defaults", TransportSettings{}, DefaultIdleConnTimeout},
+		{"idle timeout", TransportSettings{IdleConnTimeout: time.Minute}, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &http.Transport{}
+			ConfigureTransport(tr, tt.settings)
+			if tr.DialContext == nil {
+				t.Errorf("ConfigureTransport() did not set DialContext")
+			}
+			if tr.IdleConnTimeout != tt.wantIdleConn {
+				t.Errorf("ConfigureTransport() IdleConnTimeout = %v, want %v", tr.IdleConnTimeout, tt.wantIdleConn)
+			}
+			if tr.TLSHandshakeTimeout != DefaultTLSHandshakeTimeout {
+				t.Errorf("ConfigureTransport() TLSHandshakeTimeout = %v, want %v", tr.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout)
+			}
+		})
+	}
+}
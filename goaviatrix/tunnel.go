@@ -27,7 +27,7 @@ type TunnelListResp struct {
 
 func (c *Client) CreateTunnel(tunnel *Tunnel) error {
 	form := map[string]string{
-		"CID":        c.CID,
+		"CID":        c.cid(),
 		"action":     "peer_vpc_pair",
 		"vpc_name1":  tunnel.VpcName1,
 		"vpc_name2":  tunnel.VpcName2,
@@ -39,7 +39,7 @@ func (c *Client) CreateTunnel(tunnel *Tunnel) error {
 
 func (c *Client) GetTunnel(tunnel *Tunnel) (*Tunnel, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_peer_vpc_pairs",
 	}
 
@@ -67,7 +67,7 @@ func (c *Client) UpdateTunnel(tunnel *Tunnel) error {
 
 func (c *Client) DeleteTunnel(tunnel *Tunnel) error {
 	form := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "unpeer_vpc_pair",
 		"vpc_name1": tunnel.VpcName1,
 		"vpc_name2": tunnel.VpcName2,
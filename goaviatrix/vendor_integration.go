@@ -39,7 +39,7 @@ type FirewallManager struct {
 
 func (c *Client) EditFireNetFirewallVendorInfo(vendorInfo *VendorInfo) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "edit_firenet_firewall_vendor_info",
 		"vpc_id":          vendorInfo.VpcID,
 		"firewall_id":     vendorInfo.InstanceID,
@@ -57,7 +57,7 @@ func (c *Client) EditFireNetFirewallVendorInfo(vendorInfo *VendorInfo) error {
 
 func (c *Client) ShowFireNetFirewallVendorConfig(vendorInfo *VendorInfo) error {
 	form := map[string]string{
-		"CID":         c.CID,
+		"CID":         c.cid(),
 		"action":      "show_firenet_firewall_vendor_config",
 		"vpc_id":      vendorInfo.VpcID,
 		"firewall_id": vendorInfo.InstanceID,
@@ -70,7 +70,7 @@ func (c *Client) ShowFireNetFirewallVendorConfig(vendorInfo *VendorInfo) error {
 func (c *Client) EditFireNetFirewallManagerVendorInfo(ctx context.Context, firewallManager *FirewallManager) error {
 	params := map[string]string{
 		"action":          "edit_firenet_firewall_manager_vendor_info",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"vpc_id":          firewallManager.VpcID,
 		"gw_name":         firewallManager.GatewayName,
 		"firewall_vendor": firewallManager.VendorType,
@@ -88,7 +88,7 @@ func (c *Client) EditFireNetFirewallManagerVendorInfo(ctx context.Context, firew
 func (c *Client) SyncFireNetFirewallManagerVendorConfig(ctx context.Context, firewallManager *FirewallManager) error {
 	params := map[string]string{
 		"action":  "show_firenet_firewall_vendor_config",
-		"CID":     c.CID,
+		"CID":     c.cid(),
 		"vpc_id":  firewallManager.VpcID,
 		"gw_name": firewallManager.GatewayName,
 		"sync":    "true",
@@ -100,7 +100,7 @@ func (c *Client) SyncFireNetFirewallManagerVendorConfig(ctx context.Context, fir
 func (c *Client) EditFireNetFirewallVendorInfoWithPrivateKey(vendorInfo *VendorInfo) error {
 	params := map[string]string{
 		"action":          "edit_firenet_firewall_vendor_info",
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"vpc_id":          vendorInfo.VpcID,
 		"firewall_id":     vendorInfo.InstanceID,
 		"firewall_name":   vendorInfo.FirewallName,
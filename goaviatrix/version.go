@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -65,8 +66,10 @@ func (av *AviatrixVersion) String(includeBuild bool) string {
 
 // AsyncUpgrade will upgrade controller asynchronously
 func (c *Client) AsyncUpgrade(version *Version, upgradeGateways bool) error {
+	defer c.invalidateControllerVersion()
+
 	form := map[string]string{
-		"CID":   c.CID,
+		"CID":   c.cid(),
 		"async": "true", // indicates an async command
 	}
 	if upgradeGateways {
@@ -101,7 +104,7 @@ func (c *Client) AsyncUpgrade(version *Version, upgradeGateways bool) error {
 	requestID := data.Result
 	form = map[string]string{
 		"action": "check_upgrade_status",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"id":     strconv.Itoa(requestID),
 		"pos":    "0",
 	}
@@ -149,19 +152,148 @@ func (c *Client) AsyncUpgrade(version *Version, upgradeGateways bool) error {
 }
 
 func (c *Client) UpgradeGateway(gateway *Gateway) error {
+	return c.UpgradeGatewayContext(context.Background(), gateway)
+}
+
+// UpgradeGatewayContext upgrades the gateway, aborting the request when ctx is done
+func (c *Client) UpgradeGatewayContext(ctx context.Context, gateway *Gateway) error {
 	form := map[string]string{
 		"action":           "upgrade_selected_gateway",
-		"CID":              c.CID,
+		"CID":              c.cid(),
 		"gateway_list":     gateway.GwName,
 		"software_version": gateway.SoftwareVersion,
 		"image_version":    gateway.ImageVersion,
 	}
-	return c.PostAPI(form["action"], form, BasicCheck)
+	return c.PostAPIContext(ctx, form["action"], form, BasicCheck)
+}
+
+// GetGatewayUpgradeStatus returns the status of the last software upgrade of the managed CloudN (CaaG)
+// gwName, e.g. 'success', 'in_progress' or 'failed'. It returns an empty status if the gateway was
+// never upgraded.
+func (c *Client) GetGatewayUpgradeStatus(ctx context.Context, gwName string) (string, error) {
+	type Result struct {
+		Status string `json:"status"`
+	}
+	type Resp struct {
+		Return  bool   `json:"return"`
+		Results Result `json:"results"`
+		Reason  string `json:"reason"`
+	}
+	var data Resp
+	form := map[string]string{
+		"CID":          c.cid(),
+		"action":       "get_gateway_upgrade_status",
+		"gateway_name": gwName,
+	}
+	err := c.GetAPIContext(ctx, &data, form["action"], form, BasicCheck)
+	if err != nil {
+		return "", err
+	}
+	// some controller versions report e.g. 'In Progress'
+	status := strings.ToLower(strings.TrimSpace(data.Results.Status))
+	return strings.Join(strings.Fields(status), "_"), nil
+}
+
+// ErrGatewayUpgradeFailed is returned by WaitForGatewayVersion when the controller reports that the
+// upgrade of the gateway failed. Check for it with errors.Is.
+var ErrGatewayUpgradeFailed = errors.New("gateway upgrade failed")
+
+var (
+	// gatewayVersionPollMinInterval is the time between the first two checks of WaitForGatewayVersion
+	gatewayVersionPollMinInterval = 5 * time.Second
+	// gatewayVersionPollInterval is the longest time between two checks of WaitForGatewayVersion
+	gatewayVersionPollInterval = 30 * time.Second
+)
+
+// WaitForGatewayVersion polls the software version reported for the managed CloudN (CaaG) gwName
+// until it matches targetVersion, or returns an error once timeout has elapsed or ctx is done. The
+// time between two checks doubles up to gatewayVersionPollInterval. If the controller reports that
+// the upgrade failed, it returns an error wrapping ErrGatewayUpgradeFailed right away. A failure is only
+// taken into account once the upgrade started, i.e. the controller reported it in progress or a software
+// version other than the first one, as the status may still be the one of a previous upgrade. The errors
+// returned when ctx is done wrap ctx.Err(), so a canceled wait can be told apart with errors.Is.
+// A targetVersion without a build number, e.g. "6.5", matches any build of that release.
+func (c *Client) WaitForGatewayVersion(ctx context.Context, gwName, targetVersion string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var currentVersion, initialVersion string
+	upgradeStarted := false
+	interval := gatewayVersionPollMinInterval
+	for {
+		device, err := c.GetDevice(ctx, &Device{Name: gwName})
+		if err != nil {
+			// the controller can be briefly unavailable while the upgrade is applied
+			log.WithFields(log.Fields{
+				"gateway": gwName,
+				"error":   err,
+			}).Warn("could not get software version of gateway, will retry")
+		} else {
+			currentVersion = device.SoftwareVersion
+			if initialVersion == "" {
+				initialVersion = currentVersion
+			}
+			upgradeStarted = upgradeStarted || currentVersion != initialVersion
+			if SoftwareVersionMatches(currentVersion, targetVersion) {
+				log.Infof("gateway %s is running the target software version %s", gwName, currentVersion)
+				return nil
+			}
+			log.Infof("waiting for upgrade of gateway %s: current software version %q, target software version %s",
+				gwName, currentVersion, targetVersion)
+
+			// the upgrade status only tells whether to stop waiting early, so errors reading it are ignored
+			status, err := c.GetGatewayUpgradeStatus(ctx, gwName)
+			switch {
+			case err != nil:
+				log.WithFields(log.Fields{
+					"gateway": gwName,
+					"error":   err,
+				}).Warn("could not get upgrade status of gateway")
+			case status == "in_progress":
+				upgradeStarted = true
+			case status == "failed" && !upgradeStarted:
+				log.Infof("ignoring failed upgrade status of gateway %s until its upgrade to %s started, "+
+					"it may be left by a previous upgrade", gwName, targetVersion)
+			case status == "failed":
+				return fmt.Errorf("%w: controller reports the upgrade of gateway %s to software version %s failed, "+
+					"current software version %q", ErrGatewayUpgradeFailed, gwName, targetVersion, currentVersion)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return fmt.Errorf("stopped waiting for gateway %s to reach software version %s, last reported version %q: %w",
+					gwName, targetVersion, currentVersion, ctx.Err())
+			}
+			return fmt.Errorf("gateway %s did not reach software version %s within %s, last reported version %q: %w",
+				gwName, targetVersion, timeout, currentVersion, ctx.Err())
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > gatewayVersionPollInterval {
+			interval = gatewayVersionPollInterval
+		}
+	}
+}
+
+// SoftwareVersionMatches returns true if the current software version is the target version. A target
+// without a build number, e.g. "6.5", matches every build of that release.
+func SoftwareVersionMatches(current, target string) bool {
+	currentRelease, _, errCurrent := ParseVersion(current)
+	targetRelease, targetVersion, errTarget := ParseVersion(target)
+	if errCurrent != nil || errTarget != nil || currentRelease == "" {
+		return false
+	}
+	if !targetVersion.HasBuild {
+		return currentRelease == targetRelease
+	}
+	compare, err := CompareSoftwareVersions(current, target)
+	return err == nil && compare == 0
 }
 
 func (c *Client) GetCurrentVersion() (string, *AviatrixVersion, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_version_info",
 	}
 
@@ -180,10 +312,45 @@ func (c *Client) GetCurrentVersion() (string, *AviatrixVersion, error) {
 	return curVersion, aVer, nil
 }
 
+// GetControllerVersion returns the full software version of the controller, e.g. "6.5.2835". The version
+// is cached on the client after the first call, until the controller is upgraded through the client.
+func (c *Client) GetControllerVersion() (string, error) {
+	c.controllerVersionMu.Lock()
+	defer c.controllerVersionMu.Unlock()
+
+	if c.controllerVersion != "" {
+		return c.controllerVersion, nil
+	}
+
+	form := map[string]string{
+		"CID":    c.cid(),
+		"action": "list_version_info",
+	}
+	var data VersionInfoResp
+	err := c.GetAPI(&data, form["action"], form, BasicCheck)
+	if err != nil {
+		return "", err
+	}
+
+	version := strings.TrimPrefix(data.Results.CurrentVersion, "UserConnect-")
+	if _, _, err := ParseVersion(version); err != nil || version == "" {
+		return "", fmt.Errorf("could not parse controller version %q: %v", data.Results.CurrentVersion, err)
+	}
+	c.controllerVersion = version
+	return version, nil
+}
+
+// invalidateControllerVersion clears the version cached by GetControllerVersion
+func (c *Client) invalidateControllerVersion() {
+	c.controllerVersionMu.Lock()
+	c.controllerVersion = ""
+	c.controllerVersionMu.Unlock()
+}
+
 func (c *Client) GetVersionInfo() (*VersionInfo, error) {
 	form := map[string]string{
 		"action": "list_version_info",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	var data struct {
 		Results struct {
@@ -213,7 +380,7 @@ func (c *Client) Pre32Upgrade() error {
 	privateBaseURL := strings.Replace(c.baseURL, "/v1/api", "/v1/backend1", 1)
 	params := &Version{
 		Action: "userconnect_release",
-		CID:    c.CID,
+		CID:    c.cid(),
 	}
 	path := privateBaseURL
 	for i := 0; ; i++ {
@@ -240,7 +407,7 @@ func (c *Client) Pre32Upgrade() error {
 
 func (c *Client) GetLatestVersion() (string, error) {
 	form := map[string]string{
-		"CID":            c.CID,
+		"CID":            c.cid(),
 		"action":         "list_version_info",
 		"latest_version": strconv.FormatBool(true),
 	}
@@ -294,7 +461,7 @@ func ParseVersion(version string) (string, *AviatrixVersion, error) {
 func (c *Client) GetCompatibleImageVersion(ctx context.Context, cloudType int, softwareVersion string) (string, error) {
 	form := map[string]string{
 		"action":           "get_compatible_image_version",
-		"CID":              c.CID,
+		"CID":              c.cid(),
 		"software_version": softwareVersion,
 		"cloud_type":       strconv.Itoa(cloudType),
 	}
@@ -310,6 +477,54 @@ func (c *Client) GetCompatibleImageVersion(ctx context.Context, cloudType int, s
 	return data.Results.ImageVersion, nil
 }
 
+// ListGatewayImageVersions returns the software versions the controller can install, newest first.
+// If gwName is set, only the versions the managed CloudN (CaaG) gwName can be upgraded or downgraded
+// to are returned.
+func (c *Client) ListGatewayImageVersions(ctx context.Context, gwName string) ([]string, error) {
+	form := map[string]string{
+		"CID":    c.cid(),
+		"action": "list_gateway_image_versions",
+	}
+	if gwName != "" {
+		form["gateway_name"] = gwName
+	}
+	var data struct {
+		Results []string `json:"results"`
+	}
+	if err := c.GetAPIContext(ctx, &data, form["action"], form, BasicCheck); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(data.Results))
+	var versions []string
+	for _, version := range data.Results {
+		version = strings.TrimSpace(version)
+		if version == "" || seen[version] {
+			continue
+		}
+		seen[version] = true
+		versions = append(versions, version)
+	}
+	sortSoftwareVersionsDesc(versions)
+	return versions, nil
+}
+
+// sortSoftwareVersionsDesc sorts versions newest first. Versions that can not be parsed are kept last,
+// in their original order.
+func sortSoftwareVersionsDesc(versions []string) {
+	valid := func(version string) bool {
+		_, _, err := ParseVersion(version)
+		return err == nil
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		if !valid(versions[i]) || !valid(versions[j]) {
+			return valid(versions[i]) && !valid(versions[j])
+		}
+		compare, _ := CompareSoftwareVersions(versions[i], versions[j])
+		return compare > 0
+	})
+}
+
 // CompareSoftwareVersions first return value will be
 // less than 0 if a < b
 // equal to 0  if a == b
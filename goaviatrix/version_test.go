@@ -1,8 +1,15 @@
 package goaviatrix
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseVersion(t *testing.T) {
@@ -152,3 +159,198 @@ func TestCompareSoftwareVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForGatewayVersion(t *testing.T) {
+	gatewayVersionPollMinInterval, gatewayVersionPollInterval = time.Millisecond, 4*time.Millisecond
+	defer func() { gatewayVersionPollMinInterval, gatewayVersionPollInterval = 5*time.Second, 30*time.Second }()
+
+	versions := []string{"6.4.2995", "", "6.5.892"}
+	statuses := []string{"in_progress"}
+	calls, statusCalls := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") == "get_gateway_upgrade_status" {
+			status := statuses[len(statuses)-1]
+			if statusCalls < len(statuses) {
+				status = statuses[statusCalls]
+			}
+			statusCalls++
+			respondJSON(fmt.Sprintf(`{"return":true,"results":{"status":%q}}`, status))(w)
+			return
+		}
+		version := versions[len(versions)-1]
+		if calls < len(versions) {
+			version = versions[calls]
+		}
+		calls++
+		respondJSON(fmt.Sprintf(`{"return":true,"results":[{"rgw_name":"caag-1","software_version":%q}]}`, version))(w)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	if err := client.WaitForGatewayVersion(context.Background(), "caag-1", "6.5", time.Minute); err != nil {
+		t.Fatalf("WaitForGatewayVersion() unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("WaitForGatewayVersion() polled %d times, want 3", calls)
+	}
+
+	err := client.WaitForGatewayVersion(context.Background(), "caag-1", "6.6.100", 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), `last reported version "6.5.892"`) {
+		t.Errorf("WaitForGatewayVersion() error = %v, want timeout error with last reported version", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForGatewayVersion() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	err = client.WaitForGatewayVersion(ctx, "caag-1", "6.6.100", time.Minute)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForGatewayVersion() with canceled context error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WaitForGatewayVersion() returned %s after the context was canceled", elapsed)
+	}
+
+	// a failure left by a previous upgrade does not stop waiting before the upgrade started
+	statuses, statusCalls = []string{"failed"}, 0
+	err = client.WaitForGatewayVersion(context.Background(), "caag-1", "6.6.100", 20*time.Millisecond)
+	if errors.Is(err, ErrGatewayUpgradeFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForGatewayVersion() with a previous failed upgrade error = %v, want context.DeadlineExceeded", err)
+	}
+
+	statuses, statusCalls, calls = []string{"failed", "in_progress", "failed"}, 0, 0
+	err = client.WaitForGatewayVersion(context.Background(), "caag-1", "6.6.100", time.Minute)
+	if !errors.Is(err, ErrGatewayUpgradeFailed) {
+		t.Errorf("WaitForGatewayVersion() error = %v, want ErrGatewayUpgradeFailed", err)
+	}
+	if calls != 3 {
+		t.Errorf("WaitForGatewayVersion() polled %d times until the upgrade failed, want 3", calls)
+	}
+
+	// a new software version also shows that the upgrade started
+	versions, statuses, statusCalls, calls = []string{"6.5.892", "6.6.50"}, []string{"failed"}, 0, 0
+	err = client.WaitForGatewayVersion(context.Background(), "caag-1", "6.6.100", time.Minute)
+	if !errors.Is(err, ErrGatewayUpgradeFailed) {
+		t.Errorf("WaitForGatewayVersion() after a new software version error = %v, want ErrGatewayUpgradeFailed", err)
+	}
+	if calls != 2 {
+		t.Errorf("WaitForGatewayVersion() polled %d times until the upgrade failed, want 2", calls)
+	}
+}
+
+func TestGetGatewayUpgradeStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   string
+	}{
+		{"success", "success", "success"},
+		{"in progress", "In Progress", "in_progress"},
+		{"never upgraded", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if name := r.URL.Query().Get("gateway_name"); name != "caag-1" {
+					t.Errorf("GetGatewayUpgradeStatus() sent gateway_name %q, want %q", name, "caag-1")
+				}
+				respondJSON(fmt.Sprintf(`{"return":true,"results":{"status":%q}}`, tt.status))(w)
+			}))
+			defer srv.Close()
+
+			got, err := newTestClient(srv).GetGatewayUpgradeStatus(context.Background(), "caag-1")
+			if err != nil {
+				t.Fatalf("GetGatewayUpgradeStatus() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetGatewayUpgradeStatus() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListGatewayImageVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		gwName string
+	}{
+		{"all versions", ""},
+		{"versions of a device", "caag-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if action := query.Get("action"); action != "list_gateway_image_versions" {
+					t.Errorf("ListGatewayImageVersions() sent action %q", action)
+				}
+				if _, sent := query["gateway_name"]; sent != (tt.gwName != "") || query.Get("gateway_name") != tt.gwName {
+					t.Errorf("ListGatewayImageVersions() sent gateway_name %q, want %q", query.Get("gateway_name"), tt.gwName)
+				}
+				respondJSON(`{"return":true,"results":["6.4.2995","UserConnect-6.6.5224","custom"," 6.5.892","6.4.2995","6.5"]}`)(w)
+			}))
+			defer srv.Close()
+
+			got, err := newTestClient(srv).ListGatewayImageVersions(context.Background(), tt.gwName)
+			if err != nil {
+				t.Fatalf("ListGatewayImageVersions() unexpected error: %v", err)
+			}
+			want := []string{"UserConnect-6.6.5224", "6.5", "6.5.892", "6.4.2995", "custom"}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ListGatewayImageVersions() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSoftwareVersionMatches(t *testing.T) {
+	tests := []struct {
+		current string
+		target  string
+		want    bool
+	}{
+		{"6.5.892", "6.5.892", true},
+		{"UserConnect-6.5.892", "6.5", true},
+		{"6.5.892", "6.5.1000", false},
+		{"6.4.2995", "6.5", false},
+		{"", "6.5", false},
+	}
+	for _, tt := range tests {
+		if got := SoftwareVersionMatches(tt.current, tt.target); got != tt.want {
+			t.Errorf("SoftwareVersionMatches(%q, %q) = %v, want %v", tt.current, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestGetControllerVersion(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		respondJSON(`{"return":true,"results":{"current_version":"UserConnect-6.5.2835"}}`)(w)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	for i := 0; i < 2; i++ {
+		version, err := client.GetControllerVersion()
+		if err != nil {
+			t.Fatalf("GetControllerVersion() unexpected error: %v", err)
+		}
+		if version != "6.5.2835" {
+			t.Errorf("GetControllerVersion() got = %q, want %q", version, "6.5.2835")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("GetControllerVersion() called the controller %d times, want 1", calls)
+	}
+
+	client.invalidateControllerVersion()
+	if _, err := client.GetControllerVersion(); err != nil {
+		t.Fatalf("GetControllerVersion() unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("GetControllerVersion() after invalidation called the controller %d times in total, want 2", calls)
+	}
+}
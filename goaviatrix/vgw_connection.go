@@ -70,7 +70,7 @@ type VGWConnBgpManualSpokeAdvertisedNetworksResp struct {
 
 func (c *Client) CreateVGWConn(vgwConn *VGWConn) error {
 	form := map[string]string{
-		"CID":                  c.CID,
+		"CID":                  c.cid(),
 		"action":               "connect_transit_gw_to_vgw",
 		"vpc_id":               vgwConn.VPCId,
 		"connection_name":      vgwConn.ConnName,
@@ -86,7 +86,7 @@ func (c *Client) CreateVGWConn(vgwConn *VGWConn) error {
 
 func (c *Client) GetVGWConn(vgwConn *VGWConn) (*VGWConn, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_vgw_connections",
 	}
 
@@ -116,7 +116,7 @@ func (c *Client) UpdateVGWConn(vgwConn *VGWConn) error {
 
 func (c *Client) DeleteVGWConn(vgwConn *VGWConn) error {
 	form := map[string]string{
-		"CID":             c.CID,
+		"CID":             c.cid(),
 		"action":          "disconnect_transit_gw_from_vgw",
 		"vpc_id":          vgwConn.VPCId,
 		"connection_name": vgwConn.ConnName,
@@ -126,7 +126,7 @@ func (c *Client) DeleteVGWConn(vgwConn *VGWConn) error {
 
 func (c *Client) GetVGWConnDetail(vgwConn *VGWConn) (*VGWConn, error) {
 	params := map[string]string{
-		"CID":       c.CID,
+		"CID":       c.cid(),
 		"action":    "get_site2cloud_conn_detail",
 		"vpc_id":    vgwConn.VPCId,
 		"conn_name": vgwConn.ConnName,
@@ -169,7 +169,7 @@ func (c *Client) EditVgwConnectionASPathPrepend(vgwConn *VGWConn, prependASPath
 		ConnectionName string `form:"connection_name"`
 		PrependASPath  string `form:"connection_as_path_prepend"`
 	}{
-		CID:            c.CID,
+		CID:            c.cid(),
 		Action:         action,
 		GatewayName:    vgwConn.GwName,
 		ConnectionName: vgwConn.ConnName,
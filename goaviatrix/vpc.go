@@ -76,7 +76,7 @@ func (c *Client) CreateVpc(vpc *Vpc) error {
 		return errors.New(("url Parsing failed for create_custom_vpc ") + err.Error())
 	}
 	createCustomVpc := url.Values{}
-	createCustomVpc.Add("CID", c.CID)
+	createCustomVpc.Add("CID", c.cid())
 	createCustomVpc.Add("action", "create_custom_vpc")
 	createCustomVpc.Add("cloud_type", strconv.Itoa(vpc.CloudType))
 	createCustomVpc.Add("account_name", vpc.AccountName)
@@ -136,7 +136,7 @@ func (c *Client) CreateVpc(vpc *Vpc) error {
 // If the vpc does not exist, ErrNotFound is returned.
 func (c *Client) GetVpcCloudTypeById(ID string) (int, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_custom_vpcs",
 	}
 
@@ -161,7 +161,7 @@ func (c *Client) GetVpcCloudTypeById(ID string) (int, error) {
 func (c *Client) GetCloudTypeFromVpcID(vpcID string) (int, error) {
 	data := map[string]string{
 		"action": "list_custom_vpcs",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 	}
 	var respData VpcResp
 	err := c.GetAPI(&respData, data["action"], data, BasicCheck)
@@ -181,7 +181,7 @@ func (c *Client) GetCloudTypeFromVpcID(vpcID string) (int, error) {
 func (c *Client) GetVpc(vpc *Vpc) (*Vpc, error) {
 	form := map[string]string{
 		"action":   "get_custom_vpc_by_name",
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"vpc_name": vpc.Name,
 	}
 	var data GetVpcByNameResp
@@ -224,7 +224,7 @@ func (c *Client) GetVpc(vpc *Vpc) (*Vpc, error) {
 
 func (c *Client) GetVpcRouteTableIDs(vpc *Vpc) ([]string, error) {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "list_vpc_route_tables",
 		"vpc_id":       vpc.VpcID,
 		"account_name": vpc.AccountName,
@@ -264,7 +264,7 @@ func (c *Client) UpdateVpc(vpc *Vpc) error {
 
 func (c *Client) DeleteVpc(vpc *Vpc) error {
 	form := map[string]string{
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"action":       "delete_custom_vpc",
 		"account_name": vpc.AccountName,
 		"pool_name":    vpc.Name,
@@ -276,7 +276,7 @@ func (c *Client) DeleteVpc(vpc *Vpc) error {
 func (c *Client) EnableNativeAwsGwlbFirenet(vpc *Vpc) error {
 	data := map[string]string{
 		"action":       "enable_native_aws_gwlb_firenet",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"account_name": vpc.AccountName,
 		"region":       vpc.Region,
 		"vpc_id":       vpc.VpcID,
@@ -287,7 +287,7 @@ func (c *Client) EnableNativeAwsGwlbFirenet(vpc *Vpc) error {
 func (c *Client) DisableNativeAwsGwlbFirenet(vpc *Vpc) error {
 	data := map[string]string{
 		"action": "disable_native_aws_gwlb_firenet",
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"vpc_id": vpc.VpcID,
 	}
 	return c.PostAPI(data["action"], data, BasicCheck)
@@ -296,7 +296,7 @@ func (c *Client) DisableNativeAwsGwlbFirenet(vpc *Vpc) error {
 func (c *Client) ListOciVpcAvailabilityDomains(vpc *Vpc) ([]string, error) {
 	params := map[string]string{
 		"action":       "list_oci_vpc_availability_domains",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"account_name": vpc.AccountName,
 		"region":       vpc.Region,
 		"vpc_id":       vpc.VpcID,
@@ -321,7 +321,7 @@ func (c *Client) ListOciVpcAvailabilityDomains(vpc *Vpc) ([]string, error) {
 func (c *Client) ListOciVpcFaultDomains(vpc *Vpc) ([]string, error) {
 	params := map[string]string{
 		"action":       "list_oci_vpc_fault_domains",
-		"CID":          c.CID,
+		"CID":          c.cid(),
 		"account_name": vpc.AccountName,
 		"region":       vpc.Region,
 		"vpc_id":       vpc.VpcID,
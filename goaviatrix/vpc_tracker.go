@@ -44,7 +44,7 @@ type VpcTrackerResp struct {
 // GetVpcTracker retrieves the list of VPC's from the 'VPC Tracker' feature.
 func (c *Client) GetVpcTracker() ([]*VpcTracker, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "cloud_network_info",
 		"cache":    "no",
 		"show_all": "yes",
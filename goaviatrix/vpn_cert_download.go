@@ -18,20 +18,20 @@ type GetVPNCertDownloadStatusResp struct {
 }
 
 func (c *Client) EnableVPNCertDownload(vpnCertDownload *VPNCertDownload) error {
-	vpnCertDownload.CID = c.CID
+	vpnCertDownload.CID = c.cid()
 	vpnCertDownload.Action = "enable_vpn_client_download"
 	return c.PostAPI(vpnCertDownload.Action, vpnCertDownload, BasicCheck)
 }
 
 func (c *Client) DisableVPNCertDownload() error {
 	var vpnCertDownload VPNCertDownload
-	vpnCertDownload.CID = c.CID
+	vpnCertDownload.CID = c.cid()
 	vpnCertDownload.Action = "disable_vpn_client_download"
 	return c.PostAPI(vpnCertDownload.Action, vpnCertDownload, BasicCheck)
 }
 
 func (c *Client) GetVPNCertDownloadStatus() (*GetVPNCertDownloadStatusResp, error) {
-	params := map[string]string{"CID": c.CID, "action": "get_allow_downloading_vpn_client_status"}
+	params := map[string]string{"CID": c.cid(), "action": "get_allow_downloading_vpn_client_status"}
 	var data GetVPNCertDownloadStatusResp
 	err := c.GetAPI(&data, "get_allow_downloading_vpn_client_status", params, BasicCheck)
 	if err != nil {
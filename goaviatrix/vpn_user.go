@@ -32,7 +32,7 @@ type VPNUserInfo struct {
 
 func (c *Client) CreateVPNUser(vpnUser *VPNUser) error {
 	form := map[string]string{
-		"CID":           c.CID,
+		"CID":           c.cid(),
 		"action":        "add_vpn_user",
 		"username":      vpnUser.UserName,
 		"user_email":    vpnUser.UserEmail,
@@ -62,7 +62,7 @@ func (c *Client) CreateVPNUser(vpnUser *VPNUser) error {
 
 func (c *Client) GetVPNUser(vpnUser *VPNUser) (*VPNUser, error) {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "get_vpn_user_by_name",
 		"username": vpnUser.UserName,
 	}
@@ -97,7 +97,7 @@ func (c *Client) GetVPNUser(vpnUser *VPNUser) (*VPNUser, error) {
 
 func (c *Client) DeleteVPNUser(vpnUser *VPNUser) error {
 	form := map[string]string{
-		"CID":      c.CID,
+		"CID":      c.cid(),
 		"action":   "delete_vpn_user",
 		"username": vpnUser.UserName,
 	}
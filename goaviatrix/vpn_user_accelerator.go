@@ -17,7 +17,7 @@ type VpnUserXlrAPIResp struct {
 
 func (c *Client) GetVpnUserAccelerator() ([]string, error) {
 	form := map[string]string{
-		"CID":    c.CID,
+		"CID":    c.cid(),
 		"action": "list_vpn_user_xlr",
 	}
 
@@ -32,7 +32,7 @@ func (c *Client) GetVpnUserAccelerator() ([]string, error) {
 }
 
 func (c *Client) UpdateVpnUserAccelerator(xlr *VpnUserXlr) error {
-	xlr.CID = c.CID
+	xlr.CID = c.cid()
 	xlr.Action = "update_vpn_user_xlr"
 
 	return c.PostAPI(xlr.Action, xlr, BasicCheck)
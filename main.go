@@ -7,6 +7,6 @@ import (
 
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: aviatrix.Provider,
+		GRPCProviderFunc: aviatrix.NewGRPCProviderServer,
 	})
 }